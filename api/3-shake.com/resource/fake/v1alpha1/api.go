@@ -20,6 +20,37 @@ func DefaultDeviceClassParametersSpec() *DeviceClassParametersSpec {
 
 func DefaultFakeClaimParametersSpec() *FakeClaimParametersSpec {
 	return &FakeClaimParametersSpec{
-		Count: 1,
+		Count:    1,
+		Selector: &FakeSelector{},
+	}
+}
+
+// SetDefaultsDeviceClassParametersSpec fills the unset fields of spec with
+// the values of DefaultDeviceClassParametersSpec
+func SetDefaultsDeviceClassParametersSpec(spec *DeviceClassParametersSpec) {
+	defaults := DefaultDeviceClassParametersSpec()
+	if len(spec.DeviceSelector) == 0 {
+		spec.DeviceSelector = defaults.DeviceSelector
+		return
+	}
+	for i := range spec.DeviceSelector {
+		if spec.DeviceSelector[i].Type == "" {
+			spec.DeviceSelector[i].Type = defaults.DeviceSelector[0].Type
+		}
+		if spec.DeviceSelector[i].Name == "" {
+			spec.DeviceSelector[i].Name = defaults.DeviceSelector[0].Name
+		}
+	}
+}
+
+// SetDefaultsFakeClaimParametersSpec fills the unset fields of spec with the
+// values of DefaultFakeClaimParametersSpec
+func SetDefaultsFakeClaimParametersSpec(spec *FakeClaimParametersSpec) {
+	defaults := DefaultFakeClaimParametersSpec()
+	if spec.Count == 0 {
+		spec.Count = defaults.Count
+	}
+	if spec.Selector == nil {
+		spec.Selector = defaults.Selector
 	}
 }
@@ -0,0 +1,233 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+)
+
+// ConversionDataAnnotation holds the v1beta1 spec of an object served as
+// v1alpha1 when v1alpha1 cannot represent all of it, so that it can be
+// restored when the object is converted back.
+const ConversionDataAnnotation = GroupName + "/v1beta1-spec"
+
+var (
+	_ v1beta1.Convertible = &FakeClaimParameters{}
+	_ v1beta1.Convertible = &DeviceClassParameters{}
+)
+
+func (src *FakeClaimParameters) ConvertTo(dstRaw v1beta1.Hub) error {
+	dst, ok := dstRaw.(*v1beta1.FakeClaimParameters)
+	if !ok {
+		return fmt.Errorf("cannot convert %T to %T", src, dstRaw)
+	}
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	ConvertFakeClaimParametersSpecToHub(&src.Spec, &dst.Spec)
+	convertFakeClaimParametersStatusToHub(&src.Status, &dst.Status)
+
+	var restored v1beta1.FakeClaimParametersSpec
+	found, err := popConversionData(&dst.ObjectMeta, &restored)
+	if err != nil {
+		return err
+	}
+	// The saved spec is stale if a v1alpha1 client changed the object since
+	var spec FakeClaimParametersSpec
+	convertFakeClaimParametersSpecFromHub(&restored, &spec)
+	if found && apiequality.Semantic.DeepEqual(&spec, &src.Spec) {
+		dst.Spec = restored
+	}
+	return nil
+}
+
+func (dst *FakeClaimParameters) ConvertFrom(srcRaw v1beta1.Hub) error {
+	src, ok := srcRaw.(*v1beta1.FakeClaimParameters)
+	if !ok {
+		return fmt.Errorf("cannot convert %T to %T", srcRaw, dst)
+	}
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	delete(dst.Annotations, ConversionDataAnnotation)
+	convertFakeClaimParametersSpecFromHub(&src.Spec, &dst.Spec)
+	convertFakeClaimParametersStatusFromHub(&src.Status, &dst.Status)
+
+	var spec v1beta1.FakeClaimParametersSpec
+	ConvertFakeClaimParametersSpecToHub(&dst.Spec, &spec)
+	if !apiequality.Semantic.DeepEqual(&spec, &src.Spec) {
+		return pushConversionData(&dst.ObjectMeta, &src.Spec)
+	}
+	return nil
+}
+
+func (src *DeviceClassParameters) ConvertTo(dstRaw v1beta1.Hub) error {
+	dst, ok := dstRaw.(*v1beta1.DeviceClassParameters)
+	if !ok {
+		return fmt.Errorf("cannot convert %T to %T", src, dstRaw)
+	}
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	convertDeviceClassParametersSpecToHub(&src.Spec, &dst.Spec)
+
+	var restored v1beta1.DeviceClassParametersSpec
+	found, err := popConversionData(&dst.ObjectMeta, &restored)
+	if err != nil {
+		return err
+	}
+	var spec DeviceClassParametersSpec
+	convertDeviceClassParametersSpecFromHub(&restored, &spec)
+	if found && apiequality.Semantic.DeepEqual(&spec, &src.Spec) {
+		dst.Spec = restored
+	}
+	return nil
+}
+
+func (dst *DeviceClassParameters) ConvertFrom(srcRaw v1beta1.Hub) error {
+	src, ok := srcRaw.(*v1beta1.DeviceClassParameters)
+	if !ok {
+		return fmt.Errorf("cannot convert %T to %T", srcRaw, dst)
+	}
+
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	delete(dst.Annotations, ConversionDataAnnotation)
+	convertDeviceClassParametersSpecFromHub(&src.Spec, &dst.Spec)
+
+	var spec v1beta1.DeviceClassParametersSpec
+	convertDeviceClassParametersSpecToHub(&dst.Spec, &spec)
+	if !apiequality.Semantic.DeepEqual(&spec, &src.Spec) {
+		return pushConversionData(&dst.ObjectMeta, &src.Spec)
+	}
+	return nil
+}
+
+// ConvertFakeClaimParametersSpecToHub converts a v1alpha1 spec into the hub
+// version. The conversion is lossless.
+func ConvertFakeClaimParametersSpecToHub(src *FakeClaimParametersSpec, dst *v1beta1.FakeClaimParametersSpec) {
+	dst.Count = src.Count
+	dst.Split = src.Split
+	dst.Selector = nil
+	if src.Selector != nil {
+		dst.Selector = &v1beta1.FakeSelector{}
+		if src.Selector.Model != nil {
+			dst.Selector.Models = []string{*src.Selector.Model}
+		}
+	}
+}
+
+func convertFakeClaimParametersSpecFromHub(src *v1beta1.FakeClaimParametersSpec, dst *FakeClaimParametersSpec) {
+	// Named requests cannot be represented, the first one stands in for all
+	// of them and the rest are restored from the conversion data. Admin
+	// access claims request nothing and keep only their selector.
+	request := v1beta1.FakeRequest{Selector: src.Selector}
+	if requests := src.GetRequests(); len(requests) > 0 {
+		request = requests[0]
+	}
+	dst.Count = request.Count
+	dst.Split = request.Split
+	dst.Selector = nil
+	if request.Selector != nil {
+		dst.Selector = &FakeSelector{}
+		if len(request.Selector.Models) > 0 {
+			// Only a single model can be selected, narrowing the selection
+			// keeps whatever v1alpha1 clients allocate valid for the hub
+			dst.Selector.Model = ptr.To(request.Selector.Models[0])
+		}
+	}
+}
+
+func convertFakeClaimParametersStatusToHub(src *FakeClaimParametersStatus, dst *v1beta1.FakeClaimParametersStatus) {
+	dst.ObservedGeneration = src.ObservedGeneration
+	dst.Generated = nil
+	if src.Generated != nil {
+		dst.Generated = &v1beta1.GeneratedObjectReference{
+			APIGroup: src.Generated.APIGroup,
+			Kind:     src.Generated.Kind,
+			Name:     src.Generated.Name,
+			UID:      src.Generated.UID,
+		}
+	}
+	dst.Selector = src.Selector
+	dst.Conditions = copyConditions(src.Conditions)
+}
+
+func convertFakeClaimParametersStatusFromHub(src *v1beta1.FakeClaimParametersStatus, dst *FakeClaimParametersStatus) {
+	dst.ObservedGeneration = src.ObservedGeneration
+	dst.Generated = nil
+	if src.Generated != nil {
+		dst.Generated = &GeneratedObjectReference{
+			APIGroup: src.Generated.APIGroup,
+			Kind:     src.Generated.Kind,
+			Name:     src.Generated.Name,
+			UID:      src.Generated.UID,
+		}
+	}
+	dst.Selector = src.Selector
+	dst.Conditions = copyConditions(src.Conditions)
+}
+
+func convertDeviceClassParametersSpecToHub(src *DeviceClassParametersSpec, dst *v1beta1.DeviceClassParametersSpec) {
+	dst.DeviceSelector = nil
+	for _, selector := range src.DeviceSelector {
+		dst.DeviceSelector = append(dst.DeviceSelector, v1beta1.DeviceSelector{
+			Type: selector.Type,
+			Name: selector.Name,
+		})
+	}
+}
+
+func convertDeviceClassParametersSpecFromHub(src *v1beta1.DeviceClassParametersSpec, dst *DeviceClassParametersSpec) {
+	dst.DeviceSelector = nil
+	for _, selector := range src.DeviceSelector {
+		// Models cannot be represented and are kept in the annotation only
+		dst.DeviceSelector = append(dst.DeviceSelector, DeviceSelector{
+			Type: selector.Type,
+			Name: selector.Name,
+		})
+	}
+}
+
+func copyConditions(conditions []metav1.Condition) []metav1.Condition {
+	if conditions == nil {
+		return nil
+	}
+	out := make([]metav1.Condition, len(conditions))
+	for i := range conditions {
+		conditions[i].DeepCopyInto(&out[i])
+	}
+	return out
+}
+
+// pushConversionData saves spec in the annotations of an object converted
+// from the hub.
+func pushConversionData(objectMeta *metav1.ObjectMeta, spec any) error {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("error encoding conversion data: %w", err)
+	}
+	if objectMeta.Annotations == nil {
+		objectMeta.Annotations = map[string]string{}
+	}
+	objectMeta.Annotations[ConversionDataAnnotation] = string(data)
+	return nil
+}
+
+// popConversionData removes the spec saved by pushConversionData from the
+// annotations of an object converted to the hub and decodes it into spec.
+func popConversionData(objectMeta *metav1.ObjectMeta, spec any) (bool, error) {
+	data, ok := objectMeta.Annotations[ConversionDataAnnotation]
+	if !ok {
+		return false, nil
+	}
+	delete(objectMeta.Annotations, ConversionDataAnnotation)
+	if len(objectMeta.Annotations) == 0 {
+		objectMeta.Annotations = nil
+	}
+	if err := json.Unmarshal([]byte(data), spec); err != nil {
+		return false, fmt.Errorf("error decoding %s annotation: %w", ConversionDataAnnotation, err)
+	}
+	return true, nil
+}
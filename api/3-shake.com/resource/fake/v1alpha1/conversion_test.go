@@ -0,0 +1,190 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+)
+
+func TestFakeClaimParametersHubRoundTrip(t *testing.T) {
+	testCases := map[string]v1beta1.FakeClaimParametersSpec{
+		"single request": {
+			Count:    2,
+			Split:    4,
+			Selector: &v1beta1.FakeSelector{Models: []string{v1beta1.FakeModelUltra10}},
+		},
+		"whole devices of any model": {
+			Count: 1,
+		},
+		"multiple models": {
+			Count:    1,
+			Selector: &v1beta1.FakeSelector{Models: []string{v1beta1.FakeModelUltra10, v1beta1.FakeModelUltra100}},
+		},
+		"multiple requests": {
+			Requests: []v1beta1.FakeRequest{
+				{Name: "whole", Count: 1, Selector: &v1beta1.FakeSelector{Models: []string{v1beta1.FakeModelUltra10}}},
+				{Name: "split", Count: 2, Split: 2, Selector: &v1beta1.FakeSelector{Models: []string{v1beta1.FakeModelUltra100}}},
+			},
+		},
+		"driver name": {
+			Requests: []v1beta1.FakeRequest{
+				{Name: "nic", Count: 1, DriverName: "nic.example.com"},
+			},
+		},
+		"config and admin access": {
+			Selector:    &v1beta1.FakeSelector{Models: []string{v1beta1.FakeModelUltra10}},
+			Config:      &v1beta1.FakeDeviceConfig{Mode: v1beta1.FakeDeviceModeDebug},
+			AdminAccess: true,
+		},
+	}
+
+	for name, spec := range testCases {
+		t.Run(name, func(t *testing.T) {
+			hub := &v1beta1.FakeClaimParameters{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "params", Annotations: map[string]string{"keep": "me"}},
+				Spec:       spec,
+				Status:     v1beta1.FakeClaimParametersStatus{ObservedGeneration: 3},
+			}
+
+			spoke := &FakeClaimParameters{}
+			if err := spoke.ConvertFrom(hub.DeepCopy()); err != nil {
+				t.Fatalf("error converting from hub: %v", err)
+			}
+			if spoke.Status.ObservedGeneration != hub.Status.ObservedGeneration {
+				t.Errorf("expected observed generation %d, got %d", hub.Status.ObservedGeneration, spoke.Status.ObservedGeneration)
+			}
+
+			got := &v1beta1.FakeClaimParameters{}
+			if err := spoke.ConvertTo(got); err != nil {
+				t.Fatalf("error converting to hub: %v", err)
+			}
+			if !apiequality.Semantic.DeepEqual(got, hub) {
+				t.Errorf("expected %+v after a round trip, got %+v", hub, got)
+			}
+		})
+	}
+}
+
+func TestFakeClaimParametersSpokeRoundTrip(t *testing.T) {
+	testCases := map[string]FakeClaimParametersSpec{
+		"empty": {},
+		"whole devices": {
+			Count: 2,
+		},
+		"split devices of a model": {
+			Count:    1,
+			Split:    2,
+			Selector: &FakeSelector{Model: ptr.To(v1beta1.FakeModelUltra100)},
+		},
+		"selector without model": {
+			Count:    1,
+			Selector: &FakeSelector{},
+		},
+	}
+
+	for name, spec := range testCases {
+		t.Run(name, func(t *testing.T) {
+			spoke := &FakeClaimParameters{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "params"},
+				Spec:       spec,
+			}
+
+			hub := &v1beta1.FakeClaimParameters{}
+			if err := spoke.DeepCopy().ConvertTo(hub); err != nil {
+				t.Fatalf("error converting to hub: %v", err)
+			}
+			got := &FakeClaimParameters{}
+			if err := got.ConvertFrom(hub); err != nil {
+				t.Fatalf("error converting from hub: %v", err)
+			}
+			if _, ok := got.Annotations[ConversionDataAnnotation]; ok {
+				t.Errorf("expected no %s annotation for a v1alpha1 spec", ConversionDataAnnotation)
+			}
+			if !apiequality.Semantic.DeepEqual(got, spoke) {
+				t.Errorf("expected %+v after a round trip, got %+v", spoke, got)
+			}
+		})
+	}
+}
+
+// TestFakeClaimParametersStaleConversionData edits an object read as
+// v1alpha1, which leaves the spec saved in its annotation stale
+func TestFakeClaimParametersStaleConversionData(t *testing.T) {
+	hub := &v1beta1.FakeClaimParameters{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "params"},
+		Spec: v1beta1.FakeClaimParametersSpec{
+			Requests: []v1beta1.FakeRequest{
+				{Name: "first", Count: 1},
+				{Name: "second", Count: 2},
+			},
+		},
+	}
+
+	spoke := &FakeClaimParameters{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatalf("error converting from hub: %v", err)
+	}
+	if _, ok := spoke.Annotations[ConversionDataAnnotation]; !ok {
+		t.Fatalf("expected the spec to be saved in the %s annotation", ConversionDataAnnotation)
+	}
+
+	unchanged := &v1beta1.FakeClaimParameters{}
+	if err := spoke.DeepCopy().ConvertTo(unchanged); err != nil {
+		t.Fatalf("error converting to hub: %v", err)
+	}
+	if !apiequality.Semantic.DeepEqual(&unchanged.Spec, &hub.Spec) {
+		t.Errorf("expected the saved spec %+v to be restored, got %+v", hub.Spec, unchanged.Spec)
+	}
+
+	spoke.Spec.Count = 4
+	edited := &v1beta1.FakeClaimParameters{}
+	if err := spoke.ConvertTo(edited); err != nil {
+		t.Fatalf("error converting to hub: %v", err)
+	}
+	want := v1beta1.FakeClaimParametersSpec{Count: 4}
+	if !apiequality.Semantic.DeepEqual(&edited.Spec, &want) {
+		t.Errorf("expected the edited spec %+v, got %+v", want, edited.Spec)
+	}
+	if _, ok := edited.Annotations[ConversionDataAnnotation]; ok {
+		t.Errorf("expected the stale %s annotation to be dropped", ConversionDataAnnotation)
+	}
+}
+
+func TestDeviceClassParametersRoundTrip(t *testing.T) {
+	hub := &v1beta1.DeviceClassParameters{
+		ObjectMeta: metav1.ObjectMeta{Name: "class"},
+		Spec: v1beta1.DeviceClassParametersSpec{
+			DeviceSelector: []v1beta1.DeviceSelector{
+				{Type: v1beta1.FakeDeviceType, Name: "*", Models: []string{v1beta1.FakeModelUltra10, v1beta1.FakeModelUltra100}},
+				{Type: v1beta1.NICDeviceType, Name: "*"},
+			},
+		},
+	}
+
+	spoke := &DeviceClassParameters{}
+	if err := spoke.ConvertFrom(hub.DeepCopy()); err != nil {
+		t.Fatalf("error converting from hub: %v", err)
+	}
+	got := &v1beta1.DeviceClassParameters{}
+	if err := spoke.DeepCopy().ConvertTo(got); err != nil {
+		t.Fatalf("error converting to hub: %v", err)
+	}
+	if !apiequality.Semantic.DeepEqual(got, hub) {
+		t.Errorf("expected %+v after a round trip, got %+v", hub, got)
+	}
+
+	// Dropping a selector as v1alpha1 makes the saved models stale
+	spoke.Spec.DeviceSelector = spoke.Spec.DeviceSelector[1:]
+	edited := &v1beta1.DeviceClassParameters{}
+	if err := spoke.ConvertTo(edited); err != nil {
+		t.Fatalf("error converting to hub: %v", err)
+	}
+	want := []v1beta1.DeviceSelector{{Type: v1beta1.NICDeviceType, Name: "*"}}
+	if !apiequality.Semantic.DeepEqual(edited.Spec.DeviceSelector, want) {
+		t.Errorf("expected the edited selectors %+v, got %+v", want, edited.Spec.DeviceSelector)
+	}
+}
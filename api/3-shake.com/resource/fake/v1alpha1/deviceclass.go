@@ -19,6 +19,7 @@ type DeviceClassParametersSpec struct {
 // +genclient:nonNamespaced
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +k8s:openapi-gen=true
+// +kubebuilder:deprecatedversion:warning="fake.resource.3-shake.com/v1alpha1 DeviceClassParameters is deprecated, use fake.resource.3-shake.com/v1beta1"
 // +kubebuilder:resource:scope=Cluster
 //
 // DeviceClassParameters holds the set of parameters provided when creating a resource class
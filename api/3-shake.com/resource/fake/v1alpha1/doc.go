@@ -1,3 +1,6 @@
 // +k8s:deepcopy-gen=package
 // +groupName=fake.resource.3-shake.com
+// Package v1alpha1 is the deprecated first version of the
+// fake.resource.3-shake.com API. It is served alongside v1beta1 and
+// converted through it by fake-dra-webhook.
 package v1alpha1
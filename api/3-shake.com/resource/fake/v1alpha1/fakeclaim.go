@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
@@ -11,9 +12,56 @@ const (
 	UnknownDeviceType = "unknown"
 )
 
+// Models of Fake devices
+const (
+	FakeModelUltra10  = "ULTRA_10"
+	FakeModelUltra100 = "ULTRA_100"
+)
+
+// fakeModelMaxSplit is the maximum number of partitions a device of each
+// model can be split into
+var fakeModelMaxSplit = map[string]int{
+	FakeModelUltra10:  4,
+	FakeModelUltra100: 8,
+}
+
+// FakeModels returns the known Fake device models
+func FakeModels() []string {
+	return []string{FakeModelUltra10, FakeModelUltra100}
+}
+
+// MaxSplit returns the maximum number of partitions a device of the given
+// model can be split into and whether the model is known
+func MaxSplit(model string) (int, bool) {
+	split, ok := fakeModelMaxSplit[model]
+	return split, ok
+}
+
+// Condition types reported in FakeClaimParametersStatus
+const (
+	// FakeClaimParametersAccepted means the spec has been validated by the controller
+	FakeClaimParametersAccepted = "Accepted"
+	// FakeClaimParametersGenerated means the ResourceClaimParameters object is up to date with the spec
+	FakeClaimParametersGenerated = "Generated"
+	// FakeClaimParametersInvalid means the spec cannot be turned into ResourceClaimParameters
+	FakeClaimParametersInvalid = "Invalid"
+)
+
+// Condition reasons reported in FakeClaimParametersStatus
+const (
+	FakeClaimParametersReasonValidSpec           = "ValidSpec"
+	FakeClaimParametersReasonInvalidSpec         = "InvalidSpec"
+	FakeClaimParametersReasonGenerationPending   = "GenerationPending"
+	FakeClaimParametersReasonGenerationSucceeded = "GenerationSucceeded"
+	FakeClaimParametersReasonGenerationFailed    = "GenerationFailed"
+)
+
 type FakeClaimParametersSpec struct {
-	Count    int           `json:"count,omitempty"`
-	Split    int           `json:"split,omitempty"`
+	Count int `json:"count,omitempty"`
+	// Split is always serialized, so that the default of 0 shows up in
+	// stored objects
+	// +optional
+	Split    int           `json:"split"`
 	Selector *FakeSelector `json:"selector,omitempty"`
 }
 
@@ -21,26 +69,55 @@ type FakeSelector struct {
 	Model *string `json:"model,omitempty"`
 }
 
+// FakeClaimParametersStatus is the observed state of FakeClaimParameters
+type FakeClaimParametersStatus struct {
+	// ObservedGeneration is the generation of the spec the status was computed from
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Generated refers to the ResourceClaimParameters object generated from the spec
+	Generated *GeneratedObjectReference `json:"generated,omitempty"`
+	// Selector is the rendered NamedResources selector used for each request
+	Selector string `json:"selector,omitempty"`
+	// Conditions describe whether the spec was accepted and generated
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// GeneratedObjectReference identifies an object generated by the controller
+type GeneratedObjectReference struct {
+	APIGroup string    `json:"apiGroup"`
+	Kind     string    `json:"kind"`
+	Name     string    `json:"name"`
+	UID      types.UID `json:"uid,omitempty"`
+}
+
 // ToNamedResourcesSelector converts a FakeSelector into a selector for use with
 // the NamedResources structured model
 func (s FakeSelector) ToNamedResourcesSelector() string {
 	if s.Model != nil {
 		return fmt.Sprintf(`attributes.string["model"] == %q`, *s.Model)
 	}
-	return "()"
+	return "true"
 }
 
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +k8s:openapi-gen=true
+// +kubebuilder:deprecatedversion:warning="fake.resource.3-shake.com/v1alpha1 FakeClaimParameters is deprecated, use fake.resource.3-shake.com/v1beta1"
 // +kubebuilder:resource:scope=Namespaced
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Accepted",type=string,JSONPath=`.status.conditions[?(@.type=="Accepted")].status`
+// +kubebuilder:printcolumn:name="Generated",type=string,JSONPath=`.status.conditions[?(@.type=="Generated")].status`
+// +kubebuilder:printcolumn:name="Parameters",type=string,JSONPath=`.status.generated.name`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 //
 // FakeClaimParameters holds the set of parameters provided when creating a resource claim for a Fake resource
 type FakeClaimParameters struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec FakeClaimParametersSpec `json:"spec,omitempty"`
+	Spec   FakeClaimParametersSpec   `json:"spec,omitempty"`
+	Status FakeClaimParametersStatus `json:"status,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -0,0 +1,69 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateFakeClaimParametersSpec validates a FakeClaimParametersSpec
+func ValidateFakeClaimParametersSpec(spec *FakeClaimParametersSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.Count < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("count"), spec.Count, "must be greater than or equal to 0"))
+	}
+
+	var models []string
+	if spec.Selector != nil && spec.Selector.Model != nil {
+		modelPath := fldPath.Child("selector", "model")
+		switch model := *spec.Selector.Model; {
+		case model == "":
+			allErrs = append(allErrs, field.Required(modelPath, "must not be empty when set"))
+		default:
+			if _, ok := MaxSplit(model); !ok {
+				allErrs = append(allErrs, field.NotSupported(modelPath, model, FakeModels()))
+			}
+			models = []string{model}
+		}
+	} else {
+		// Any model may be allocated, so the split has to fit all of them
+		models = FakeModels()
+	}
+
+	splitPath := fldPath.Child("split")
+	if spec.Split < 0 {
+		allErrs = append(allErrs, field.Invalid(splitPath, spec.Split, "must be greater than or equal to 0"))
+	}
+	for _, model := range models {
+		if maxSplit, ok := MaxSplit(model); ok && spec.Split > maxSplit {
+			allErrs = append(allErrs, field.Invalid(splitPath, spec.Split, fmt.Sprintf("must be less than or equal to %d for model %s", maxSplit, model)))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateDeviceClassParametersSpec validates a DeviceClassParametersSpec
+func ValidateDeviceClassParametersSpec(spec *DeviceClassParametersSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	selectorPath := fldPath.Child("deviceSelector")
+	if len(spec.DeviceSelector) == 0 {
+		allErrs = append(allErrs, field.Required(selectorPath, "at least one device selector is required"))
+	}
+	for i, selector := range spec.DeviceSelector {
+		switch selector.Type {
+		case "":
+			allErrs = append(allErrs, field.Required(selectorPath.Index(i).Child("type"), ""))
+		case FakeDeviceType:
+		default:
+			allErrs = append(allErrs, field.NotSupported(selectorPath.Index(i).Child("type"), selector.Type, []string{FakeDeviceType}))
+		}
+		if selector.Name == "" {
+			allErrs = append(allErrs, field.Required(selectorPath.Index(i).Child("name"), ""))
+		}
+	}
+
+	return allErrs
+}
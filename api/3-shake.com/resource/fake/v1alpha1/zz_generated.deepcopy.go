@@ -21,6 +21,7 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -123,6 +124,7 @@ func (in *FakeClaimParameters) DeepCopyInto(out *FakeClaimParameters) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FakeClaimParameters.
@@ -195,6 +197,33 @@ func (in *FakeClaimParametersSpec) DeepCopy() *FakeClaimParametersSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FakeClaimParametersStatus) DeepCopyInto(out *FakeClaimParametersStatus) {
+	*out = *in
+	if in.Generated != nil {
+		in, out := &in.Generated, &out.Generated
+		*out = new(GeneratedObjectReference)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FakeClaimParametersStatus.
+func (in *FakeClaimParametersStatus) DeepCopy() *FakeClaimParametersStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FakeClaimParametersStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FakeSelector) DeepCopyInto(out *FakeSelector) {
 	*out = *in
@@ -214,3 +243,18 @@ func (in *FakeSelector) DeepCopy() *FakeSelector {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratedObjectReference) DeepCopyInto(out *GeneratedObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratedObjectReference.
+func (in *GeneratedObjectReference) DeepCopy() *GeneratedObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratedObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
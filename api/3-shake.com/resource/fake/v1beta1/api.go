@@ -0,0 +1,79 @@
+package v1beta1
+
+const (
+	GroupName = "fake.resource.3-shake.com"
+	Version   = "v1beta1"
+
+	FakeClaimParametersKind   = "FakeClaimParameters"
+	DeviceClassParametersKind = "DeviceClassParameters"
+	FakeRequestKind           = "FakeRequest"
+	FakeDeviceQuotaKind       = "FakeDeviceQuota"
+)
+
+func DefaultDeviceClassParametersSpec() *DeviceClassParametersSpec {
+	return &DeviceClassParametersSpec{
+		DeviceSelector: []DeviceSelector{
+			{
+				Type: FakeDeviceType,
+				Name: "*",
+			},
+		},
+	}
+}
+
+func DefaultFakeClaimParametersSpec() *FakeClaimParametersSpec {
+	return &FakeClaimParametersSpec{
+		Count:    1,
+		Selector: &FakeSelector{},
+	}
+}
+
+// SetDefaultsDeviceClassParametersSpec fills the unset fields of spec with
+// the values of DefaultDeviceClassParametersSpec
+func SetDefaultsDeviceClassParametersSpec(spec *DeviceClassParametersSpec) {
+	defaults := DefaultDeviceClassParametersSpec()
+	if len(spec.DeviceSelector) == 0 {
+		spec.DeviceSelector = defaults.DeviceSelector
+		return
+	}
+	for i := range spec.DeviceSelector {
+		if spec.DeviceSelector[i].Type == "" {
+			spec.DeviceSelector[i].Type = defaults.DeviceSelector[0].Type
+		}
+		if spec.DeviceSelector[i].Name == "" {
+			spec.DeviceSelector[i].Name = defaults.DeviceSelector[0].Name
+		}
+	}
+}
+
+// SetDefaultsFakeClaimParametersSpec fills the unset fields of spec, or of
+// each of its requests, with the values of DefaultFakeClaimParametersSpec
+func SetDefaultsFakeClaimParametersSpec(spec *FakeClaimParametersSpec) {
+	if spec.Config != nil {
+		SetDefaultsFakeDeviceConfig(spec.Config)
+	}
+	if spec.AdminAccess {
+		// Nothing is allocated, the selector only filters the visible devices
+		if spec.Selector == nil {
+			spec.Selector = DefaultFakeClaimParametersSpec().Selector
+		}
+		return
+	}
+	if len(spec.Requests) > 0 {
+		for i := range spec.Requests {
+			setDefaults(&spec.Requests[i].Count, &spec.Requests[i].Selector)
+		}
+		return
+	}
+	setDefaults(&spec.Count, &spec.Selector)
+}
+
+func setDefaults(count *int, selector **FakeSelector) {
+	defaults := DefaultFakeClaimParametersSpec()
+	if *count == 0 {
+		*count = defaults.Count
+	}
+	if *selector == nil {
+		*selector = defaults.Selector
+	}
+}
@@ -0,0 +1,86 @@
+package v1beta1
+
+// Modes the devices of a claim can be operated in
+const (
+	FakeDeviceModeCompute  = "compute"
+	FakeDeviceModeGraphics = "graphics"
+	FakeDeviceModeDebug    = "debug"
+)
+
+// Clock profiles the devices of a claim can run with
+const (
+	FakeClockProfileBase      = "base"
+	FakeClockProfileBoost     = "boost"
+	FakeClockProfilePowerSave = "power-save"
+)
+
+// ReservedEnvPrefixes are the prefixes of the environment variables set by
+// the driver itself, which FakeDeviceConfig must not override
+var ReservedEnvPrefixes = []string{"FAKE_", "DRA_"}
+
+// powerLimitRange is the range of power limits in watts a device accepts
+type powerLimitRange struct {
+	min, max int
+}
+
+// fakeModelPowerLimit is the range of power limits accepted by a device of
+// each model
+var fakeModelPowerLimit = map[string]powerLimitRange{
+	FakeModelUltra10:  {min: 100, max: 300},
+	FakeModelUltra100: {min: 200, max: 700},
+}
+
+// FakeDeviceModes returns the supported device modes
+func FakeDeviceModes() []string {
+	return []string{FakeDeviceModeCompute, FakeDeviceModeGraphics, FakeDeviceModeDebug}
+}
+
+// FakeClockProfiles returns the supported clock profiles
+func FakeClockProfiles() []string {
+	return []string{FakeClockProfileBase, FakeClockProfileBoost, FakeClockProfilePowerSave}
+}
+
+// PowerLimitRange returns the minimum and maximum power limit in watts of a
+// device of the given model and whether the model is known
+func PowerLimitRange(model string) (int, int, bool) {
+	limits, ok := fakeModelPowerLimit[model]
+	return limits.min, limits.max, ok
+}
+
+// FakeDeviceConfig configures the devices allocated for a claim and the
+// containers using them. The kubelet plugin passes it to the containers
+// through CDI, both as environment variables and as a descriptor file.
+type FakeDeviceConfig struct {
+	// Env are extra environment variables set in the containers
+	// +listType=map
+	// +listMapKey=name
+	Env []FakeEnvVar `json:"env,omitempty"`
+	// Mode is the mode the devices are operated in, one of compute, graphics
+	// or debug
+	Mode string `json:"mode,omitempty"`
+	// PowerLimitWatts caps the power draw of each device, devices run
+	// without a limit if unset. It is only supported by the models of Fake
+	// devices, which have a range of power limits.
+	PowerLimitWatts *int `json:"powerLimitWatts,omitempty"`
+	// ClockProfile is the clock profile the devices run with, one of base,
+	// boost or power-save
+	ClockProfile string `json:"clockProfile,omitempty"`
+}
+
+// FakeEnvVar is an environment variable set in the containers
+type FakeEnvVar struct {
+	// Name of the environment variable
+	Name string `json:"name"`
+	// Value of the environment variable
+	Value string `json:"value,omitempty"`
+}
+
+// SetDefaultsFakeDeviceConfig fills the unset fields of config
+func SetDefaultsFakeDeviceConfig(config *FakeDeviceConfig) {
+	if config.Mode == "" {
+		config.Mode = FakeDeviceModeCompute
+	}
+	if config.ClockProfile == "" {
+		config.ClockProfile = FakeClockProfileBase
+	}
+}
@@ -0,0 +1,26 @@
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Hub is implemented by the types of the hub version, which all other
+// versions convert to and from
+// +kubebuilder:object:generate=false
+type Hub interface {
+	runtime.Object
+	Hub()
+}
+
+// Convertible is implemented by the types of the other versions
+// +kubebuilder:object:generate=false
+type Convertible interface {
+	runtime.Object
+	// ConvertTo converts the object into the hub object dst
+	ConvertTo(dst Hub) error
+	// ConvertFrom fills the object from the hub object src
+	ConvertFrom(src Hub) error
+}
+
+func (*FakeClaimParameters) Hub()   {}
+func (*DeviceClassParameters) Hub() {}
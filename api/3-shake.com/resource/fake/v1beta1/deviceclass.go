@@ -0,0 +1,79 @@
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeviceSelector allows one to match on a specific type of Device as part of the class
+type DeviceSelector struct {
+	// Type is the type of the devices matched, one of fake, nic or
+	// accelerator
+	Type string `json:"type"`
+	// Name is the name of the devices matched, "*" matches any device
+	Name string `json:"name"`
+	// Models restricts the selector to devices of the listed models, devices
+	// of any model are matched if empty
+	// +listType=set
+	Models []string `json:"models,omitempty"`
+}
+
+// DeviceClassParametersSpec is the spec for DeviceClaimParameters CRD
+type DeviceClassParametersSpec struct {
+	// DeviceSelector lists the devices of the class, a device matching any
+	// of the selectors belongs to it
+	DeviceSelector []DeviceSelector `json:"deviceSelector"`
+}
+
+// ToNamedResourcesSelector converts a DeviceSelector into a selector for use
+// with the NamedResources structured model
+func (s DeviceSelector) ToNamedResourcesSelector() string {
+	conditions := []string{fmt.Sprintf(`attributes.string["type"] == %q`, s.Type)}
+	if s.Name != "" && s.Name != "*" {
+		conditions = append(conditions, fmt.Sprintf(`attributes.string["uuid"] == %q`, s.Name))
+	}
+	if len(s.Models) > 0 {
+		conditions = append(conditions, FakeSelector{Models: s.Models}.ToNamedResourcesSelector())
+	}
+	return strings.Join(conditions, " && ")
+}
+
+// ToNamedResourcesSelector converts the device selectors of a defaulted spec
+// into a single selector for use with the NamedResources structured model
+func (s *DeviceClassParametersSpec) ToNamedResourcesSelector() string {
+	if len(s.DeviceSelector) == 1 {
+		return s.DeviceSelector[0].ToNamedResourcesSelector()
+	}
+	selectors := make([]string, len(s.DeviceSelector))
+	for i, selector := range s.DeviceSelector {
+		selectors[i] = "(" + selector.ToNamedResourcesSelector() + ")"
+	}
+	return strings.Join(selectors, " || ")
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:storageversion
+//
+// DeviceClassParameters holds the set of parameters provided when creating a resource class
+type DeviceClassParameters struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DeviceClassParametersSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//
+// DeviceClassParametersList is a list of DeviceClassParameters resources
+type DeviceClassParametersList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []DeviceClassParameters `json:"items"`
+}
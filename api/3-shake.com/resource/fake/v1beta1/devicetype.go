@@ -0,0 +1,115 @@
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Types of devices emulated by the kubelet plugin
+const (
+	FakeDeviceType        = "fake"
+	NICDeviceType         = "nic"
+	AcceleratorDeviceType = "accelerator"
+	UnknownDeviceType     = "unknown"
+)
+
+// Models of Fake devices
+const (
+	FakeModelUltra10  = "ULTRA_10"
+	FakeModelUltra100 = "ULTRA_100"
+)
+
+// Models of NIC devices, which are split into virtual functions
+const (
+	NICModel25G  = "NIC_25G"
+	NICModel100G = "NIC_100G"
+)
+
+// Models of accelerator devices, which are split into partitions sharing
+// their memory
+const (
+	AcceleratorModel32G = "ACCEL_32G"
+	AcceleratorModel80G = "ACCEL_80G"
+)
+
+// deviceTypeModels are the models of each type of device
+var deviceTypeModels = map[string][]string{
+	FakeDeviceType:        {FakeModelUltra10, FakeModelUltra100},
+	NICDeviceType:         {NICModel25G, NICModel100G},
+	AcceleratorDeviceType: {AcceleratorModel32G, AcceleratorModel80G},
+}
+
+// fakeModelMaxSplit is the maximum number of partitions a device of each
+// model can be split into
+var fakeModelMaxSplit = map[string]int{
+	FakeModelUltra10:    4,
+	FakeModelUltra100:   8,
+	NICModel25G:         8,
+	NICModel100G:        32,
+	AcceleratorModel32G: 4,
+	AcceleratorModel80G: 8,
+}
+
+// nicModelSpeedGbps is the link speed of a NIC of each model
+var nicModelSpeedGbps = map[string]int64{
+	NICModel25G:  25,
+	NICModel100G: 100,
+}
+
+// acceleratorModelMemory is the memory of an accelerator of each model
+var acceleratorModelMemory = map[string]resource.Quantity{
+	AcceleratorModel32G: resource.MustParse("32Gi"),
+	AcceleratorModel80G: resource.MustParse("80Gi"),
+}
+
+// DeviceTypes returns the types of devices emulated by the kubelet plugin
+func DeviceTypes() []string {
+	return []string{FakeDeviceType, NICDeviceType, AcceleratorDeviceType}
+}
+
+// DeviceTypeModels returns the models of the given type of device
+func DeviceTypeModels(deviceType string) []string {
+	return deviceTypeModels[deviceType]
+}
+
+// ModelDeviceType returns the type of the devices of the given model, or
+// UnknownDeviceType
+func ModelDeviceType(model string) string {
+	for deviceType, models := range deviceTypeModels {
+		for _, m := range models {
+			if m == model {
+				return deviceType
+			}
+		}
+	}
+	return UnknownDeviceType
+}
+
+// FakeModels returns the known device models of all types
+func FakeModels() []string {
+	var models []string
+	for _, deviceType := range DeviceTypes() {
+		models = append(models, deviceTypeModels[deviceType]...)
+	}
+	return models
+}
+
+// MaxSplit returns the maximum number of partitions a device of the given
+// model can be split into and whether the model is known
+func MaxSplit(model string) (int, bool) {
+	split, ok := fakeModelMaxSplit[model]
+	return split, ok
+}
+
+// NICSpeedGbps returns the link speed of a NIC of the given model and
+// whether the model is known
+func NICSpeedGbps(model string) (int64, bool) {
+	speed, ok := nicModelSpeedGbps[model]
+	return speed, ok
+}
+
+// AcceleratorMemory returns the memory of an accelerator of the given model
+// and whether the model is known
+func AcceleratorMemory(model string) (resource.Quantity, bool) {
+	memory, ok := acceleratorModelMemory[model]
+	return memory, ok
+}
@@ -0,0 +1,23 @@
+// +k8s:deepcopy-gen=package
+// +groupName=fake.resource.3-shake.com
+// Package v1beta1 is the hub version of the fake.resource.3-shake.com API.
+// Objects are stored in this version and every other version is converted
+// through it by the conversion webhook of fake-dra-webhook.
+//
+// Objects written while v1alpha1 was the storage version stay encoded as
+// v1alpha1 in etcd until they are written again. They are still served in
+// both versions as long as the conversion webhook is running, but v1alpha1
+// can only be removed from the CRDs once it is gone from their
+// status.storedVersions. To migrate the storage version:
+//
+//  1. Upgrade the chart with the webhook enabled. The CRDs of this version
+//     declare their conversion through the Service of fake-dra-webhook, with
+//     the CA bundle injected by cert-manager or set from webhook.tls.caBundle.
+//     CRDs installed from crds/ by an earlier release are not owned by the
+//     release yet and have to be labeled and annotated for Helm to adopt them.
+//  2. Rewrite every object so that it is stored as v1beta1, for example with
+//     `kubectl get fakeclaimparameters,deviceclassparameters -A -o json | kubectl replace -f -`
+//     or a StorageVersionMigration.
+//  3. Drop v1alpha1 from status.storedVersions of both CRDs through their
+//     status subresource.
+package v1beta1
@@ -0,0 +1,210 @@
+package v1beta1
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Condition types reported in FakeClaimParametersStatus
+const (
+	// FakeClaimParametersAccepted means the spec has been validated by the controller
+	FakeClaimParametersAccepted = "Accepted"
+	// FakeClaimParametersGenerated means the ResourceClaimParameters object is up to date with the spec
+	FakeClaimParametersGenerated = "Generated"
+	// FakeClaimParametersInvalid means the spec cannot be turned into ResourceClaimParameters
+	FakeClaimParametersInvalid = "Invalid"
+)
+
+// Condition reasons reported in FakeClaimParametersStatus
+const (
+	FakeClaimParametersReasonValidSpec           = "ValidSpec"
+	FakeClaimParametersReasonInvalidSpec         = "InvalidSpec"
+	FakeClaimParametersReasonGenerationPending   = "GenerationPending"
+	FakeClaimParametersReasonGenerationSucceeded = "GenerationSucceeded"
+	FakeClaimParametersReasonGenerationFailed    = "GenerationFailed"
+	FakeClaimParametersReasonAdminAccessDenied   = "AdminAccessDenied"
+	FakeClaimParametersReasonUnknownDriver       = "UnknownDriver"
+)
+
+// DefaultRequestName is the name of the request described by the Count,
+// Split and Selector fields of a FakeClaimParametersSpec without Requests
+const DefaultRequestName = "default"
+
+type FakeClaimParametersSpec struct {
+	// Count is the number of devices to allocate
+	Count int `json:"count,omitempty"`
+	// Split is the number of partitions each allocated device is split into,
+	// 0 allocates whole devices. 1 hands out each device as a single
+	// partition, with a UUID of its own and its parent. It is always
+	// serialized, so that the default of 0 shows up in stored objects.
+	// +optional
+	Split int `json:"split"`
+	// Selector restricts the devices that may be allocated
+	Selector *FakeSelector `json:"selector,omitempty"`
+	// Requests are allocated together in a single claim, each with its own
+	// count, split and selector. Count, Split and Selector must not be set
+	// together with Requests.
+	// +listType=map
+	// +listMapKey=name
+	Requests []FakeRequest `json:"requests,omitempty"`
+	// Config is passed to the containers using the allocated devices
+	Config *FakeDeviceConfig `json:"config,omitempty"`
+	// AdminAccess gives the containers read-only visibility into every
+	// device on the node, or those matching Selector, without allocating
+	// any of them. Count, Split, Requests and Config must not be set
+	// together with AdminAccess, which is only allowed in the namespaces
+	// the controller is configured to accept it in.
+	AdminAccess bool `json:"adminAccess,omitempty"`
+}
+
+// FakeRequest is a named request for a number of identical devices
+type FakeRequest struct {
+	// Name identifies the request within the spec
+	Name string `json:"name"`
+	// Count is the number of devices to allocate
+	Count int `json:"count,omitempty"`
+	// Split is the number of partitions each allocated device is split into,
+	// 0 allocates whole devices. 1 hands out each device as a single
+	// partition, with a UUID of its own and its parent. It is always
+	// serialized, so that the default of 0 shows up in stored objects.
+	// +optional
+	Split int `json:"split"`
+	// Selector restricts the devices that may be allocated
+	Selector *FakeSelector `json:"selector,omitempty"`
+	// DriverName is the driver the devices are allocated from, which lets a
+	// single claim span several drivers. The default driver of the
+	// controller is used if empty.
+	DriverName string `json:"driverName,omitempty"`
+}
+
+// GetRequests returns the requests of the spec, which is a single request
+// called DefaultRequestName built from Count, Split and Selector if Requests
+// is empty. Admin access claims request no devices.
+func (s *FakeClaimParametersSpec) GetRequests() []FakeRequest {
+	if s.AdminAccess {
+		return nil
+	}
+	if len(s.Requests) > 0 {
+		return s.Requests
+	}
+	return []FakeRequest{
+		{
+			Name:     DefaultRequestName,
+			Count:    s.Count,
+			Split:    s.Split,
+			Selector: s.Selector,
+		},
+	}
+}
+
+type FakeSelector struct {
+	// Models are the models that may be allocated, devices of any model may
+	// be allocated if empty
+	// +listType=set
+	Models []string `json:"models,omitempty"`
+}
+
+// FakeClaimParametersStatus is the observed state of FakeClaimParameters
+type FakeClaimParametersStatus struct {
+	// ObservedGeneration is the generation of the spec the status was computed from
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Generated refers to the ResourceClaimParameters object generated from the spec
+	Generated *GeneratedObjectReference `json:"generated,omitempty"`
+	// Selector is the rendered NamedResources selector used for each device
+	// of a spec without requests
+	Selector string `json:"selector,omitempty"`
+	// Requests report the rendered NamedResources selector of each request
+	// +listType=map
+	// +listMapKey=name
+	Requests []FakeRequestStatus `json:"requests,omitempty"`
+	// Conditions describe whether the spec was accepted and generated
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// FakeRequestStatus is the observed state of a request
+type FakeRequestStatus struct {
+	// Name of the request
+	Name string `json:"name"`
+	// Selector is the rendered NamedResources selector used for each device
+	// of the request
+	Selector string `json:"selector"`
+}
+
+// GeneratedObjectReference identifies an object generated by the controller
+type GeneratedObjectReference struct {
+	APIGroup string    `json:"apiGroup"`
+	Kind     string    `json:"kind"`
+	Name     string    `json:"name"`
+	UID      types.UID `json:"uid,omitempty"`
+}
+
+// ToNamedResourcesSelector converts a FakeSelector into a selector for use with
+// the NamedResources structured model
+func (s FakeSelector) ToNamedResourcesSelector() string {
+	switch len(s.Models) {
+	case 0:
+		return "true"
+	case 1:
+		return fmt.Sprintf(`attributes.string["model"] == %q`, s.Models[0])
+	default:
+		models := make([]string, len(s.Models))
+		for i, model := range s.Models {
+			models[i] = fmt.Sprintf("%q", model)
+		}
+		return fmt.Sprintf(`attributes.string["model"] in [%s]`, strings.Join(models, ", "))
+	}
+}
+
+// VendorClaimParameters is how a FakeClaimParametersSpec is passed to the
+// kubelet plugin in the vendor parameters of ResourceClaimParameters. The
+// TypeMeta tells the plugin which version the spec has to be decoded as.
+type VendorClaimParameters struct {
+	metav1.TypeMeta `json:",inline"`
+
+	Spec FakeClaimParametersSpec `json:"spec"`
+}
+
+// VendorRequestParameters is passed to the kubelet plugin in the vendor
+// parameters of each generated ResourceRequest, so that it can tell which
+// request of the FakeClaimParametersSpec an allocated device belongs to.
+type VendorRequestParameters struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Request is the name of the request in the FakeClaimParametersSpec
+	Request string `json:"request"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:scope=Namespaced
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Accepted",type=string,JSONPath=`.status.conditions[?(@.type=="Accepted")].status`
+// +kubebuilder:printcolumn:name="Generated",type=string,JSONPath=`.status.conditions[?(@.type=="Generated")].status`
+// +kubebuilder:printcolumn:name="Parameters",type=string,JSONPath=`.status.generated.name`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//
+// FakeClaimParameters holds the set of parameters provided when creating a resource claim for a Fake resource
+type FakeClaimParameters struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FakeClaimParametersSpec   `json:"spec,omitempty"`
+	Status FakeClaimParametersStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//
+// FakeClaimParametersList is a list of FakeClaimParameters resources
+type FakeClaimParametersList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []FakeClaimParameters `json:"items"`
+}
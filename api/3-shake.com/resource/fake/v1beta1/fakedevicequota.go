@@ -0,0 +1,96 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FakeDeviceQuotaSpec limits the Fake devices the claims of a namespace may
+// hold. Unset limits are not enforced.
+type FakeDeviceQuotaSpec struct {
+	// Devices is the maximum number of devices, whole or split
+	Devices *int `json:"devices,omitempty"`
+	// Partitions is the maximum number of partitions, a device that is not
+	// split counting as a single partition
+	Partitions *int `json:"partitions,omitempty"`
+	// Models is an allowlist of the models that may be requested, it does
+	// not limit the number of devices of each model. Every request has to
+	// select models among them, any model may be requested if empty.
+	// +listType=set
+	Models []string `json:"models,omitempty"`
+}
+
+// FakeDeviceQuotaStatus is the observed usage of a namespace
+type FakeDeviceQuotaStatus struct {
+	// ObservedGeneration is the generation of the spec the status refers to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Used is the usage of the allocated claims of the namespace
+	Used FakeDeviceUsage `json:"used,omitempty"`
+	// Claims is the number of allocated claims in the usage
+	Claims int `json:"claims,omitempty"`
+}
+
+// FakeDeviceUsage is an amount of Fake devices
+type FakeDeviceUsage struct {
+	// Devices is the number of devices, whole or split
+	Devices int `json:"devices"`
+	// Partitions is the number of partitions, a device that is not split
+	// counting as a single partition
+	Partitions int `json:"partitions"`
+	// Models is the number of devices of each model
+	Models map[string]int `json:"models,omitempty"`
+}
+
+// Add adds other to u
+func (u *FakeDeviceUsage) Add(other *FakeDeviceUsage) {
+	u.Devices += other.Devices
+	u.Partitions += other.Partitions
+	for model, devices := range other.Models {
+		if u.Models == nil {
+			u.Models = map[string]int{}
+		}
+		u.Models[model] += devices
+	}
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:scope=Namespaced
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Devices",type=integer,JSONPath=`.status.used.devices`
+// +kubebuilder:printcolumn:name="Max Devices",type=integer,JSONPath=`.spec.devices`
+// +kubebuilder:printcolumn:name="Partitions",type=integer,JSONPath=`.status.used.partitions`
+// +kubebuilder:printcolumn:name="Max Partitions",type=integer,JSONPath=`.spec.partitions`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//
+// FakeDeviceQuota limits the Fake devices the claims of its namespace may hold
+type FakeDeviceQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FakeDeviceQuotaSpec   `json:"spec,omitempty"`
+	Status FakeDeviceQuotaStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+//
+// FakeDeviceQuotaList is a list of FakeDeviceQuota resources
+type FakeDeviceQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []FakeDeviceQuota `json:"items"`
+}
+
+// RequestedUsage returns the devices and partitions requested by a defaulted
+// spec. The models are only known once the devices are allocated and are
+// left empty.
+func RequestedUsage(spec *FakeClaimParametersSpec) *FakeDeviceUsage {
+	usage := &FakeDeviceUsage{}
+	for _, request := range spec.GetRequests() {
+		usage.Devices += request.Count
+		usage.Partitions += request.Count * max(request.Split, 1)
+	}
+	return usage
+}
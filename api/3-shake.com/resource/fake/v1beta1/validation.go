@@ -0,0 +1,253 @@
+package v1beta1
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateFakeClaimParametersSpec validates a FakeClaimParametersSpec
+func ValidateFakeClaimParametersSpec(spec *FakeClaimParametersSpec, fldPath *field.Path) field.ErrorList {
+	if spec.AdminAccess {
+		return validateAdminAccess(spec, fldPath)
+	}
+
+	var allErrs field.ErrorList
+	if spec.Config != nil {
+		allErrs = append(allErrs, ValidateFakeDeviceConfig(spec.Config, selectableModels(spec), fldPath.Child("config"))...)
+		if spec.Config.Mode == FakeDeviceModeDebug {
+			for _, request := range spec.GetRequests() {
+				if request.Split > 0 {
+					allErrs = append(allErrs, field.Invalid(fldPath.Child("config", "mode"), spec.Config.Mode, fmt.Sprintf("requires whole devices, request %s splits them", request.Name)))
+				}
+			}
+		}
+	}
+
+	if len(spec.Requests) == 0 {
+		return append(allErrs, validateRequest(spec.Count, spec.Split, spec.Selector, fldPath)...)
+	}
+
+	if spec.Count != 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("count"), "must not be set together with requests"))
+	}
+	if spec.Split != 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("split"), "must not be set together with requests"))
+	}
+	if spec.Selector != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("selector"), "must not be set together with requests"))
+	}
+
+	names := sets.New[string]()
+	for i, request := range spec.Requests {
+		requestPath := fldPath.Child("requests").Index(i)
+		namePath := requestPath.Child("name")
+		switch {
+		case request.Name == "":
+			allErrs = append(allErrs, field.Required(namePath, ""))
+		case names.Has(request.Name):
+			allErrs = append(allErrs, field.Duplicate(namePath, request.Name))
+		default:
+			for _, msg := range validation.IsDNS1123Label(request.Name) {
+				allErrs = append(allErrs, field.Invalid(namePath, request.Name, msg))
+			}
+		}
+		names.Insert(request.Name)
+		if request.DriverName != "" {
+			for _, msg := range validation.IsDNS1123Subdomain(request.DriverName) {
+				allErrs = append(allErrs, field.Invalid(requestPath.Child("driverName"), request.DriverName, msg))
+			}
+		}
+		allErrs = append(allErrs, validateRequest(request.Count, request.Split, request.Selector, requestPath)...)
+	}
+
+	return allErrs
+}
+
+func validateRequest(count, split int, selector *FakeSelector, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if count < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("count"), count, "must be greater than or equal to 0"))
+	}
+
+	var models []string
+	if selector != nil && len(selector.Models) > 0 {
+		allErrs = append(allErrs, validateModels(selector.Models, fldPath.Child("selector", "models"))...)
+		models = selector.Models
+	} else {
+		// Any model may be allocated, so the split has to fit all of them
+		models = FakeModels()
+	}
+
+	splitPath := fldPath.Child("split")
+	if split < 0 {
+		allErrs = append(allErrs, field.Invalid(splitPath, split, "must be greater than or equal to 0"))
+	}
+	for _, model := range models {
+		if maxSplit, ok := MaxSplit(model); ok && split > maxSplit {
+			allErrs = append(allErrs, field.Invalid(splitPath, split, fmt.Sprintf("must be less than or equal to %d for model %s", maxSplit, model)))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateFakeDeviceQuotaSpec validates a FakeDeviceQuotaSpec
+func ValidateFakeDeviceQuotaSpec(spec *FakeDeviceQuotaSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.Devices != nil && *spec.Devices < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("devices"), *spec.Devices, "must be greater than or equal to 0"))
+	}
+	if spec.Partitions != nil && *spec.Partitions < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("partitions"), *spec.Partitions, "must be greater than or equal to 0"))
+	}
+	allErrs = append(allErrs, validateModels(spec.Models, fldPath.Child("models"))...)
+
+	return allErrs
+}
+
+// validateAdminAccess validates a spec requesting admin access, which only
+// accepts a selector filtering the visible devices
+func validateAdminAccess(spec *FakeClaimParametersSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.Count != 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("count"), "must not be set together with adminAccess"))
+	}
+	if spec.Split != 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("split"), "must not be set together with adminAccess"))
+	}
+	if len(spec.Requests) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("requests"), "must not be set together with adminAccess"))
+	}
+	if spec.Config != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("config"), "must not be set together with adminAccess"))
+	}
+	if spec.Selector != nil {
+		allErrs = append(allErrs, validateModels(spec.Selector.Models, fldPath.Child("selector", "models"))...)
+	}
+
+	return allErrs
+}
+
+// ValidateFakeDeviceConfig validates a FakeDeviceConfig applied to devices of
+// the given models
+func ValidateFakeDeviceConfig(config *FakeDeviceConfig, models []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := sets.New[string]()
+	for i, env := range config.Env {
+		namePath := fldPath.Child("env").Index(i).Child("name")
+		switch {
+		case env.Name == "":
+			allErrs = append(allErrs, field.Required(namePath, ""))
+		case names.Has(env.Name):
+			allErrs = append(allErrs, field.Duplicate(namePath, env.Name))
+		default:
+			for _, msg := range validation.IsEnvVarName(env.Name) {
+				allErrs = append(allErrs, field.Invalid(namePath, env.Name, msg))
+			}
+			for _, prefix := range ReservedEnvPrefixes {
+				if strings.HasPrefix(env.Name, prefix) {
+					allErrs = append(allErrs, field.Invalid(namePath, env.Name, fmt.Sprintf("must not start with %s, which is reserved for the driver", prefix)))
+				}
+			}
+		}
+		names.Insert(env.Name)
+	}
+
+	if config.Mode != "" && !slices.Contains(FakeDeviceModes(), config.Mode) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("mode"), config.Mode, FakeDeviceModes()))
+	}
+	if config.ClockProfile != "" && !slices.Contains(FakeClockProfiles(), config.ClockProfile) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("clockProfile"), config.ClockProfile, FakeClockProfiles()))
+	}
+
+	if config.PowerLimitWatts != nil {
+		powerLimit := *config.PowerLimitWatts
+		powerLimitPath := fldPath.Child("powerLimitWatts")
+		for _, model := range models {
+			minLimit, maxLimit, ok := PowerLimitRange(model)
+			switch {
+			case !ok && ModelDeviceType(model) != UnknownDeviceType:
+				// The power draw of NICs and accelerators cannot be capped
+				allErrs = append(allErrs, field.Forbidden(powerLimitPath, fmt.Sprintf("is not supported by model %s, select models which have a power limit", model)))
+			case ok && (powerLimit < minLimit || powerLimit > maxLimit):
+				allErrs = append(allErrs, field.Invalid(powerLimitPath, powerLimit, fmt.Sprintf("must be between %d and %d for model %s", minLimit, maxLimit, model)))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// selectableModels returns the models that may be allocated for any of the
+// requests of spec
+func selectableModels(spec *FakeClaimParametersSpec) []string {
+	models := sets.New[string]()
+	for _, request := range spec.GetRequests() {
+		if request.Selector == nil || len(request.Selector.Models) == 0 {
+			return FakeModels()
+		}
+		models.Insert(request.Selector.Models...)
+	}
+	return sets.List(models)
+}
+
+// ValidateDeviceClassParametersSpec validates a DeviceClassParametersSpec
+func ValidateDeviceClassParametersSpec(spec *DeviceClassParametersSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	selectorPath := fldPath.Child("deviceSelector")
+	if len(spec.DeviceSelector) == 0 {
+		allErrs = append(allErrs, field.Required(selectorPath, "at least one device selector is required"))
+	}
+	for i, selector := range spec.DeviceSelector {
+		typePath := selectorPath.Index(i).Child("type")
+		switch {
+		case selector.Type == "":
+			allErrs = append(allErrs, field.Required(typePath, ""))
+		case !slices.Contains(DeviceTypes(), selector.Type):
+			allErrs = append(allErrs, field.NotSupported(typePath, selector.Type, DeviceTypes()))
+		}
+		if selector.Name == "" {
+			allErrs = append(allErrs, field.Required(selectorPath.Index(i).Child("name"), ""))
+		}
+		modelsPath := selectorPath.Index(i).Child("models")
+		allErrs = append(allErrs, validateModels(selector.Models, modelsPath)...)
+		for j, model := range selector.Models {
+			if deviceType := ModelDeviceType(model); deviceType != UnknownDeviceType && selector.Type != "" && deviceType != selector.Type {
+				allErrs = append(allErrs, field.Invalid(modelsPath.Index(j), model, fmt.Sprintf("is a model of %s devices, not %s", deviceType, selector.Type)))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateModels(models []string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	seen := sets.New[string]()
+	for i, model := range models {
+		switch {
+		case model == "":
+			allErrs = append(allErrs, field.Required(fldPath.Index(i), "must not be empty"))
+		case seen.Has(model):
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), model))
+		default:
+			if _, ok := MaxSplit(model); !ok {
+				allErrs = append(allErrs, field.NotSupported(fldPath.Index(i), model, FakeModels()))
+			}
+		}
+		seen.Insert(model)
+	}
+
+	return allErrs
+}
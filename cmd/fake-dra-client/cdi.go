@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	cdiapi "github.com/container-orchestrated-devices/container-device-interface/pkg/cdi"
+	cdispec "github.com/container-orchestrated-devices/container-device-interface/specs-go"
+	"sigs.k8s.io/yaml"
+)
+
+// ResolvedContainerEdits are the container edits of a set of CDI devices,
+// made of the edits of each device and of the spec files defining them
+type ResolvedContainerEdits struct {
+	cdispec.ContainerEdits
+	// UnresolvedDevices are the CDI devices not found in the CDI spec files
+	UnresolvedDevices []string `json:"unresolvedDevices,omitempty"`
+}
+
+// CDIResolver resolves CDI devices from the CDI spec files on the node
+type CDIResolver struct {
+	cache *cdiapi.Cache
+}
+
+// NewCDIResolver scans the CDI spec files in a directory. It has to be
+// created after the kubelet plugin prepared the claims, since the spec files
+// of the claims are only created then.
+func NewCDIResolver(cdiRoot string) (*CDIResolver, error) {
+	cache, err := cdiapi.NewCache(
+		cdiapi.WithSpecDirs(cdiRoot),
+		cdiapi.WithAutoRefresh(false),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error scanning CDI spec files in %s: %w", cdiRoot, err)
+	}
+	return &CDIResolver{cache: cache}, nil
+}
+
+// Resolve merges the container edits of CDI devices. The edits of a spec file
+// shared by several of the devices are only applied once, as the container
+// runtime does.
+func (r *CDIResolver) Resolve(devices []string) *ResolvedContainerEdits {
+	resolved := &ResolvedContainerEdits{}
+	edits := &cdiapi.ContainerEdits{ContainerEdits: &resolved.ContainerEdits}
+	specs := map[*cdiapi.Spec]bool{}
+
+	for _, name := range devices {
+		device := r.cache.GetDevice(name)
+		if device == nil {
+			resolved.UnresolvedDevices = append(resolved.UnresolvedDevices, name)
+			continue
+		}
+		if spec := device.GetSpec(); !specs[spec] {
+			specs[spec] = true
+			edits.Append(&cdiapi.ContainerEdits{ContainerEdits: &spec.ContainerEdits})
+		}
+		edits.Append(&cdiapi.ContainerEdits{ContainerEdits: &device.ContainerEdits})
+	}
+
+	return resolved
+}
+
+// printResult prints a result in the output format
+func printResult(w io.Writer, output string, result interface{}) error {
+	var data []byte
+	var err error
+	switch output {
+	case "json":
+		data, err = json.MarshalIndent(result, "", "  ")
+		data = append(data, '\n')
+	default:
+		data, err = yaml.Marshal(result)
+		data = append([]byte("---\n"), data...)
+	}
+	if err != nil {
+		return fmt.Errorf("error marshalling result: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
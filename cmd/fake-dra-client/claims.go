@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	drapbv1 "k8s.io/kubelet/pkg/apis/dra/v1alpha3"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	"github.com/toVersus/fake-dra-driver/pkg/vendorparameters"
+)
+
+// ClaimFlags describe the claims passed to the kubelet plugin, either as a
+// single claim built from the flags or as allocated ResourceClaims read from
+// files
+type ClaimFlags struct {
+	files []string
+
+	uid        *string
+	name       *string
+	namespace  *string
+	nodeName   *string
+	devices    *[]string
+	parameters *string
+}
+
+// AddFlags adds the flags describing the claims to a flag set
+func (f *ClaimFlags) AddFlags(fs *pflag.FlagSet) {
+	fs.StringArrayVarP(&f.files, "filename", "f", nil, "YAML or JSON files with allocated ResourceClaims, as printed by 'kubectl get resourceclaims -o yaml', or - for stdin. The structured resource handles of the driver are passed as they are. Can be repeated.")
+	f.uid = fs.String("claim-uid", "", "UID of the claim built from the flags. Defaults to a random UID, which has to be passed again to unprepare the claim.")
+	f.name = fs.String("claim-name", "fake-dra-client", "Name of the claim built from the flags.")
+	f.namespace = fs.String("claim-namespace", "default", "Namespace of the claim built from the flags.")
+	f.nodeName = fs.String("node-name", "", "Name of the node the claim built from the flags is allocated on. Defaults to the hostname.")
+	f.devices = fs.StringSlice("devices", nil, "Comma separated devices allocated to the claim built from the flags, as <name>[:<request>] where the request defaults to \""+fakecrd.DefaultRequestName+"\".")
+	f.parameters = fs.String("parameters", "", "YAML or JSON file with the FakeClaimParameters the claim built from the flags was allocated for. Defaults to parameters with no fields set.")
+}
+
+// Claims returns the claims for the driver described by the flags
+func (f *ClaimFlags) Claims(driverName string) ([]*drapbv1.Claim, error) {
+	if len(f.files) == 0 {
+		claim, err := f.claimFromFlags(driverName)
+		if err != nil {
+			return nil, err
+		}
+		return []*drapbv1.Claim{claim}, nil
+	}
+
+	var claims []*drapbv1.Claim
+	for _, file := range f.files {
+		resourceClaims, err := readResourceClaims(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading ResourceClaims from %s: %w", file, err)
+		}
+		for _, resourceClaim := range resourceClaims {
+			claim, err := claimFromResourceClaim(resourceClaim, driverName)
+			if err != nil {
+				return nil, fmt.Errorf("error building claim from ResourceClaim %s/%s: %w", resourceClaim.Namespace, resourceClaim.Name, err)
+			}
+			claims = append(claims, claim)
+		}
+	}
+	return claims, nil
+}
+
+// claimFromFlags builds a claim with a structured resource handle carrying
+// the vendor parameters the controller would have generated
+func (f *ClaimFlags) claimFromFlags(driverName string) (*drapbv1.Claim, error) {
+	spec := &fakecrd.FakeClaimParametersSpec{}
+	if *f.parameters != "" {
+		params, err := readClaimParameters(*f.parameters)
+		if err != nil {
+			return nil, fmt.Errorf("error reading FakeClaimParameters from %s: %w", *f.parameters, err)
+		}
+		spec = &params.Spec
+	}
+	fakecrd.SetDefaultsFakeClaimParametersSpec(spec)
+
+	vendorClaimParameters, err := vendorparameters.EncodeClaimParameters(spec)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding vendor claim parameters: %w", err)
+	}
+
+	nodeName := *f.nodeName
+	if nodeName == "" {
+		if nodeName, err = os.Hostname(); err != nil {
+			return nil, fmt.Errorf("error getting hostname: %w", err)
+		}
+	}
+
+	handle := &resourceapi.StructuredResourceHandle{
+		VendorClaimParameters: runtime.RawExtension{Raw: vendorClaimParameters},
+		NodeName:              nodeName,
+	}
+	for _, device := range *f.devices {
+		name, request, _ := strings.Cut(device, ":")
+		if name == "" {
+			return nil, fmt.Errorf("invalid device %q: name must not be empty", device)
+		}
+		if request == "" {
+			request = fakecrd.DefaultRequestName
+		}
+		vendorRequestParameters, err := vendorparameters.EncodeRequestParameters(request)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding vendor request parameters: %w", err)
+		}
+		handle.Results = append(handle.Results, resourceapi.DriverAllocationResult{
+			VendorRequestParameters: runtime.RawExtension{Raw: vendorRequestParameters},
+			AllocationResultModel: resourceapi.AllocationResultModel{
+				NamedResources: &resourceapi.NamedResourcesAllocationResult{Name: name},
+			},
+		})
+	}
+
+	uid := *f.uid
+	if uid == "" {
+		uid = string(uuid.NewUUID())
+	}
+
+	return &drapbv1.Claim{
+		Namespace:                *f.namespace,
+		Uid:                      uid,
+		Name:                     *f.name,
+		StructuredResourceHandle: []*resourceapi.StructuredResourceHandle{handle},
+	}, nil
+}
+
+// claimFromResourceClaim builds a claim from the resource handle of the
+// driver in the allocation of a ResourceClaim, the same way kubelet does
+func claimFromResourceClaim(resourceClaim *resourceapi.ResourceClaim, driverName string) (*drapbv1.Claim, error) {
+	if resourceClaim.Status.Allocation == nil {
+		return nil, errors.New("claim is not allocated")
+	}
+
+	for _, handle := range resourceClaim.Status.Allocation.ResourceHandles {
+		driver := handle.DriverName
+		if driver == "" {
+			driver = resourceClaim.Status.DriverName
+		}
+		if driver != driverName {
+			continue
+		}
+		claim := &drapbv1.Claim{
+			Namespace:      resourceClaim.Namespace,
+			Uid:            string(resourceClaim.UID),
+			Name:           resourceClaim.Name,
+			ResourceHandle: handle.Data,
+		}
+		if handle.StructuredData != nil {
+			claim.StructuredResourceHandle = []*resourceapi.StructuredResourceHandle{handle.StructuredData}
+		}
+		return claim, nil
+	}
+	return nil, fmt.Errorf("no resource handle of driver %s", driverName)
+}
+
+// readResourceClaims reads ResourceClaims and Lists of them from a file with
+// one or more YAML or JSON documents
+func readResourceClaims(file string) ([]*resourceapi.ResourceClaim, error) {
+	var claims []*resourceapi.ResourceClaim
+	err := decodeDocuments(file, func(raw []byte) error {
+		var typeMeta metav1.TypeMeta
+		if err := utilyaml.Unmarshal(raw, &typeMeta); err != nil {
+			return err
+		}
+		switch typeMeta.Kind {
+		case "List", "ResourceClaimList":
+			var list struct {
+				Items []*resourceapi.ResourceClaim `json:"items"`
+			}
+			if err := utilyaml.Unmarshal(raw, &list); err != nil {
+				return err
+			}
+			claims = append(claims, list.Items...)
+		case "ResourceClaim":
+			var claim resourceapi.ResourceClaim
+			if err := utilyaml.Unmarshal(raw, &claim); err != nil {
+				return err
+			}
+			claims = append(claims, &claim)
+		default:
+			return fmt.Errorf("unsupported kind %q", typeMeta.Kind)
+		}
+		return nil
+	})
+	return claims, err
+}
+
+// readClaimParameters reads the FakeClaimParameters in a file
+func readClaimParameters(file string) (*fakecrd.FakeClaimParameters, error) {
+	var params *fakecrd.FakeClaimParameters
+	err := decodeDocuments(file, func(raw []byte) error {
+		if params != nil {
+			return errors.New("file must contain a single document")
+		}
+		params = &fakecrd.FakeClaimParameters{}
+		if err := utilyaml.Unmarshal(raw, params); err != nil {
+			return err
+		}
+		if params.APIVersion != fakecrd.SchemeGroupVersion.String() || params.Kind != fakecrd.FakeClaimParametersKind {
+			return fmt.Errorf("unsupported %s, Kind=%s: must be %s, Kind=%s", params.APIVersion, params.Kind, fakecrd.SchemeGroupVersion, fakecrd.FakeClaimParametersKind)
+		}
+		return nil
+	})
+	if err == nil && params == nil {
+		err = errors.New("file is empty")
+	}
+	return params, err
+}
+
+// decodeDocuments calls fn with every YAML or JSON document of a file, which
+// is read from stdin if it is -
+func decodeDocuments(file string, fn func(raw []byte) error) error {
+	var r io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	for {
+		raw, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(strings.TrimSpace(string(raw))) == 0 {
+			continue
+		}
+		if err := fn(raw); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/klog/v2"
+	drapbv1 "k8s.io/kubelet/pkg/apis/dra/v1alpha3"
+)
+
+// ClaimResult is what the kubelet plugin returned for a claim
+type ClaimResult struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid"`
+	Error     string `json:"error,omitempty"`
+	// CDIDevices are the fully qualified names of the CDI devices handed to
+	// the containers using the claim
+	CDIDevices []string `json:"cdiDevices,omitempty"`
+	// ContainerEdits are the edits of the CDI devices resolved from the CDI
+	// spec files, as the container runtime would apply them
+	ContainerEdits *ResolvedContainerEdits `json:"containerEdits,omitempty"`
+}
+
+// ListAndWatchResult is a response of NodeListAndWatchResources
+type ListAndWatchResult struct {
+	Resources []*resourceapi.ResourceModel `json:"resources"`
+}
+
+func NewPrepareCommand(ctx context.Context, flags *Flags, sharedFlagSets cliflag.NamedFlagSets) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prepare",
+		Short: "Call NodePrepareResources for claims",
+		Long:  "prepare calls NodePrepareResources for the claims and prints the CDI devices returned for each of them together with the container edits the CDI spec files resolve them to",
+		Example: `  # Prepare fake-0 and fake-1 for the default request of the parameters in claim-parameters.yaml
+  fake-dra-client prepare --claim-uid 5a6b7c8d --devices fake-0,fake-1 --parameters claim-parameters.yaml
+
+  # Prepare the claims of a Pod again, as kubelet does when the Pod starts
+  kubectl get resourceclaims -n test1 -o yaml | fake-dra-client prepare -f -`,
+	}
+
+	claimFlags := &ClaimFlags{}
+	namedFlagSets := cliflag.NamedFlagSets{}
+	claimFlags.AddFlags(namedFlagSets.FlagSet("claims"))
+	cmd.Flags().AddFlagSet(namedFlagSets.FlagSet("claims"))
+	setUsageAndHelpFunc(cmd, sharedFlagSets, namedFlagSets)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+		defer cancel()
+
+		claims, err := claimFlags.Claims(*flags.driverName)
+		if err != nil {
+			return err
+		}
+
+		client, conn, err := dial(ctx, flags)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		callCtx, callCancel := context.WithTimeout(ctx, *flags.timeout)
+		defer callCancel()
+		klog.FromContext(ctx).V(2).Info("Calling NodePrepareResources", "numClaims", len(claims))
+		resp, err := client.NodePrepareResources(callCtx, &drapbv1.NodePrepareResourcesRequest{Claims: claims})
+		if err != nil {
+			return fmt.Errorf("error calling NodePrepareResources: %w", err)
+		}
+
+		resolver, err := NewCDIResolver(*flags.cdiRoot)
+		if err != nil {
+			return err
+		}
+
+		var results []ClaimResult
+		failed := 0
+		for _, claim := range claims {
+			result := ClaimResult{Namespace: claim.Namespace, Name: claim.Name, UID: claim.Uid}
+			prepared, ok := resp.Claims[claim.Uid]
+			switch {
+			case !ok:
+				result.Error = "claim missing from the response"
+			case prepared.Error != "":
+				result.Error = prepared.Error
+			default:
+				result.CDIDevices = prepared.CDIDevices
+				result.ContainerEdits = resolver.Resolve(prepared.CDIDevices)
+			}
+			if result.Error != "" {
+				failed++
+			}
+			results = append(results, result)
+		}
+
+		if err := printResult(cmd.OutOrStdout(), *flags.output, results); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("preparing %d of %d claims failed", failed, len(claims))
+		}
+		return nil
+	}
+
+	return cmd
+}
+
+func NewUnprepareCommand(ctx context.Context, flags *Flags, sharedFlagSets cliflag.NamedFlagSets) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unprepare",
+		Short: "Call NodeUnprepareResources for claims",
+		Long:  "unprepare calls NodeUnprepareResources for the claims and prints the error returned for each of them, if any",
+		Example: `  # Unprepare a claim prepared with fake-dra-client prepare
+  fake-dra-client unprepare --claim-uid 5a6b7c8d`,
+	}
+
+	claimFlags := &ClaimFlags{}
+	namedFlagSets := cliflag.NamedFlagSets{}
+	claimFlags.AddFlags(namedFlagSets.FlagSet("claims"))
+	cmd.Flags().AddFlagSet(namedFlagSets.FlagSet("claims"))
+	setUsageAndHelpFunc(cmd, sharedFlagSets, namedFlagSets)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+		defer cancel()
+
+		if len(claimFlags.files) == 0 && *claimFlags.uid == "" {
+			return errors.New("--claim-uid is required to unprepare a claim built from the flags")
+		}
+		claims, err := claimFlags.Claims(*flags.driverName)
+		if err != nil {
+			return err
+		}
+
+		client, conn, err := dial(ctx, flags)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		callCtx, callCancel := context.WithTimeout(ctx, *flags.timeout)
+		defer callCancel()
+		klog.FromContext(ctx).V(2).Info("Calling NodeUnprepareResources", "numClaims", len(claims))
+		resp, err := client.NodeUnprepareResources(callCtx, &drapbv1.NodeUnprepareResourcesRequest{Claims: claims})
+		if err != nil {
+			return fmt.Errorf("error calling NodeUnprepareResources: %w", err)
+		}
+
+		var results []ClaimResult
+		failed := 0
+		for _, claim := range claims {
+			result := ClaimResult{Namespace: claim.Namespace, Name: claim.Name, UID: claim.Uid}
+			unprepared, ok := resp.Claims[claim.Uid]
+			switch {
+			case !ok:
+				result.Error = "claim missing from the response"
+			case unprepared.Error != "":
+				result.Error = unprepared.Error
+			}
+			if result.Error != "" {
+				failed++
+			}
+			results = append(results, result)
+		}
+
+		if err := printResult(cmd.OutOrStdout(), *flags.output, results); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return fmt.Errorf("unpreparing %d of %d claims failed", failed, len(claims))
+		}
+		return nil
+	}
+
+	return cmd
+}
+
+func NewListAndWatchCommand(ctx context.Context, flags *Flags, sharedFlagSets cliflag.NamedFlagSets) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-and-watch",
+		Short: "Call NodeListAndWatchResources",
+		Long:  "list-and-watch calls NodeListAndWatchResources and prints the resources the plugin publishes for the node",
+	}
+
+	namedFlagSets := cliflag.NamedFlagSets{}
+	fs := namedFlagSets.FlagSet("watch")
+	watch := fs.BoolP("watch", "w", false, "Keep printing the resources sent by the plugin until interrupted, instead of exiting after the first response.")
+	cmd.Flags().AddFlagSet(fs)
+	setUsageAndHelpFunc(cmd, sharedFlagSets, namedFlagSets)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+		defer cancel()
+
+		client, conn, err := dial(ctx, flags)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		klog.FromContext(ctx).V(2).Info("Calling NodeListAndWatchResources")
+		stream, err := client.NodeListAndWatchResources(ctx, &drapbv1.NodeListAndWatchResourcesRequest{})
+		if err != nil {
+			return fmt.Errorf("error calling NodeListAndWatchResources: %w", err)
+		}
+
+		for {
+			resp, err := stream.Recv()
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("error receiving resources: %w", err)
+			}
+			if err := printResult(cmd.OutOrStdout(), *flags.output, &ListAndWatchResult{Resources: resp.Resources}); err != nil {
+				return err
+			}
+			if !*watch {
+				return nil
+			}
+		}
+	}
+
+	return cmd
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/component-base/featuregate"
+	"k8s.io/component-base/logs"
+	logsapi "k8s.io/component-base/logs/api/v1"
+	"k8s.io/component-base/term"
+	"k8s.io/klog/v2"
+	drapbv1 "k8s.io/kubelet/pkg/apis/dra/v1alpha3"
+
+	_ "k8s.io/component-base/logs/json/register" // for JSON log output support
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+)
+
+const (
+	DefaultDriverName = fakecrd.GroupName
+
+	// PluginsPath is where the directories of the plugins are created
+	PluginsPath = "/var/lib/kubelet/plugins"
+)
+
+type Flags struct {
+	driverName *string
+	socket     *string
+	timeout    *time.Duration
+
+	cdiRoot *string
+	output  *string
+}
+
+func main() {
+	command := NewCommand()
+	if err := command.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func NewCommand() *cobra.Command {
+	featureGate := featuregate.NewFeatureGate()
+	logsconfig := logsapi.NewLoggingConfiguration()
+	utilruntime.Must(logsapi.AddFeatureGates(featureGate))
+
+	cmd := &cobra.Command{
+		Use:   "fake-dra-client",
+		Short: "Call the kubelet plugin gRPC API of a DRA driver the way kubelet does",
+		Long: "fake-dra-client connects to the socket of a DRA kubelet plugin and calls NodePrepareResources, NodeUnprepareResources " +
+			"and NodeListAndWatchResources on it, so that the plugin can be debugged on a node without creating Pods.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	flags, sharedFlagSets := AddFlags(cmd, logsconfig)
+
+	logger := klog.Background().WithName("fake-dra-client")
+	ctx := klog.NewContext(context.Background(), logger)
+
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := logsapi.ValidateAndApply(logsconfig, featureGate); err != nil {
+			return err
+		}
+		if *flags.output != "yaml" && *flags.output != "json" {
+			return fmt.Errorf("invalid --output %q: must be yaml or json", *flags.output)
+		}
+		if *flags.socket == "" {
+			*flags.socket = filepath.Join(PluginsPath, *flags.driverName, "plugin.sock")
+		}
+		return nil
+	}
+
+	cmd.AddCommand(
+		NewPrepareCommand(ctx, flags, sharedFlagSets),
+		NewUnprepareCommand(ctx, flags, sharedFlagSets),
+		NewListAndWatchCommand(ctx, flags, sharedFlagSets),
+	)
+
+	return cmd
+}
+
+// AddFlags adds the flags shared by all subcommands, which are returned
+// grouped for the help of the subcommands
+func AddFlags(cmd *cobra.Command, logsconfig *logsapi.LoggingConfiguration) (*Flags, cliflag.NamedFlagSets) {
+	flags := &Flags{}
+	sharedFlagSets := cliflag.NamedFlagSets{}
+
+	fs := sharedFlagSets.FlagSet("logging")
+	logsapi.AddFlags(logsconfig, fs)
+	logs.AddFlags(fs, logs.SkipLoggingConfigurationFlags())
+
+	fs = sharedFlagSets.FlagSet("plugin")
+	flags.driverName = fs.String("driver-name", DefaultDriverName, "Name of the driver whose kubelet plugin is called. Claims are built for this driver.")
+	flags.socket = fs.String("socket", "", "Absolute path to the socket of the kubelet plugin. Defaults to "+PluginsPath+"/<driver-name>/plugin.sock.")
+	flags.timeout = fs.Duration("timeout", 30*time.Second, "Timeout of the calls to NodePrepareResources and NodeUnprepareResources.")
+
+	fs = sharedFlagSets.FlagSet("output")
+	flags.cdiRoot = fs.String("cdi-root", "/etc/cdi", "Absolute path to the directory where the plugin generates CDI files, used to resolve the container edits of the returned CDI devices.")
+	flags.output = fs.StringP("output", "o", "yaml", "Output format of the results, yaml or json.")
+
+	fs = cmd.PersistentFlags()
+	for _, f := range sharedFlagSets.FlagSets {
+		fs.AddFlagSet(f)
+	}
+
+	cols, _, _ := term.TerminalSize(cmd.OutOrStdout())
+	cliflag.SetUsageAndHelpFunc(cmd, sharedFlagSets, cols)
+
+	return flags, sharedFlagSets
+}
+
+// setUsageAndHelpFunc prints the flags of a subcommand grouped after the
+// shared ones
+func setUsageAndHelpFunc(cmd *cobra.Command, sharedFlagSets, namedFlagSets cliflag.NamedFlagSets) {
+	allFlagSets := cliflag.NamedFlagSets{}
+	for _, name := range namedFlagSets.Order {
+		allFlagSets.FlagSet(name).AddFlagSet(namedFlagSets.FlagSets[name])
+	}
+	for _, name := range sharedFlagSets.Order {
+		allFlagSets.FlagSet(name).AddFlagSet(sharedFlagSets.FlagSets[name])
+	}
+	cols, _, _ := term.TerminalSize(cmd.OutOrStdout())
+	cliflag.SetUsageAndHelpFunc(cmd, allFlagSets, cols)
+}
+
+// dial connects to the socket of the kubelet plugin. The connection is
+// established lazily, so an unreachable socket is reported by the first call.
+func dial(ctx context.Context, flags *Flags) (drapbv1.NodeClient, *grpc.ClientConn, error) {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("Connecting to kubelet plugin", "socket", *flags.socket)
+
+	conn, err := grpc.DialContext(ctx, "unix://"+*flags.socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error connecting to %s: %w", *flags.socket, err)
+	}
+	return drapbv1.NewNodeClient(conn), conn, nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+)
+
+// RunWithLeaderElection calls run once this replica holds the lease, or right
+// away if leader election is disabled. The context passed to run is cancelled
+// when the lease is lost, in which case an error is returned so that the
+// process restarts as a candidate with fresh caches.
+func RunWithLeaderElection(ctx context.Context, config *Config, run func(context.Context) error) error {
+	logger := klog.FromContext(ctx)
+	flags := config.flags
+
+	if !*flags.leaderElect {
+		return run(ctx)
+	}
+
+	namespace := *flags.leaderElectLeaseNamespace
+	if namespace == "" {
+		namespace = config.namespace
+	}
+	if namespace == "" {
+		return fmt.Errorf("lease namespace is unknown, set --leader-elect-lease-namespace or POD_NAMESPACE")
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("error getting hostname: %w", err)
+	}
+	// Make the identity unique in case of a restart with the same hostname
+	identity := hostname + "_" + string(uuid.NewUUID())
+
+	lock, err := resourcelock.New(
+		resourcelock.LeasesResourceLock,
+		namespace,
+		*flags.leaderElectLeaseName,
+		config.clientset.core.CoreV1(),
+		config.clientset.core.CoordinationV1(),
+		resourcelock.ResourceLockConfig{Identity: identity},
+	)
+	if err != nil {
+		return fmt.Errorf("error creating lease lock: %w", err)
+	}
+
+	electionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var started atomic.Bool
+	runErrCh := make(chan error, 1)
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   *flags.leaderElectLeaseDuration,
+		RenewDeadline:   *flags.leaderElectRenewDeadline,
+		RetryPeriod:     *flags.leaderElectRetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            *flags.leaderElectLeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				logger.Info("Started leading", "lease", klog.KRef(namespace, *flags.leaderElectLeaseName), "identity", identity)
+				started.Store(true)
+				runErrCh <- run(ctx)
+				// Release the lease right away so that another replica does
+				// not have to wait for it to expire
+				cancel()
+			},
+			OnStoppedLeading: func() {
+				logger.Info("Stopped leading", "lease", klog.KRef(namespace, *flags.leaderElectLeaseName), "identity", identity)
+			},
+			OnNewLeader: func(leader string) {
+				if leader != identity {
+					logger.Info("New leader elected", "identity", leader)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating leader elector: %w", err)
+	}
+
+	logger.Info("Waiting for leader election", "lease", klog.KRef(namespace, *flags.leaderElectLeaseName), "identity", identity)
+	elector.Run(electionCtx)
+
+	if ctx.Err() != nil && !started.Load() {
+		// Stopped while waiting as a candidate
+		return nil
+	}
+	// Otherwise the lease was held at some point and the context of run is
+	// cancelled together with the leadership, wait for it to wind down
+	if err := <-runErrCh; err != nil {
+		return err
+	}
+	if ctx.Err() == nil {
+		return fmt.Errorf("leader election lost")
+	}
+	return nil
+}
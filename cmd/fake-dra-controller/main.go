@@ -7,8 +7,11 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"os/signal"
 	"path"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -16,10 +19,15 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	coreclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/component-base/cli"
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/component-base/featuregate"
@@ -30,18 +38,50 @@ import (
 
 	_ "k8s.io/component-base/logs/json/register" // for JSON log output support
 	"k8s.io/component-base/metrics/legacyregistry"
-	_ "k8s.io/component-base/metrics/prometheus/restclient" // for client metric registration
-	_ "k8s.io/component-base/metrics/prometheus/version"    // for version metric registration
-	_ "k8s.io/component-base/metrics/prometheus/workqueue"  // register work queues in the default legacy registry
+	_ "k8s.io/component-base/metrics/prometheus/clientgo/leaderelection" // for leader election metric registration
+	_ "k8s.io/component-base/metrics/prometheus/restclient"              // for client metric registration
+	_ "k8s.io/component-base/metrics/prometheus/version"                 // for version metric registration
+	_ "k8s.io/component-base/metrics/prometheus/workqueue"               // register work queues in the default legacy registry
 
 	shakeclientset "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned"
+	shakescheme "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned/scheme"
+	"github.com/toVersus/fake-dra-driver/pkg/controller"
 )
 
+var (
+	// eventScheme resolves references to the objects Events are recorded on
+	eventScheme = runtime.NewScheme()
+
+	// eventCorrelatorOptions aggregates similar Events and rate limits them
+	// per object, so that a failing object cannot flood the API server
+	eventCorrelatorOptions = record.CorrelatorOptions{
+		BurstSize: 25,
+		QPS:       1. / 300.,
+	}
+)
+
+func init() {
+	utilruntime.Must(scheme.AddToScheme(eventScheme))
+	utilruntime.Must(shakescheme.AddToScheme(eventScheme))
+}
+
 type Flags struct {
 	kubeconfig   *string
 	kubeAPIQPS   *float32
 	kubeAPIBurst *int
 	workers      *int
+	dryRun       *string
+
+	driverNames *[]string
+
+	adminAccessNamespaces *[]string
+
+	leaderElect               *bool
+	leaderElectLeaseName      *string
+	leaderElectLeaseNamespace *string
+	leaderElectLeaseDuration  *time.Duration
+	leaderElectRenewDeadline  *time.Duration
+	leaderElectRetryPeriod    *time.Duration
 
 	httpEndpoint *string
 	metricsPath  *string
@@ -58,8 +98,12 @@ type Config struct {
 	flags     *Flags
 	csconfig  *rest.Config
 	clientset *Clientset
-	ctx       context.Context
-	mux       *http.ServeMux
+	recorder  record.EventRecorder
+	// registry holds the driver specific metrics, nil if metrics are not
+	// served
+	registry *prometheus.Registry
+	ctx      context.Context
+	mux      *http.ServeMux
 }
 
 func main() {
@@ -101,6 +145,11 @@ func NewCommand() *cobra.Command {
 	}
 
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		// Cancelling the context on termination lets the leader release its
+		// lease instead of making the other replicas wait for it to expire
+		ctx, cancel := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+		defer cancel()
+
 		mux := http.NewServeMux()
 
 		csconfig, err := GetClusterConfig(ctx, flags)
@@ -118,6 +167,11 @@ func NewCommand() *cobra.Command {
 			return fmt.Errorf("error creating shake client: %w", err)
 		}
 
+		eventBroadcaster := record.NewBroadcaster(record.WithContext(ctx), record.WithCorrelatorOptions(eventCorrelatorOptions))
+		eventBroadcaster.StartStructuredLogging(4)
+		eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: coreclient.CoreV1().Events("")})
+		defer eventBroadcaster.Shutdown()
+
 		config := &Config{
 			ctx:       ctx,
 			mux:       mux,
@@ -128,6 +182,7 @@ func NewCommand() *cobra.Command {
 				coreclient,
 				shakeclient,
 			},
+			recorder: eventBroadcaster.NewRecorder(eventScheme, corev1.EventSource{Component: "fake-dra-controller"}),
 		}
 
 		if *flags.httpEndpoint != "" {
@@ -136,9 +191,26 @@ func NewCommand() *cobra.Command {
 			}
 		}
 
-		err = StartClaimParametersGenerator(ctx, config)
+		dryRun, err := parseDryRun(*flags.dryRun)
+		if err != nil {
+			return err
+		}
+		opts := controller.Options{
+			DriverNames:           *flags.driverNames,
+			AdminAccessNamespaces: *flags.adminAccessNamespaces,
+			Workers:               *flags.workers,
+			DryRun:                dryRun,
+			Recorder:              config.recorder,
+		}
+		if config.registry != nil {
+			opts.Registerer = config.registry
+		}
+
+		err = RunWithLeaderElection(ctx, config, func(ctx context.Context) error {
+			return controller.Run(ctx, coreclient, shakeclient, opts)
+		})
 		if err != nil {
-			return fmt.Errorf("start claim parameters generator: %w", err)
+			return fmt.Errorf("start controllers: %w", err)
 		}
 
 		return nil
@@ -159,7 +231,22 @@ func AddFlags(cmd *cobra.Command, logsconfig *logsapi.LoggingConfiguration, feat
 	flags.kubeconfig = fs.String("kubeconfig", "", "Absolute path to the kube.config file. Either this or KUBECONFIG need to be set if the driver is being run out of cluster.")
 	flags.kubeAPIQPS = fs.Float32("kube-api-qps", 5, "QPS to use while communicating with the kubernetes apiserver.")
 	flags.kubeAPIBurst = fs.Int("kube-api-burst", 10, "Burst to use while communicating with the kubernetes apiserver.")
-	flags.workers = fs.Int("workers", 10, "Concurrency to process multiple claims")
+	flags.workers = fs.Int("workers", controller.DefaultWorkers, "Concurrency to process multiple claims")
+	flags.dryRun = fs.String("dry-run", "none", "Must be \"none\" or \"server\". If server, objects are only validated by the API server and changes are not persisted.")
+
+	fs = sharedFlagSets.FlagSet("driver")
+	flags.driverNames = fs.StringSlice("driver-names", []string{controller.DefaultDriverName}, "Comma separated names of the drivers whose ResourceClasses, FakeClaimParameters and FakeDeviceQuotas are reconciled. Requests of FakeClaimParameters which do not name a driver allocate from the first one.")
+
+	fs = sharedFlagSets.FlagSet("admin access")
+	flags.adminAccessNamespaces = fs.StringSlice("admin-access-namespaces", nil, "Comma separated namespaces FakeClaimParameters may request admin access in. Admin access claims see every device on the node without allocating them.")
+
+	fs = sharedFlagSets.FlagSet("leader election")
+	flags.leaderElect = fs.Bool("leader-elect", false, "Start a leader election client and gain leadership before running the controller. Enable this when running replicated controllers for high availability.")
+	flags.leaderElectLeaseName = fs.String("leader-elect-lease-name", "fake-dra-controller", "The name of the Lease object used for leader election.")
+	flags.leaderElectLeaseNamespace = fs.String("leader-elect-lease-namespace", "", "The namespace of the Lease object used for leader election. Defaults to the namespace in POD_NAMESPACE.")
+	flags.leaderElectLeaseDuration = fs.Duration("leader-elect-lease-duration", 15*time.Second, "The duration that non-leader candidates will wait after observing a leadership renewal until attempting to acquire leadership.")
+	flags.leaderElectRenewDeadline = fs.Duration("leader-elect-renew-deadline", 10*time.Second, "The interval between attempts by the acting leader to renew a leadership slot before it stops leading. This must be less than the lease duration.")
+	flags.leaderElectRetryPeriod = fs.Duration("leader-elect-retry-period", 2*time.Second, "The duration the clients should wait between attempting acquisition and renewal of a leadership.")
 
 	fs = sharedFlagSets.FlagSet("http server")
 	flags.httpEndpoint = fs.String("http-endpoint", "",
@@ -222,6 +309,7 @@ func SetupHTTPEndpoint(ctx context.Context, config *Config) error {
 			legacyregistry.DefaultGatherer,
 		}
 		gatherers = append(gatherers, reg)
+		config.registry = reg
 
 		actualPath := path.Join("/", *config.flags.metricsPath)
 		logger.Info("Starting metrics", "path", actualPath)
@@ -259,3 +347,15 @@ func SetupHTTPEndpoint(ctx context.Context, config *Config) error {
 
 	return nil
 }
+
+// parseDryRun validates the value of --dry-run
+func parseDryRun(dryRun string) (bool, error) {
+	switch dryRun {
+	case "none":
+		return false, nil
+	case "server":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid --dry-run value %q, must be \"none\" or \"server\"", dryRun)
+	}
+}
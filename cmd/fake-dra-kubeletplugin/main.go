@@ -2,40 +2,44 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"path"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
+	corev1 "k8s.io/api/core/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	coreclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/component-base/featuregate"
 	"k8s.io/component-base/logs"
 	logsapi "k8s.io/component-base/logs/api/v1"
+	"k8s.io/component-base/metrics/legacyregistry"
 	"k8s.io/component-base/term"
-	plugin "k8s.io/dynamic-resource-allocation/kubeletplugin"
 	"k8s.io/klog/v2"
 
 	_ "k8s.io/component-base/logs/json/register" // for JSON log output support
 
-	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1alpha1"
-	shakeclientset "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned"
-)
-
-const (
-	DriverName     = fakecrd.GroupName
-	DriverAPIGroup = fakecrd.GroupName
-
-	PluginRegistrationPath = "/var/lib/kubelet/plugins_registry/" + DriverName + ".sock"
-	DriverPluginPath       = "/var/lib/kubelet/plugins/" + DriverName
-	DriverPluginSocketPath = DriverPluginPath + "/plugin.sock"
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	"github.com/toVersus/fake-dra-driver/pkg/kubeletplugin"
 )
 
 type Flags struct {
@@ -43,12 +47,36 @@ type Flags struct {
 	kubeAPIQPS   *float32
 	kubeAPIBurst *int
 
-	cdiRoot *string
+	driverName             *string
+	nodeName               *string
+	pluginRegistrationPath *string
+	pluginPath             *string
+
+	cdiRoot         *string
+	cdiVendor       *string
+	cdiClass        *string
+	claimConfigRoot *string
+
+	deviceTypes           *[]string
+	unhealthyDevices      *[]string
+	adminAccessNamespaces *[]string
+
+	httpEndpoint      *string
+	metricsPath       *string
+	telemetryInterval *time.Duration
+
+	standalone *bool
+	rootDir    *string
+	// temporaryRootDir is set when the root directory was created by the
+	// plugin, which removes it on shutdown
+	temporaryRootDir bool
 }
 
-type Config struct {
-	flags       *Flags
-	shakeclient shakeclientset.Interface
+// eventCorrelatorOptions aggregates similar Events and rate limits them per
+// object, so that a failing claim cannot flood the API server
+var eventCorrelatorOptions = record.CorrelatorOptions{
+	BurstSize: 25,
+	QPS:       1. / 300.,
 }
 
 func main() {
@@ -69,11 +97,13 @@ func NewCommand() *cobra.Command {
 		Long: "fake-dra-kubeletplugin implements the Dynamic Resource Allocation API based kubelet plugin",
 	}
 
-	flags := AddFlags(cmd, logsconfig)
+	flags, sharedFlagSets := AddFlags(cmd, logsconfig)
 
 	logger := klog.Background().WithName("fake-dra-kubeletplugin")
 	ctx := klog.NewContext(context.Background(), logger)
 
+	cmd.AddCommand(NewVirtualNodesCommand(ctx, flags, sharedFlagSets))
+
 	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
 		v := viper.New()
 		v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
@@ -89,36 +119,77 @@ func NewCommand() *cobra.Command {
 		if err := logsapi.ValidateAndApply(logsconfig, featureGate); err != nil {
 			return err
 		}
-		return nil
+		return flags.complete()
 	}
 
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
-		csconfig, err := GetClientsetConfig(ctx, flags)
-		if err != nil {
-			return fmt.Errorf("error creating client configuration: %w", err)
+		ctx, cancel := signal.NotifyContext(ctx, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+		defer cancel()
+
+		if flags.temporaryRootDir {
+			defer os.RemoveAll(*flags.rootDir)
 		}
 
-		shakeclient, err := shakeclientset.NewForConfig(csconfig)
-		if err != nil {
-			return fmt.Errorf("error creating 3-shake.com client: %w", err)
+		eventBroadcaster := record.NewBroadcaster(record.WithContext(ctx), record.WithCorrelatorOptions(eventCorrelatorOptions))
+		eventBroadcaster.StartStructuredLogging(4)
+		defer eventBroadcaster.Shutdown()
+
+		opts := kubeletplugin.Options{
+			DriverName:             *flags.driverName,
+			NodeName:               *flags.nodeName,
+			PluginRegistrationPath: *flags.pluginRegistrationPath,
+			PluginPath:             *flags.pluginPath,
+			CDIRoot:                *flags.cdiRoot,
+			CDIVendor:              *flags.cdiVendor,
+			CDIClass:               *flags.cdiClass,
+			ClaimConfigRoot:        *flags.claimConfigRoot,
+			DeviceTypes:            *flags.deviceTypes,
+			UnhealthyDevices:       *flags.unhealthyDevices,
+			AdminAccessNamespaces:  *flags.adminAccessNamespaces,
+			Recorder:               eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "fake-dra-kubeletplugin", Host: *flags.nodeName}),
+		}
+		if *flags.standalone {
+			opts.RootDir = *flags.rootDir
+		}
+
+		if *flags.httpEndpoint != "" {
+			registry, err := SetupHTTPEndpoint(ctx, flags)
+			if err != nil {
+				return fmt.Errorf("error creating HTTP endpoint: %w", err)
+			}
+			if registry != nil {
+				opts.Registerer = registry
+				opts.TelemetryInterval = *flags.telemetryInterval
+			}
 		}
 
-		nodeName := os.Getenv("NODE_NAME")
-		podNamespace := os.Getenv("POD_NAMESPACE")
+		// The plugin only needs the API server to record Events, so it runs
+		// without a client when there is neither a kubeconfig nor a cluster
+		csconfig, err := GetClientsetConfig(ctx, flags)
+		switch {
+		case errors.Is(err, rest.ErrNotInCluster):
+			logger.Info("Running without Kubernetes client, Events are only logged", "reason", err)
+		case err != nil:
+			return fmt.Errorf("error creating client configuration: %w", err)
+		default:
+			opts.CoreClient, err = coreclientset.NewForConfig(csconfig)
+			if err != nil {
+				return fmt.Errorf("error creating core client: %w", err)
+			}
 
-		config := &Config{
-			flags:       flags,
-			shakeclient: shakeclient,
+			eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: opts.CoreClient.CoreV1().Events("")})
 		}
 
-		klog.InfoS("Starting fake-dra-kubeletplugin", "pod", podNamespace, "node", nodeName)
-		return StartPlugin(ctx, config)
+		logger.Info("Starting fake-dra-kubeletplugin", "pod", os.Getenv("POD_NAMESPACE"), "node", *flags.nodeName, "driverName", *flags.driverName, "standalone", *flags.standalone)
+		return RunPlugin(ctx, opts)
 	}
 
 	return cmd
 }
 
-func AddFlags(cmd *cobra.Command, logsconfig *logsapi.LoggingConfiguration) *Flags {
+// AddFlags adds the flags shared by the plugin and its subcommands, which are
+// returned grouped for the help of the subcommands
+func AddFlags(cmd *cobra.Command, logsconfig *logsapi.LoggingConfiguration) (*Flags, cliflag.NamedFlagSets) {
 	flags := &Flags{}
 	sharedFlagSets := cliflag.NamedFlagSets{}
 
@@ -131,18 +202,76 @@ func AddFlags(cmd *cobra.Command, logsconfig *logsapi.LoggingConfiguration) *Fla
 	flags.kubeAPIQPS = fs.Float32("kube-api-qps", 5, "QPS to use while communicating with the kubernetes apiserver.")
 	flags.kubeAPIBurst = fs.Int("kube-api-burst", 10, "Burst to use while communicating with the kubernetes apiserver.")
 
+	fs = sharedFlagSets.FlagSet("driver")
+	flags.driverName = fs.String("driver-name", kubeletplugin.DefaultDriverName, "Name of the driver the plugin registers with kubelet and publishes resources for. Running several drivers with different names gives each of them its own inventory of devices.")
+	flags.nodeName = fs.String("node-name", "", "Name of the node the plugin runs on, which determines the devices emulated on it. Defaults to the hostname.")
+	flags.pluginRegistrationPath = fs.String("plugin-registration-path", "", "Absolute path to the registration socket of the plugin. Defaults to "+kubeletplugin.PluginsRegistryPath+"/<driver-name>.sock.")
+	flags.pluginPath = fs.String("plugin-path", "", "Absolute path to the directory where the socket of the plugin is created. Defaults to "+kubeletplugin.PluginsPath+"/<driver-name>.")
+
 	fs = sharedFlagSets.FlagSet("CDI")
-	flags.cdiRoot = fs.String("cdi-root", "/etc/cdi", "Absolute path to the directory where CDI files will be generated.")
+	flags.cdiRoot = fs.String("cdi-root", "", "Absolute path to the directory where CDI files will be generated. Defaults to /etc/cdi, or <root-dir>/cdi in standalone mode.")
+	flags.cdiVendor = fs.String("cdi-vendor", "", "Vendor of the CDI devices handed to containers. Defaults to k8s.<driver-name>.")
+	flags.cdiClass = fs.String("cdi-class", kubeletplugin.DefaultCDIClass, "Class of the CDI devices handed to containers.")
+	flags.claimConfigRoot = fs.String("claim-config-root", "", "Absolute path to the directory where the device config descriptor files of claims will be generated. The path has to be the same on the host. Defaults to <plugin-path>/claims.")
+
+	fs = sharedFlagSets.FlagSet("emulation")
+	flags.deviceTypes = fs.StringSlice("device-types", fakecrd.DeviceTypes(), fmt.Sprintf("Comma separated types of devices to emulate on the node, among %s.", strings.Join(kubeletplugin.RegisteredDeviceTypes(), ", ")))
+	flags.unhealthyDevices = fs.StringSlice("unhealthy-devices", nil, "Comma separated UUIDs of devices to emulate as unhealthy. Preparing claims allocated such a device fails.")
+	flags.adminAccessNamespaces = fs.StringSlice("admin-access-namespaces", nil, "Comma separated namespaces admin access claims may be prepared in. Should match the namespaces given to the controller, admin access claims of any other namespace fail to prepare.")
 
 	fs = cmd.PersistentFlags()
 	for _, f := range sharedFlagSets.FlagSets {
 		fs.AddFlagSet(f)
 	}
 
+	// The standalone mode only applies to the plugin itself, so its flags
+	// are not shared with the subcommands
+	flagSets := cliflag.NamedFlagSets{}
+	for _, name := range sharedFlagSets.Order {
+		flagSets.FlagSet(name).AddFlagSet(sharedFlagSets.FlagSets[name])
+	}
+	fs = flagSets.FlagSet("telemetry")
+	flags.httpEndpoint = fs.String("http-endpoint", "",
+		"The TCP network address where the HTTP server for metrics, including the telemetry of the devices, will listen (example: `:8080`). The default is the empty string, which means the server is disabled.")
+	flags.metricsPath = fs.String("metrics-path", "/metrics", "The HTTP path where Prometheus metrics will be exposed, disabled if empty. The telemetry of the devices is only sampled when metrics are exposed.")
+	flags.telemetryInterval = fs.Duration("telemetry-interval", kubeletplugin.DefaultTelemetryInterval, "Interval the utilization, memory, temperature and power of the devices are sampled at.")
+	cmd.Flags().AddFlagSet(fs)
+
+	fs = flagSets.FlagSet("standalone")
+	flags.standalone = fs.Bool("standalone", false, "Run the plugin out of cluster without root privileges. Paths which are not set are created under --root-dir instead of the directories of kubelet, and the API server is only used when a kubeconfig is given.")
+	flags.rootDir = fs.String("root-dir", "", "Absolute path to the directory holding the sockets, CDI files and claim config files in standalone mode. Defaults to a temporary directory removed on shutdown.")
+	cmd.Flags().AddFlagSet(fs)
+
 	cols, _, _ := term.TerminalSize(cmd.OutOrStdout())
-	cliflag.SetUsageAndHelpFunc(cmd, sharedFlagSets, cols)
+	cliflag.SetUsageAndHelpFunc(cmd, flagSets, cols)
 
-	return flags
+	return flags, sharedFlagSets
+}
+
+// complete validates the driver name and defaults the node name. The root
+// directory of the standalone mode is created if it is not set, the paths
+// which are not set are defaulted under it by the plugin.
+func (f *Flags) complete() error {
+	if msgs := validation.IsDNS1123Subdomain(*f.driverName); len(msgs) > 0 {
+		return fmt.Errorf("invalid --driver-name %q: %s", *f.driverName, strings.Join(msgs, ", "))
+	}
+	if *f.nodeName == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("error getting hostname for --node-name: %w", err)
+		}
+		*f.nodeName = strings.ToLower(hostname)
+	}
+
+	if *f.standalone && *f.rootDir == "" {
+		dir, err := os.MkdirTemp("", "fake-dra-kubeletplugin-")
+		if err != nil {
+			return fmt.Errorf("error creating root directory: %w", err)
+		}
+		*f.rootDir = dir
+		f.temporaryRootDir = true
+	}
+	return nil
 }
 
 func GetClientsetConfig(ctx context.Context, f *Flags) (*rest.Config, error) {
@@ -157,9 +286,13 @@ func GetClientsetConfig(ctx context.Context, f *Flags) (*rest.Config, error) {
 
 	var err error
 	if *f.kubeconfig == "" {
+		// A standalone plugin only talks to the API server it is told about
+		if *f.standalone {
+			return nil, rest.ErrNotInCluster
+		}
 		csconfig, err = rest.InClusterConfig()
 		if err != nil {
-			return nil, fmt.Errorf("create in-cluster client configuration: %v", err)
+			return nil, fmt.Errorf("create in-cluster client configuration: %w", err)
 		}
 	} else {
 		csconfig, err = clientcmd.BuildConfigFromFlags("", *f.kubeconfig)
@@ -174,52 +307,64 @@ func GetClientsetConfig(ctx context.Context, f *Flags) (*rest.Config, error) {
 	return csconfig, nil
 }
 
-func StartPlugin(ctx context.Context, config *Config) error {
+// SetupHTTPEndpoint serves the metrics on the HTTP endpoint and returns the
+// registry of the telemetry of the devices, nil if metrics are disabled
+func SetupHTTPEndpoint(ctx context.Context, f *Flags) (*prometheus.Registry, error) {
 	logger := klog.FromContext(ctx)
-	logger.Info("Starting fake-dra-kubeletplugin")
-
-	logger.Info("Creating plugin directory", "dir", DriverPluginPath)
-	if err := os.MkdirAll(DriverPluginPath, 0750); err != nil {
-		return fmt.Errorf("error creating plugin directory: %w", err)
-	}
-
-	info, err := os.Stat(*config.flags.cdiRoot)
-	if err != nil && os.IsNotExist(err) {
-		logger.Info("Creating CDI config directory", "dir", *config.flags.cdiRoot)
-		if err := os.MkdirAll(*config.flags.cdiRoot, 0750); err != nil {
-			return err
+	mux := http.NewServeMux()
+
+	var reg *prometheus.Registry
+	if *f.metricsPath != "" {
+		// To collect metrics data from the metric handler itself, we
+		// let it register itself and then collect from that registry.
+		reg = prometheus.NewRegistry()
+		gatherers := prometheus.Gatherers{
+			// Include Go runtime and process metrics:
+			// https://github.com/kubernetes/kubernetes/blob/9780d88cb6a4b5b067256ecb4abf56892093ee87/staging/src/k8s.io/component-base/metrics/legacyregistry/registry.go#L46-L49
+			legacyregistry.DefaultGatherer,
+			reg,
 		}
-	} else if err != nil {
-		return err
-	} else if !info.IsDir() {
-		return fmt.Errorf("path to cdi file file generation must not be a directory: %w", err)
+
+		actualPath := path.Join("/", *f.metricsPath)
+		logger.Info("Starting metrics", "path", actualPath)
+		mux.Handle(actualPath,
+			promhttp.InstrumentMetricHandler(
+				reg,
+				promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{})))
 	}
 
-	driver, err := NewDriver(ctx, config)
+	listener, err := net.Listen("tcp", *f.httpEndpoint)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("Listen on HTTP endpoint: %v", err)
 	}
 
-	dp, err := plugin.Start(
-		driver,
-		plugin.DriverName(DriverName),
-		plugin.RegistrarSocketPath(PluginRegistrationPath),
-		plugin.PluginSocketPath(DriverPluginSocketPath),
-		plugin.KubeletPluginSocketPath(DriverPluginSocketPath),
-	)
+	go func() {
+		logger.Info("Starting HTTP server", "endpoint", *f.httpEndpoint)
+		err := http.Serve(listener, mux)
+		if err != nil {
+			logger.Error(err, "HTTP server failed")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+	}()
+
+	return reg, nil
+}
+
+// RunPlugin serves the kubelet plugin until the context is done
+func RunPlugin(ctx context.Context, opts kubeletplugin.Options) error {
+	logger := klog.FromContext(ctx)
+	logger.Info("Starting fake-dra-kubeletplugin")
+
+	p, err := kubeletplugin.Start(ctx, opts)
 	if err != nil {
 		return err
 	}
 
-	sig := make(chan os.Signal, 1)
-	signal.Notify(sig, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
-	klog.Info("Got signal, shutting down fake-dra-kubeletplugin...", "signal", <-sig)
-	<-sig
-
-	dp.Stop()
+	<-ctx.Done()
+	logger.Info("Shutting down fake-dra-kubeletplugin...", "reason", context.Cause(ctx))
 
-	if err := driver.Shutdown(ctx); err != nil {
-		return fmt.Errorf("error shutting down driver: %w", err)
+	if err := p.Stop(ctx); err != nil {
+		return err
 	}
 	logger.Info("Shutdown fake-dra-kubeletplugin completed successfully")
 
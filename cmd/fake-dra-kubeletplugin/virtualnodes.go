@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/labels"
+	coreclientset "k8s.io/client-go/kubernetes"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/component-base/term"
+	"k8s.io/klog/v2"
+
+	"github.com/toVersus/fake-dra-driver/pkg/kubeletplugin"
+)
+
+type VirtualNodesFlags struct {
+	nodeSelector *string
+	workers      *int
+}
+
+// NewVirtualNodesCommand returns the command publishing the devices of
+// virtual nodes, which share the flags of the plugin selecting the driver and
+// the emulated devices.
+func NewVirtualNodesCommand(ctx context.Context, flags *Flags, sharedFlagSets cliflag.NamedFlagSets) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "virtual-nodes",
+		Short: "Publish the emulated devices of nodes without a kubelet",
+		Long:  "virtual-nodes publishes ResourceSlices with the devices the kubelet plugin would emulate on nodes without a kubelet, such as those simulated by KWOK, so that the scheduler can allocate them at scale",
+	}
+
+	vflags := &VirtualNodesFlags{}
+	flagSets := cliflag.NamedFlagSets{}
+	fs := flagSets.FlagSet("virtual nodes")
+	vflags.nodeSelector = fs.String("node-selector", "type=kwok", "Label selector of the virtual nodes to publish devices for.")
+	vflags.workers = fs.Int("workers", 10, "Concurrency to publish the devices of multiple nodes.")
+	cmd.Flags().AddFlagSet(fs)
+
+	for _, name := range sharedFlagSets.Order {
+		flagSets.FlagSet(name).AddFlagSet(sharedFlagSets.FlagSets[name])
+	}
+	cols, _, _ := term.TerminalSize(cmd.OutOrStdout())
+	cliflag.SetUsageAndHelpFunc(cmd, flagSets, cols)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+		defer cancel()
+
+		csconfig, err := GetClientsetConfig(ctx, flags)
+		if err != nil {
+			return fmt.Errorf("error creating client configuration: %w", err)
+		}
+
+		coreclient, err := coreclientset.NewForConfig(csconfig)
+		if err != nil {
+			return fmt.Errorf("error creating core client: %w", err)
+		}
+
+		selector, err := labels.Parse(*vflags.nodeSelector)
+		if err != nil {
+			return fmt.Errorf("invalid --node-selector: %w", err)
+		}
+
+		publisher, err := kubeletplugin.NewVirtualNodePublisher(coreclient, kubeletplugin.VirtualNodePublisherOptions{
+			DriverName:   *flags.driverName,
+			DeviceTypes:  *flags.deviceTypes,
+			NodeSelector: selector,
+		})
+		if err != nil {
+			return fmt.Errorf("error creating virtual node publisher: %w", err)
+		}
+
+		klog.FromContext(ctx).Info("Starting virtual node publisher", "driverName", *flags.driverName, "deviceTypes", *flags.deviceTypes, "nodeSelector", selector.String(), "workers", *vflags.workers)
+		return publisher.Run(ctx, *vflags.workers)
+	}
+
+	return cmd
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// maxRequestBytes limits the size of admission requests, the API server
+	// sends at most 3MiB objects
+	maxRequestBytes = 8 * 1024 * 1024
+)
+
+var (
+	scheme = runtime.NewScheme()
+	codecs = serializer.NewCodecFactory(scheme)
+)
+
+func init() {
+	utilruntime.Must(admissionv1.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+}
+
+// admitFunc decides on a single admission request
+type admitFunc func(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
+
+// admissionHandler decodes AdmissionReview requests, passes them to admit and
+// writes back its response.
+func admissionHandler(admit admitFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := klog.FromContext(r.Context()).WithValues("path", r.URL.Path)
+
+		if r.Method != http.MethodPost {
+			http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+			http.Error(w, fmt.Sprintf("unsupported content type %q", contentType), http.StatusUnsupportedMediaType)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBytes))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("error reading request body: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		review := &admissionv1.AdmissionReview{}
+		if _, _, err := codecs.UniversalDeserializer().Decode(body, nil, review); err != nil {
+			http.Error(w, fmt.Sprintf("error decoding AdmissionReview: %v", err), http.StatusBadRequest)
+			return
+		}
+		if review.Request == nil {
+			http.Error(w, "AdmissionReview contains no request", http.StatusBadRequest)
+			return
+		}
+
+		logger = logger.WithValues("uid", review.Request.UID, "kind", review.Request.Kind.Kind,
+			"object", klog.KRef(review.Request.Namespace, review.Request.Name), "operation", review.Request.Operation)
+		ctx := klog.NewContext(r.Context(), logger)
+
+		response := admit(ctx, review.Request)
+		response.UID = review.Request.UID
+		logger.V(4).Info("Admission request handled", "allowed", response.Allowed)
+
+		review.Request = nil
+		review.Response = response
+		out, err := runtime.Encode(codecs.LegacyCodec(admissionv1.SchemeGroupVersion), review)
+		if err != nil {
+			logger.Error(err, "Error encoding AdmissionReview")
+			http.Error(w, fmt.Sprintf("error encoding AdmissionReview: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(out); err != nil {
+			logger.Error(err, "Error writing AdmissionReview response")
+		}
+	})
+}
+
+func allowed() *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+func denied(err error) *admissionv1.AdmissionResponse {
+	status := metav1.Status{
+		Status:  metav1.StatusFailure,
+		Message: err.Error(),
+		Reason:  metav1.StatusReasonBadRequest,
+		Code:    http.StatusBadRequest,
+	}
+	if apiStatus, ok := err.(apierrors.APIStatus); ok {
+		status = apiStatus.Status()
+	}
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result:  &status,
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+
+	fakev1alpha1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1alpha1"
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+)
+
+const (
+	conversionPath = "/convert"
+)
+
+var (
+	conversionScheme = runtime.NewScheme()
+	conversionCodecs = serializer.NewCodecFactory(conversionScheme)
+)
+
+func init() {
+	utilruntime.Must(fakev1alpha1.AddToScheme(conversionScheme))
+	utilruntime.Must(fakecrd.AddToScheme(conversionScheme))
+}
+
+// conversionHandler decodes ConversionReview requests, converts the objects
+// through the hub version and writes back the converted objects.
+func conversionHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger := klog.FromContext(r.Context()).WithValues("path", r.URL.Path)
+
+		if r.Method != http.MethodPost {
+			http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+			http.Error(w, fmt.Sprintf("unsupported content type %q", contentType), http.StatusUnsupportedMediaType)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBytes))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("error reading request body: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		review := &apiextensionsv1.ConversionReview{}
+		if _, _, err := codecs.UniversalDeserializer().Decode(body, nil, review); err != nil {
+			http.Error(w, fmt.Sprintf("error decoding ConversionReview: %v", err), http.StatusBadRequest)
+			return
+		}
+		if review.Request == nil {
+			http.Error(w, "ConversionReview contains no request", http.StatusBadRequest)
+			return
+		}
+
+		logger = logger.WithValues("uid", review.Request.UID, "desiredAPIVersion", review.Request.DesiredAPIVersion)
+		ctx := klog.NewContext(r.Context(), logger)
+
+		response := convertObjects(ctx, review.Request)
+		response.UID = review.Request.UID
+		logger.V(4).Info("Conversion request handled", "objects", len(review.Request.Objects), "status", response.Result.Status)
+
+		review.Request = nil
+		review.Response = response
+		out, err := runtime.Encode(codecs.LegacyCodec(apiextensionsv1.SchemeGroupVersion), review)
+		if err != nil {
+			logger.Error(err, "Error encoding ConversionReview")
+			http.Error(w, fmt.Sprintf("error encoding ConversionReview: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(out); err != nil {
+			logger.Error(err, "Error writing ConversionReview response")
+		}
+	})
+}
+
+func convertObjects(ctx context.Context, req *apiextensionsv1.ConversionRequest) *apiextensionsv1.ConversionResponse {
+	logger := klog.FromContext(ctx)
+
+	desired, err := schema.ParseGroupVersion(req.DesiredAPIVersion)
+	if err != nil {
+		return conversionFailed(fmt.Errorf("error parsing desired API version: %w", err))
+	}
+
+	converted := make([]runtime.RawExtension, 0, len(req.Objects))
+	for _, in := range req.Objects {
+		out, err := convertObject(in.Raw, desired)
+		if err != nil {
+			logger.V(2).Info("Conversion failed", "err", err)
+			return conversionFailed(err)
+		}
+		converted = append(converted, runtime.RawExtension{Raw: out})
+	}
+
+	return &apiextensionsv1.ConversionResponse{
+		ConvertedObjects: converted,
+		Result:           metav1.Status{Status: metav1.StatusSuccess},
+	}
+}
+
+// convertObject converts a single object into the desired version, going
+// through the hub version unless one of them is the hub.
+func convertObject(raw []byte, desired schema.GroupVersion) ([]byte, error) {
+	obj, gvk, err := conversionCodecs.UniversalDeserializer().Decode(raw, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding object: %w", err)
+	}
+	if gvk.GroupVersion() == desired {
+		return raw, nil
+	}
+
+	var hub fakecrd.Hub
+	switch obj := obj.(type) {
+	case fakecrd.Hub:
+		hub = obj
+	case fakecrd.Convertible:
+		hub, err = newObject[fakecrd.Hub](fakecrd.SchemeGroupVersion.WithKind(gvk.Kind))
+		if err != nil {
+			return nil, err
+		}
+		if err := obj.ConvertTo(hub); err != nil {
+			return nil, fmt.Errorf("error converting %s to %s: %w", gvk, fakecrd.SchemeGroupVersion, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported kind %s", gvk)
+	}
+
+	out := runtime.Object(hub)
+	if desired != fakecrd.SchemeGroupVersion {
+		convertible, err := newObject[fakecrd.Convertible](desired.WithKind(gvk.Kind))
+		if err != nil {
+			return nil, err
+		}
+		if err := convertible.ConvertFrom(hub); err != nil {
+			return nil, fmt.Errorf("error converting %s to %s: %w", fakecrd.SchemeGroupVersion, desired, err)
+		}
+		out = convertible
+	}
+
+	out.GetObjectKind().SetGroupVersionKind(desired.WithKind(gvk.Kind))
+	return json.Marshal(out)
+}
+
+func newObject[T runtime.Object](gvk schema.GroupVersionKind) (T, error) {
+	var zero T
+	obj, err := conversionScheme.New(gvk)
+	if err != nil {
+		return zero, fmt.Errorf("unsupported kind %s: %w", gvk, err)
+	}
+	typed, ok := obj.(T)
+	if !ok {
+		return zero, fmt.Errorf("kind %s cannot be converted", gvk)
+	}
+	return typed, nil
+}
+
+func conversionFailed(err error) *apiextensionsv1.ConversionResponse {
+	return &apiextensionsv1.ConversionResponse{
+		Result: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: err.Error(),
+		},
+	}
+}
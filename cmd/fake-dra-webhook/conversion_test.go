@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	fakev1alpha1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1alpha1"
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+)
+
+func TestConversionHandler(t *testing.T) {
+	multiModel := &fakecrd.FakeClaimParameters{
+		TypeMeta:   metav1.TypeMeta{APIVersion: fakecrd.SchemeGroupVersion.String(), Kind: fakecrd.FakeClaimParametersKind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "multi-model"},
+		Spec: fakecrd.FakeClaimParametersSpec{
+			Count:    1,
+			Selector: &fakecrd.FakeSelector{Models: []string{fakecrd.FakeModelUltra10, fakecrd.FakeModelUltra100}},
+		},
+	}
+	singleModel := &fakev1alpha1.FakeClaimParameters{
+		TypeMeta:   metav1.TypeMeta{APIVersion: fakev1alpha1.SchemeGroupVersion.String(), Kind: fakev1alpha1.FakeClaimParametersKind},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "single-model"},
+		Spec: fakev1alpha1.FakeClaimParametersSpec{
+			Count:    2,
+			Selector: &fakev1alpha1.FakeSelector{Model: ptr.To(fakecrd.FakeModelUltra10)},
+		},
+	}
+
+	testCases := map[string]struct {
+		method      string
+		contentType string
+		desired     string
+		objects     []runtime.Object
+
+		wantCode int
+		// wantObjects are the converted objects of a successful conversion
+		wantObjects []runtime.Object
+		wantFailure bool
+	}{
+		"to the hub": {
+			desired: fakecrd.SchemeGroupVersion.String(),
+			objects: []runtime.Object{singleModel, multiModel},
+			wantObjects: []runtime.Object{
+				&fakecrd.FakeClaimParameters{
+					TypeMeta:   metav1.TypeMeta{APIVersion: fakecrd.SchemeGroupVersion.String(), Kind: fakecrd.FakeClaimParametersKind},
+					ObjectMeta: singleModel.ObjectMeta,
+					Spec: fakecrd.FakeClaimParametersSpec{
+						Count:    2,
+						Selector: &fakecrd.FakeSelector{Models: []string{fakecrd.FakeModelUltra10}},
+					},
+				},
+				multiModel,
+			},
+		},
+		"from the hub": {
+			desired: fakev1alpha1.SchemeGroupVersion.String(),
+			objects: []runtime.Object{multiModel},
+			wantObjects: []runtime.Object{
+				&fakev1alpha1.FakeClaimParameters{
+					TypeMeta: metav1.TypeMeta{APIVersion: fakev1alpha1.SchemeGroupVersion.String(), Kind: fakev1alpha1.FakeClaimParametersKind},
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "default",
+						Name:      "multi-model",
+						Annotations: map[string]string{
+							fakev1alpha1.ConversionDataAnnotation: `{"count":1,"split":0,"selector":{"models":["ULTRA_10","ULTRA_100"]}}`,
+						},
+					},
+					Spec: fakev1alpha1.FakeClaimParametersSpec{
+						Count:    1,
+						Selector: &fakev1alpha1.FakeSelector{Model: ptr.To(fakecrd.FakeModelUltra10)},
+					},
+				},
+			},
+		},
+		"unsupported kind": {
+			desired: fakecrd.SchemeGroupVersion.String(),
+			objects: []runtime.Object{
+				&metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{APIVersion: fakev1alpha1.SchemeGroupVersion.String(), Kind: "FakeDeviceQuota"}},
+			},
+			wantFailure: true,
+		},
+		"invalid desired version": {
+			desired:     "fake.resource.3-shake.com/v1/extra",
+			objects:     []runtime.Object{multiModel},
+			wantFailure: true,
+		},
+		"unsupported method": {
+			method:   http.MethodGet,
+			wantCode: http.StatusMethodNotAllowed,
+		},
+		"unsupported content type": {
+			contentType: "application/yaml",
+			wantCode:    http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			review := &apiextensionsv1.ConversionReview{
+				TypeMeta: metav1.TypeMeta{APIVersion: apiextensionsv1.SchemeGroupVersion.String(), Kind: "ConversionReview"},
+				Request: &apiextensionsv1.ConversionRequest{
+					UID:               "review-uid",
+					DesiredAPIVersion: tc.desired,
+				},
+			}
+			for _, obj := range tc.objects {
+				raw, err := json.Marshal(obj)
+				if err != nil {
+					t.Fatal(err)
+				}
+				review.Request.Objects = append(review.Request.Objects, runtime.RawExtension{Raw: raw})
+			}
+			body, err := json.Marshal(review)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			method := http.MethodPost
+			if tc.method != "" {
+				method = tc.method
+			}
+			contentType := "application/json"
+			if tc.contentType != "" {
+				contentType = tc.contentType
+			}
+			req := httptest.NewRequest(method, conversionPath, bytes.NewReader(body))
+			req.Header.Set("Content-Type", contentType)
+			rec := httptest.NewRecorder()
+			conversionHandler().ServeHTTP(rec, req)
+
+			wantCode := http.StatusOK
+			if tc.wantCode != 0 {
+				wantCode = tc.wantCode
+			}
+			if rec.Code != wantCode {
+				t.Fatalf("expected status code %d, got %d: %s", wantCode, rec.Code, rec.Body.String())
+			}
+			if wantCode != http.StatusOK {
+				return
+			}
+
+			out := &apiextensionsv1.ConversionReview{}
+			if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+				t.Fatalf("error decoding response: %v", err)
+			}
+			if out.Response == nil {
+				t.Fatal("expected a response")
+			}
+			if out.Response.UID != review.Request.UID {
+				t.Errorf("expected UID %s, got %s", review.Request.UID, out.Response.UID)
+			}
+			if tc.wantFailure {
+				if out.Response.Result.Status != metav1.StatusFailure {
+					t.Errorf("expected the conversion to fail, got %+v", out.Response.Result)
+				}
+				return
+			}
+			if out.Response.Result.Status != metav1.StatusSuccess {
+				t.Fatalf("expected the conversion to succeed, got %+v", out.Response.Result)
+			}
+
+			if len(out.Response.ConvertedObjects) != len(tc.wantObjects) {
+				t.Fatalf("expected %d converted objects, got %d", len(tc.wantObjects), len(out.Response.ConvertedObjects))
+			}
+			for i, converted := range out.Response.ConvertedObjects {
+				obj, _, err := conversionCodecs.UniversalDeserializer().Decode(converted.Raw, nil, nil)
+				if err != nil {
+					t.Fatalf("error decoding converted object: %v", err)
+				}
+				if !apiequality.Semantic.DeepEqual(obj, tc.wantObjects[i]) {
+					t.Errorf("expected converted object %+v, got %+v", tc.wantObjects[i], obj)
+				}
+			}
+		})
+	}
+}
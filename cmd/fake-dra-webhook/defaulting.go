@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/klog/v2"
+
+	fakev1alpha1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1alpha1"
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+)
+
+// jsonPatchOperation is a single RFC 6902 JSON patch operation
+type jsonPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+func defaultFakeClaimParameters(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return allowed()
+	}
+
+	// The patch has to apply to the object in the version it was sent in
+	switch req.Kind.Version {
+	case fakecrd.Version:
+		var fakeClaimParameters fakecrd.FakeClaimParameters
+		if err := json.Unmarshal(req.Object.Raw, &fakeClaimParameters); err != nil {
+			return denied(fmt.Errorf("error decoding FakeClaimParameters: %w", err))
+		}
+		spec := fakeClaimParameters.Spec.DeepCopy()
+		fakecrd.SetDefaultsFakeClaimParametersSpec(spec)
+		return patchSpec(ctx, req.Object.Raw, spec)
+	case fakev1alpha1.Version:
+		var fakeClaimParameters fakev1alpha1.FakeClaimParameters
+		if err := json.Unmarshal(req.Object.Raw, &fakeClaimParameters); err != nil {
+			return denied(fmt.Errorf("error decoding FakeClaimParameters: %w", err))
+		}
+		spec := fakeClaimParameters.Spec.DeepCopy()
+		fakev1alpha1.SetDefaultsFakeClaimParametersSpec(spec)
+		return patchSpec(ctx, req.Object.Raw, spec)
+	default:
+		return denied(fmt.Errorf("unsupported version %q", req.Kind.Version))
+	}
+}
+
+func defaultDeviceClassParameters(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return allowed()
+	}
+
+	switch req.Kind.Version {
+	case fakecrd.Version:
+		var deviceClassParameters fakecrd.DeviceClassParameters
+		if err := json.Unmarshal(req.Object.Raw, &deviceClassParameters); err != nil {
+			return denied(fmt.Errorf("error decoding DeviceClassParameters: %w", err))
+		}
+		spec := deviceClassParameters.Spec.DeepCopy()
+		fakecrd.SetDefaultsDeviceClassParametersSpec(spec)
+		return patchSpec(ctx, req.Object.Raw, spec)
+	case fakev1alpha1.Version:
+		var deviceClassParameters fakev1alpha1.DeviceClassParameters
+		if err := json.Unmarshal(req.Object.Raw, &deviceClassParameters); err != nil {
+			return denied(fmt.Errorf("error decoding DeviceClassParameters: %w", err))
+		}
+		spec := deviceClassParameters.Spec.DeepCopy()
+		fakev1alpha1.SetDefaultsDeviceClassParametersSpec(spec)
+		return patchSpec(ctx, req.Object.Raw, spec)
+	default:
+		return denied(fmt.Errorf("unsupported version %q", req.Kind.Version))
+	}
+}
+
+// patchSpec responds with a JSON patch turning the spec of the raw object into
+// the defaulted spec.
+func patchSpec(ctx context.Context, raw []byte, defaulted any) *admissionv1.AdmissionResponse {
+	logger := klog.FromContext(ctx)
+
+	var object map[string]any
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return denied(fmt.Errorf("error decoding object: %w", err))
+	}
+	desired, err := toJSONValue(defaulted)
+	if err != nil {
+		return denied(fmt.Errorf("error encoding defaulted spec: %w", err))
+	}
+
+	spec, exists := object["spec"]
+	patch := diffJSONValues("/spec", spec, desired, exists)
+	if len(patch) == 0 {
+		return allowed()
+	}
+
+	rawPatch, err := json.Marshal(patch)
+	if err != nil {
+		return denied(fmt.Errorf("error encoding JSON patch: %w", err))
+	}
+	logger.V(4).Info("Defaulting object", "patch", string(rawPatch))
+
+	patchType := admissionv1.PatchTypeJSONPatch
+	response := allowed()
+	response.Patch = rawPatch
+	response.PatchType = &patchType
+	return response
+}
+
+// diffJSONValues returns the operations adding the fields set in desired but
+// missing from current. Fields only present in current, such as fields added
+// by a newer API version, are left alone.
+func diffJSONValues(path string, current, desired any, exists bool) []jsonPatchOperation {
+	if !exists {
+		return []jsonPatchOperation{{Op: "add", Path: path, Value: desired}}
+	}
+
+	switch desired := desired.(type) {
+	case map[string]any:
+		current, ok := current.(map[string]any)
+		if !ok {
+			return []jsonPatchOperation{{Op: "replace", Path: path, Value: desired}}
+		}
+		var patch []jsonPatchOperation
+		for _, key := range sortedKeys(desired) {
+			value, exists := current[key]
+			patch = append(patch, diffJSONValues(path+"/"+escapeJSONPointer(key), value, desired[key], exists)...)
+		}
+		return patch
+	case []any:
+		current, ok := current.([]any)
+		if !ok || len(current) != len(desired) {
+			return []jsonPatchOperation{{Op: "replace", Path: path, Value: desired}}
+		}
+		var patch []jsonPatchOperation
+		for i := range desired {
+			patch = append(patch, diffJSONValues(path+"/"+strconv.Itoa(i), current[i], desired[i], true)...)
+		}
+		return patch
+	default:
+		if reflect.DeepEqual(current, desired) {
+			return nil
+		}
+		return []jsonPatchOperation{{Op: "replace", Path: path, Value: desired}}
+	}
+}
+
+// toJSONValue converts obj into the generic representation produced by
+// json.Unmarshal so that it can be compared with a decoded object.
+func toJSONValue(obj any) (any, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func escapeJSONPointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
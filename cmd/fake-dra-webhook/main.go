@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	coreclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/component-base/cli"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/component-base/featuregate"
+	"k8s.io/component-base/logs"
+	logsapi "k8s.io/component-base/logs/api/v1"
+	"k8s.io/component-base/term"
+	"k8s.io/klog/v2"
+
+	_ "k8s.io/component-base/logs/json/register" // for JSON log output support
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	shakeclientset "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned"
+)
+
+type Flags struct {
+	kubeconfig   *string
+	kubeAPIQPS   *float32
+	kubeAPIBurst *int
+
+	bindAddress       *string
+	port              *int
+	tlsCertFile       *string
+	tlsPrivateKeyFile *string
+
+	driverNames *[]string
+}
+
+type Config struct {
+	flags       *Flags
+	csconfig    *rest.Config
+	clientset   coreclientset.Interface
+	shakeclient shakeclientset.Interface
+}
+
+func main() {
+	command := NewCommand()
+	code := cli.Run(command)
+	os.Exit(code)
+}
+
+func NewCommand() *cobra.Command {
+	featureGate := featuregate.NewFeatureGate()
+	logsconfig := logsapi.NewLoggingConfiguration()
+	utilruntime.Must(logsapi.AddFeatureGates(featureGate))
+
+	cmd := &cobra.Command{
+		Use:  "fake-dra-webhook",
+		Long: "fake-dra-webhook is the webhook server defaulting, validating and converting the fake.resource.3-shake.com API and enforcing FakeDeviceQuotas",
+	}
+	flags := AddFlags(cmd, logsconfig, featureGate)
+
+	logger := klog.Background().WithName("fake-dra-webhook")
+	ctx := klog.NewContext(context.Background(), logger)
+
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		v := viper.New()
+		v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+		v.AutomaticEnv()
+		cmd.Flags().VisitAll(func(f *pflag.Flag) {
+			if !f.Changed && v.IsSet(f.Name) {
+				val := v.Get(f.Name)
+				if err := cmd.Flags().Set(f.Name, fmt.Sprintf("%v", val)); err != nil {
+					logger.Error(err, "Unable to bind environment variable to input flag", "key", f.Name, "value", val)
+				}
+			}
+		})
+		if err := logsapi.ValidateAndApply(logsconfig, featureGate); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+		defer cancel()
+
+		csconfig, err := GetClusterConfig(ctx, flags)
+		if err != nil {
+			return fmt.Errorf("error creating client configuration: %w", err)
+		}
+
+		coreclient, err := coreclientset.NewForConfig(csconfig)
+		if err != nil {
+			return fmt.Errorf("error creating core client: %w", err)
+		}
+
+		shakeclient, err := shakeclientset.NewForConfig(csconfig)
+		if err != nil {
+			return fmt.Errorf("error creating shake client: %w", err)
+		}
+
+		config := &Config{
+			flags:       flags,
+			csconfig:    csconfig,
+			clientset:   coreclient,
+			shakeclient: shakeclient,
+		}
+
+		return StartWebhookServer(ctx, config)
+	}
+
+	return cmd
+}
+
+func AddFlags(cmd *cobra.Command, logsconfig *logsapi.LoggingConfiguration, featureGate featuregate.MutableFeatureGate) *Flags {
+	flags := &Flags{}
+	sharedFlagSets := cliflag.NamedFlagSets{}
+
+	fs := sharedFlagSets.FlagSet("logging")
+	logsapi.AddFlags(logsconfig, fs)
+	logs.AddFlags(fs, logs.SkipLoggingConfigurationFlags())
+
+	fs = sharedFlagSets.FlagSet("Kubernetes client")
+	flags.kubeconfig = fs.String("kubeconfig", "", "Absolute path to the kube.config file. Either this or KUBECONFIG need to be set if the webhook is being run out of cluster.")
+	flags.kubeAPIQPS = fs.Float32("kube-api-qps", 5, "QPS to use while communicating with the kubernetes apiserver.")
+	flags.kubeAPIBurst = fs.Int("kube-api-burst", 10, "Burst to use while communicating with the kubernetes apiserver.")
+
+	fs = sharedFlagSets.FlagSet("webhook server")
+	flags.bindAddress = fs.String("bind-address", "", "The IP address on which to listen for admission requests. The default is the empty string, which means all interfaces.")
+	flags.port = fs.Int("port", 8443, "The port on which to serve admission requests over HTTPS.")
+	flags.tlsCertFile = fs.String("tls-cert-file", "/etc/fake-dra-webhook/tls/tls.crt", "File containing the x509 certificate for HTTPS. The certificate is reloaded when the file changes.")
+	flags.tlsPrivateKeyFile = fs.String("tls-private-key-file", "/etc/fake-dra-webhook/tls/tls.key", "File containing the x509 private key matching --tls-cert-file.")
+
+	fs = sharedFlagSets.FlagSet("driver")
+	flags.driverNames = fs.StringSlice("driver-names", []string{fakecrd.GroupName}, "Comma separated names of the drivers whose ResourceClasses are subject to FakeDeviceQuotas and which requests of FakeClaimParameters may allocate from.")
+
+	fs = sharedFlagSets.FlagSet("other")
+	featureGate.AddFlag(fs)
+
+	fs = cmd.PersistentFlags()
+	for _, f := range sharedFlagSets.FlagSets {
+		fs.AddFlagSet(f)
+	}
+
+	cols, _, _ := term.TerminalSize(cmd.OutOrStdout())
+	cliflag.SetUsageAndHelpFunc(cmd, sharedFlagSets, cols)
+
+	return flags
+}
+
+func GetClusterConfig(ctx context.Context, f *Flags) (*rest.Config, error) {
+	logger := klog.FromContext(ctx)
+	var csconfig *rest.Config
+
+	kubeconfigEnv := os.Getenv("KUBECONFIG")
+	if kubeconfigEnv != "" {
+		logger.Info("Found KUBECONFIG environment variable set, using that...")
+		*f.kubeconfig = kubeconfigEnv
+	}
+
+	var err error
+	if *f.kubeconfig == "" {
+		csconfig, err = rest.InClusterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("create in-cluster client configuration: %w", err)
+		}
+	} else {
+		csconfig, err = clientcmd.BuildConfigFromFlags("", *f.kubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf("create out-of-cluster client configuration from kubeconfig: %w", err)
+		}
+	}
+
+	csconfig.QPS = *f.kubeAPIQPS
+	csconfig.Burst = *f.kubeAPIBurst
+
+	return csconfig, nil
+}
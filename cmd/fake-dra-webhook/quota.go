@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+)
+
+func (v *validator) validateFakeDeviceQuota(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	logger := klog.FromContext(ctx)
+
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return allowed()
+	}
+
+	var fakeDeviceQuota fakecrd.FakeDeviceQuota
+	if err := json.Unmarshal(req.Object.Raw, &fakeDeviceQuota); err != nil {
+		return denied(fmt.Errorf("error decoding FakeDeviceQuota: %w", err))
+	}
+
+	allErrs := fakecrd.ValidateFakeDeviceQuotaSpec(&fakeDeviceQuota.Spec, field.NewPath("spec"))
+	if len(allErrs) > 0 {
+		logger.V(2).Info("Rejecting FakeDeviceQuota", "errors", allErrs.ToAggregate().Error())
+		return denied(apierrors.NewInvalid(fakecrd.SchemeGroupVersion.WithKind(fakecrd.FakeDeviceQuotaKind).GroupKind(), req.Name, allErrs))
+	}
+	return allowed()
+}
+
+// validateResourceClaim rejects ResourceClaims that would take the usage of
+// their namespace over one of its FakeDeviceQuotas. Only the spec of new
+// claims is checked, since it is immutable.
+func (v *validator) validateResourceClaim(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	logger := klog.FromContext(ctx)
+
+	if req.Operation != admissionv1.Create {
+		return allowed()
+	}
+
+	var claim resourceapi.ResourceClaim
+	if err := json.Unmarshal(req.Object.Raw, &claim); err != nil {
+		return denied(fmt.Errorf("error decoding ResourceClaim: %w", err))
+	}
+
+	allErrs, err := v.checkClaimQuotas(req.Namespace, &claim.Spec, true, field.NewPath("spec"))
+	if err != nil {
+		return denied(apierrors.NewInternalError(err))
+	}
+	if len(allErrs) > 0 {
+		logger.V(2).Info("Rejecting ResourceClaim", "errors", allErrs.ToAggregate().Error())
+		return denied(apierrors.NewInvalid(resourceapi.SchemeGroupVersion.WithKind("ResourceClaim").GroupKind(), req.Name, allErrs))
+	}
+	return allowed()
+}
+
+// validateResourceClaimTemplate rejects ResourceClaimTemplates whose claims
+// could never fit in one of the FakeDeviceQuotas of their namespace. The
+// current usage is checked when the claims are created from the template.
+func (v *validator) validateResourceClaimTemplate(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	logger := klog.FromContext(ctx)
+
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return allowed()
+	}
+
+	var template resourceapi.ResourceClaimTemplate
+	if err := json.Unmarshal(req.Object.Raw, &template); err != nil {
+		return denied(fmt.Errorf("error decoding ResourceClaimTemplate: %w", err))
+	}
+
+	allErrs, err := v.checkClaimQuotas(req.Namespace, &template.Spec.Spec, false, field.NewPath("spec", "spec"))
+	if err != nil {
+		return denied(apierrors.NewInternalError(err))
+	}
+	if len(allErrs) > 0 {
+		logger.V(2).Info("Rejecting ResourceClaimTemplate", "errors", allErrs.ToAggregate().Error())
+		return denied(apierrors.NewInvalid(resourceapi.SchemeGroupVersion.WithKind("ResourceClaimTemplate").GroupKind(), req.Name, allErrs))
+	}
+	return allowed()
+}
+
+// checkClaimQuotas checks the devices requested by a claim spec of the
+// namespace against its FakeDeviceQuotas. If includeUsed is set, the request
+// is added to the usage recorded in the status of the quotas, which only
+// covers allocated claims, and to the requests of the claims of the namespace
+// pending allocation. Claims of other drivers and claims whose parameters are
+// not FakeClaimParameters are left alone, while claims of classes that do not
+// exist yet are rejected since their driver is unknown.
+func (v *validator) checkClaimQuotas(namespace string, claimSpec *resourceapi.ResourceClaimSpec, includeUsed bool, fldPath *field.Path) (field.ErrorList, error) {
+	quotas, err := v.fakeDeviceQuotas.FakeDeviceQuotas(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing FakeDeviceQuotas from cache: %w", err)
+	}
+	if len(quotas) == 0 {
+		return nil, nil
+	}
+
+	spec, allErrs, err := v.claimParametersSpec(namespace, claimSpec, fldPath)
+	if err != nil || len(allErrs) > 0 || spec == nil {
+		return allErrs, err
+	}
+
+	var pending *fakecrd.FakeDeviceUsage
+	if includeUsed {
+		pending, err = v.pendingUsage(namespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, quota := range quotas {
+		var used *fakecrd.FakeDeviceUsage
+		if includeUsed {
+			used = quota.Status.Used.DeepCopy()
+			used.Add(pending)
+		}
+		if violations := fakeDeviceQuotaViolations(&quota.Spec, spec, used); len(violations) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath,
+				fmt.Sprintf("exceeds FakeDeviceQuota %s: %s", quota.Name, strings.Join(violations, ", "))))
+		}
+	}
+	return allErrs, nil
+}
+
+// claimParametersSpec returns the defaulted FakeClaimParameters spec a claim
+// spec of the namespace requests its devices with, nil if the claim is not
+// accounted for in FakeDeviceQuotas.
+func (v *validator) claimParametersSpec(namespace string, claimSpec *resourceapi.ResourceClaimSpec, fldPath *field.Path) (*fakecrd.FakeClaimParametersSpec, field.ErrorList, error) {
+	class, err := v.resourceClasses.Get(claimSpec.ResourceClassName)
+	if apierrors.IsNotFound(err) {
+		// Whether the claim is one of the fake drivers cannot be told
+		// without its class, letting it through would bypass the quotas
+		return nil, field.ErrorList{field.Forbidden(fldPath.Child("resourceClassName"),
+			fmt.Sprintf("ResourceClass %s must exist before claims of it are created in a namespace with FakeDeviceQuotas", claimSpec.ResourceClassName))}, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting ResourceClass from cache: %w", err)
+	}
+	if !v.driverNames.Has(class.DriverName) {
+		return nil, nil, nil
+	}
+
+	spec := fakecrd.DefaultFakeClaimParametersSpec()
+	if ref := claimSpec.ParametersRef; ref != nil {
+		if ref.APIGroup != fakecrd.GroupName || ref.Kind != fakecrd.FakeClaimParametersKind {
+			return nil, nil, nil
+		}
+		fakeClaimParameters, err := v.fakeClaimParameters.FakeClaimParameters(namespace).Get(ref.Name)
+		if apierrors.IsNotFound(err) {
+			return nil, field.ErrorList{field.Forbidden(fldPath.Child("parametersRef"),
+				fmt.Sprintf("FakeClaimParameters %s must exist before claims referencing it are created in a namespace with FakeDeviceQuotas", ref.Name))}, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting FakeClaimParameters from cache: %w", err)
+		}
+		spec = fakeClaimParameters.Spec.DeepCopy()
+		fakecrd.SetDefaultsFakeClaimParametersSpec(spec)
+	}
+	return spec, nil, nil
+}
+
+// pendingUsage sums up the devices requested by the claims of the namespace
+// that were admitted but are not allocated yet, which the usage in the status
+// of the FakeDeviceQuotas does not cover. Claims created concurrently may
+// still both be admitted before either reaches the cache.
+func (v *validator) pendingUsage(namespace string) (*fakecrd.FakeDeviceUsage, error) {
+	objs, err := v.claims.ByIndex(cache.NamespaceIndex, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error listing ResourceClaims from cache: %w", err)
+	}
+
+	pending := &fakecrd.FakeDeviceUsage{}
+	for _, obj := range objs {
+		claim, ok := obj.(*resourceapi.ResourceClaim)
+		if !ok || claim.Status.Allocation != nil || claim.DeletionTimestamp != nil {
+			continue
+		}
+		spec, allErrs, err := v.claimParametersSpec(namespace, &claim.Spec, field.NewPath("spec"))
+		if err != nil {
+			return nil, err
+		}
+		if len(allErrs) > 0 || spec == nil {
+			// Claims whose class or parameters are gone cannot be
+			// allocated until they come back
+			continue
+		}
+		pending.Add(fakecrd.RequestedUsage(spec))
+	}
+	return pending, nil
+}
+
+// checkFakeClaimParametersQuotas checks that a single claim using the
+// defaulted spec fits in the FakeDeviceQuotas of the namespace.
+func (v *validator) checkFakeClaimParametersQuotas(namespace string, spec *fakecrd.FakeClaimParametersSpec, fldPath *field.Path) (field.ErrorList, error) {
+	quotas, err := v.fakeDeviceQuotas.FakeDeviceQuotas(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing FakeDeviceQuotas from cache: %w", err)
+	}
+
+	var allErrs field.ErrorList
+	for _, quota := range quotas {
+		if violations := fakeDeviceQuotaViolations(&quota.Spec, spec, nil); len(violations) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath,
+				fmt.Sprintf("exceeds FakeDeviceQuota %s: %s", quota.Name, strings.Join(violations, ", "))))
+		}
+	}
+	return allErrs, nil
+}
+
+// fakeDeviceQuotaViolations describes how a claim using the defaulted spec
+// exceeds the quota once added to the used devices, which may be nil.
+func fakeDeviceQuotaViolations(quota *fakecrd.FakeDeviceQuotaSpec, spec *fakecrd.FakeClaimParametersSpec, used *fakecrd.FakeDeviceUsage) []string {
+	requested := fakecrd.RequestedUsage(spec)
+	total := &fakecrd.FakeDeviceUsage{}
+	if used != nil {
+		total.Add(used)
+	}
+	total.Add(requested)
+
+	var violations []string
+	if quota.Devices != nil && requested.Devices > 0 && total.Devices > *quota.Devices {
+		violations = append(violations, fmt.Sprintf("requested %d devices with %d in use, limited to %d", requested.Devices, total.Devices-requested.Devices, *quota.Devices))
+	}
+	if quota.Partitions != nil && requested.Partitions > 0 && total.Partitions > *quota.Partitions {
+		violations = append(violations, fmt.Sprintf("requested %d partitions with %d in use, limited to %d", requested.Partitions, total.Partitions-requested.Partitions, *quota.Partitions))
+	}
+	if len(quota.Models) > 0 {
+		for _, request := range spec.GetRequests() {
+			if request.Selector == nil || len(request.Selector.Models) == 0 {
+				violations = append(violations, fmt.Sprintf("request %s must select models among %s", request.Name, strings.Join(quota.Models, ", ")))
+				continue
+			}
+			for _, model := range request.Selector.Models {
+				if !slices.Contains(quota.Models, model) {
+					violations = append(violations, fmt.Sprintf("request %s selects model %s, limited to %s", request.Name, model, strings.Join(quota.Models, ", ")))
+				}
+			}
+		}
+	}
+	return violations
+}
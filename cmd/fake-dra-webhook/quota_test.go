@@ -0,0 +1,241 @@
+package main
+
+import (
+	"testing"
+
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	resourcelisters "k8s.io/client-go/listers/resource/v1alpha2"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	fakelisters "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/listers/fake/v1beta1"
+)
+
+const (
+	testNamespace  = "test"
+	testClass      = "fake.example.com"
+	otherClass     = "other.example.com"
+	testDriverName = fakecrd.GroupName
+)
+
+func TestCheckClaimQuotas(t *testing.T) {
+	testCases := map[string]struct {
+		quota fakecrd.FakeDeviceQuota
+		// claims are already admitted in the namespace
+		claims []*resourceapi.ResourceClaim
+		// parameters are the FakeClaimParameters of the namespace
+		parameters []*fakecrd.FakeClaimParameters
+		claimSpec  resourceapi.ResourceClaimSpec
+		// template checks the spec of a ResourceClaimTemplate, which is not
+		// checked against the usage
+		template bool
+
+		wantErr bool
+	}{
+		"within quota": {
+			quota:     newQuota(fakecrd.FakeDeviceQuotaSpec{Devices: ptr.To(2)}, nil),
+			claimSpec: claimSpec(testClass, ""),
+		},
+		"used devices exceeded": {
+			quota:     newQuota(fakecrd.FakeDeviceQuotaSpec{Devices: ptr.To(2)}, &fakecrd.FakeDeviceUsage{Devices: 2, Partitions: 2}),
+			claimSpec: claimSpec(testClass, ""),
+			wantErr:   true,
+		},
+		"pending and used devices exceeded": {
+			quota: newQuota(fakecrd.FakeDeviceQuotaSpec{Devices: ptr.To(3)}, &fakecrd.FakeDeviceUsage{Devices: 1, Partitions: 1}),
+			claims: []*resourceapi.ResourceClaim{
+				newClaim("pending", claimSpec(testClass, "two"), false),
+			},
+			parameters: []*fakecrd.FakeClaimParameters{
+				newParameters("two", fakecrd.FakeClaimParametersSpec{Count: 2}),
+			},
+			claimSpec: claimSpec(testClass, ""),
+			wantErr:   true,
+		},
+		"allocated claims only counted as used": {
+			quota: newQuota(fakecrd.FakeDeviceQuotaSpec{Devices: ptr.To(3)}, &fakecrd.FakeDeviceUsage{Devices: 2, Partitions: 2}),
+			claims: []*resourceapi.ResourceClaim{
+				newClaim("allocated", claimSpec(testClass, "two"), true),
+			},
+			parameters: []*fakecrd.FakeClaimParameters{
+				newParameters("two", fakecrd.FakeClaimParametersSpec{Count: 2}),
+			},
+			claimSpec: claimSpec(testClass, ""),
+		},
+		"pending claims of other drivers not counted": {
+			quota: newQuota(fakecrd.FakeDeviceQuotaSpec{Devices: ptr.To(1)}, nil),
+			claims: []*resourceapi.ResourceClaim{
+				newClaim("other", claimSpec(otherClass, ""), false),
+			},
+			claimSpec: claimSpec(testClass, ""),
+		},
+		"partitions of split devices exceeded": {
+			quota: newQuota(fakecrd.FakeDeviceQuotaSpec{Partitions: ptr.To(3)}, nil),
+			parameters: []*fakecrd.FakeClaimParameters{
+				newParameters("split", fakecrd.FakeClaimParametersSpec{Count: 2, Split: 2}),
+			},
+			claimSpec: claimSpec(testClass, "split"),
+			wantErr:   true,
+		},
+		"allowed model": {
+			quota: newQuota(fakecrd.FakeDeviceQuotaSpec{Models: []string{fakecrd.FakeModelUltra10}}, nil),
+			parameters: []*fakecrd.FakeClaimParameters{
+				newParameters("ultra10", fakecrd.FakeClaimParametersSpec{Selector: &fakecrd.FakeSelector{Models: []string{fakecrd.FakeModelUltra10}}}),
+			},
+			claimSpec: claimSpec(testClass, "ultra10"),
+		},
+		"model outside the allowlist": {
+			quota: newQuota(fakecrd.FakeDeviceQuotaSpec{Models: []string{fakecrd.FakeModelUltra10}}, nil),
+			parameters: []*fakecrd.FakeClaimParameters{
+				newParameters("ultra100", fakecrd.FakeClaimParametersSpec{Selector: &fakecrd.FakeSelector{Models: []string{fakecrd.FakeModelUltra100}}}),
+			},
+			claimSpec: claimSpec(testClass, "ultra100"),
+			wantErr:   true,
+		},
+		"unset selector with a model allowlist": {
+			quota:     newQuota(fakecrd.FakeDeviceQuotaSpec{Models: []string{fakecrd.FakeModelUltra10}}, nil),
+			claimSpec: claimSpec(testClass, ""),
+			wantErr:   true,
+		},
+		"unknown class": {
+			quota:     newQuota(fakecrd.FakeDeviceQuotaSpec{Devices: ptr.To(1)}, nil),
+			claimSpec: claimSpec("missing.example.com", ""),
+			wantErr:   true,
+		},
+		"missing parameters": {
+			quota:     newQuota(fakecrd.FakeDeviceQuotaSpec{Devices: ptr.To(1)}, nil),
+			claimSpec: claimSpec(testClass, "missing"),
+			wantErr:   true,
+		},
+		"other driver": {
+			quota:     newQuota(fakecrd.FakeDeviceQuotaSpec{Devices: ptr.To(0)}, nil),
+			claimSpec: claimSpec(otherClass, ""),
+		},
+		"foreign parameters": {
+			quota: newQuota(fakecrd.FakeDeviceQuotaSpec{Devices: ptr.To(0)}, nil),
+			claimSpec: resourceapi.ResourceClaimSpec{
+				ResourceClassName: testClass,
+				ParametersRef: &resourceapi.ResourceClaimParametersReference{
+					APIGroup: "example.com",
+					Kind:     "OtherParameters",
+					Name:     "other",
+				},
+			},
+		},
+		"template within quota despite usage": {
+			quota:     newQuota(fakecrd.FakeDeviceQuotaSpec{Devices: ptr.To(1)}, &fakecrd.FakeDeviceUsage{Devices: 1, Partitions: 1}),
+			claimSpec: claimSpec(testClass, ""),
+			template:  true,
+		},
+		"template that can never fit": {
+			quota: newQuota(fakecrd.FakeDeviceQuotaSpec{Devices: ptr.To(1)}, nil),
+			parameters: []*fakecrd.FakeClaimParameters{
+				newParameters("two", fakecrd.FakeClaimParametersSpec{Count: 2}),
+			},
+			claimSpec: claimSpec(testClass, "two"),
+			template:  true,
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			v := newTestValidator(t, &tc.quota, tc.claims, tc.parameters)
+			allErrs, err := v.checkClaimQuotas(testNamespace, &tc.claimSpec, !tc.template, field.NewPath("spec"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tc.wantErr && len(allErrs) == 0 {
+				t.Error("expected the claim to be rejected")
+			}
+			if !tc.wantErr && len(allErrs) > 0 {
+				t.Errorf("unexpected rejection: %v", allErrs.ToAggregate())
+			}
+		})
+	}
+}
+
+// newTestValidator returns a validator whose caches hold a fake and another
+// ResourceClass together with the given objects of the test namespace
+func newTestValidator(t *testing.T, quota *fakecrd.FakeDeviceQuota, claims []*resourceapi.ResourceClaim, parameters []*fakecrd.FakeClaimParameters) *validator {
+	t.Helper()
+	namespaceIndexers := cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}
+
+	claimIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+		parametersRefIndex:   parametersRefIndexFunc,
+	})
+	classIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	parametersIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, namespaceIndexers)
+	quotaIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, namespaceIndexers)
+
+	add := func(indexer cache.Indexer, obj any) {
+		if err := indexer.Add(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add(classIndexer, &resourceapi.ResourceClass{ObjectMeta: metav1.ObjectMeta{Name: testClass}, DriverName: testDriverName})
+	add(classIndexer, &resourceapi.ResourceClass{ObjectMeta: metav1.ObjectMeta{Name: otherClass}, DriverName: "other.example.com"})
+	add(quotaIndexer, quota)
+	for _, claim := range claims {
+		add(claimIndexer, claim)
+	}
+	for _, params := range parameters {
+		add(parametersIndexer, params)
+	}
+
+	return &validator{
+		claims:              claimIndexer,
+		resourceClasses:     resourcelisters.NewResourceClassLister(classIndexer),
+		fakeClaimParameters: fakelisters.NewFakeClaimParametersLister(parametersIndexer),
+		fakeDeviceQuotas:    fakelisters.NewFakeDeviceQuotaLister(quotaIndexer),
+		driverNames:         sets.New(testDriverName),
+	}
+}
+
+func newQuota(spec fakecrd.FakeDeviceQuotaSpec, used *fakecrd.FakeDeviceUsage) fakecrd.FakeDeviceQuota {
+	quota := fakecrd.FakeDeviceQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "quota"},
+		Spec:       spec,
+	}
+	if used != nil {
+		quota.Status.Used = *used
+	}
+	return quota
+}
+
+func newParameters(name string, spec fakecrd.FakeClaimParametersSpec) *fakecrd.FakeClaimParameters {
+	return &fakecrd.FakeClaimParameters{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Spec:       spec,
+	}
+}
+
+// claimSpec returns the spec of a claim of the class, referring to the
+// FakeClaimParameters of the given name if not empty
+func claimSpec(class, parameters string) resourceapi.ResourceClaimSpec {
+	spec := resourceapi.ResourceClaimSpec{ResourceClassName: class}
+	if parameters != "" {
+		spec.ParametersRef = &resourceapi.ResourceClaimParametersReference{
+			APIGroup: fakecrd.GroupName,
+			Kind:     fakecrd.FakeClaimParametersKind,
+			Name:     parameters,
+		}
+	}
+	return spec
+}
+
+func newClaim(name string, spec resourceapi.ResourceClaimSpec, allocated bool) *resourceapi.ResourceClaim {
+	claim := &resourceapi.ResourceClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Spec:       spec,
+	}
+	if allocated {
+		claim.Status.Allocation = &resourceapi.AllocationResult{}
+	}
+	return claim
+}
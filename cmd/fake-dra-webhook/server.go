@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	shakeinformers "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/informers/externalversions"
+)
+
+const (
+	shutdownTimeout = 10 * time.Second
+)
+
+func StartWebhookServer(ctx context.Context, config *Config) error {
+	logger := klog.FromContext(ctx)
+
+	certificate, err := newCertificateReloader(*config.flags.tlsCertFile, *config.flags.tlsPrivateKeyFile)
+	if err != nil {
+		return fmt.Errorf("error loading TLS certificate: %w", err)
+	}
+
+	// ResourceClaims are cached and indexed on their parameters so that
+	// admission does not need to list them from the API server
+	informerFactory := informers.NewSharedInformerFactory(config.clientset, 0)
+	claimInformer := informerFactory.Resource().V1alpha2().ResourceClaims().Informer()
+	if err := claimInformer.AddIndexers(cache.Indexers{parametersRefIndex: parametersRefIndexFunc}); err != nil {
+		return fmt.Errorf("error adding ResourceClaim indexer: %w", err)
+	}
+
+	resourceClasses := informerFactory.Resource().V1alpha2().ResourceClasses().Lister()
+
+	// FakeDeviceQuotas and the FakeClaimParameters they are checked against
+	// are cached as well
+	shakeInformerFactory := shakeinformers.NewSharedInformerFactory(config.shakeclient, 0)
+	fakeClaimParameters := shakeInformerFactory.Fake().V1beta1().FakeClaimParameters().Lister()
+	fakeDeviceQuotas := shakeInformerFactory.Fake().V1beta1().FakeDeviceQuotas().Lister()
+
+	validator := &validator{
+		claims:              claimInformer.GetIndexer(),
+		resourceClasses:     resourceClasses,
+		fakeClaimParameters: fakeClaimParameters,
+		fakeDeviceQuotas:    fakeDeviceQuotas,
+		driverNames:         sets.New(*config.flags.driverNames...),
+	}
+
+	// Admission is only served once the caches are synced, while conversion
+	// and health checks are served right away: once the CRDs are converted
+	// by this server, listing them to sync the caches needs /convert.
+	var synced atomic.Bool
+	whenSynced := func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !synced.Load() {
+				http.Error(w, "informer caches are not synced yet", http.StatusServiceUnavailable)
+				return
+			}
+			handler.ServeHTTP(w, r)
+		})
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/validate-fakeclaimparameters", whenSynced(admissionHandler(validator.validateFakeClaimParameters)))
+	mux.Handle("/validate-deviceclassparameters", whenSynced(admissionHandler(validator.validateDeviceClassParameters)))
+	mux.Handle("/validate-fakedevicequotas", whenSynced(admissionHandler(validator.validateFakeDeviceQuota)))
+	mux.Handle("/validate-resourceclaims", whenSynced(admissionHandler(validator.validateResourceClaim)))
+	mux.Handle("/validate-resourceclaimtemplates", whenSynced(admissionHandler(validator.validateResourceClaimTemplate)))
+	mux.Handle("/mutate-fakeclaimparameters", whenSynced(admissionHandler(defaultFakeClaimParameters)))
+	mux.Handle("/mutate-deviceclassparameters", whenSynced(admissionHandler(defaultDeviceClassParameters)))
+	mux.Handle(conversionPath, conversionHandler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !synced.Load() {
+			http.Error(w, "informer caches are not synced yet", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := &http.Server{
+		Addr:    net.JoinHostPort(*config.flags.bindAddress, strconv.Itoa(*config.flags.port)),
+		Handler: mux,
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: certificate.GetCertificate,
+		},
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Info("Starting webhook server", "address", server.Addr)
+		if err := server.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	informerFactory.Start(ctx.Done())
+	defer informerFactory.Shutdown()
+	shakeInformerFactory.Start(ctx.Done())
+	defer shakeInformerFactory.Shutdown()
+
+	syncErrCh := make(chan error, 1)
+	go func() {
+		syncErrCh <- waitForCacheSync(ctx, informerFactory, shakeInformerFactory)
+	}()
+
+	for done := false; !done; {
+		select {
+		case err := <-errCh:
+			return fmt.Errorf("webhook server failed: %w", err)
+		case err := <-syncErrCh:
+			if err != nil && ctx.Err() == nil {
+				return err
+			}
+			if err == nil {
+				logger.Info("Informer caches synced, serving admission requests")
+				synced.Store(true)
+			}
+			syncErrCh = nil
+		case <-ctx.Done():
+			done = true
+		}
+	}
+
+	logger.Info("Shutting down webhook server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// cacheSyncWaiter is implemented by the informer factories of both clientsets
+type cacheSyncWaiter interface {
+	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
+}
+
+// waitForCacheSync waits until the caches of all started informers are
+// synced, or ctx is done.
+func waitForCacheSync(ctx context.Context, factories ...cacheSyncWaiter) error {
+	for _, factory := range factories {
+		for informerType, synced := range factory.WaitForCacheSync(ctx.Done()) {
+			if !synced {
+				return fmt.Errorf("error syncing informer cache for %v", informerType)
+			}
+		}
+	}
+	return nil
+}
+
+// certificateReloader serves the certificate from the given files and reloads
+// it whenever the certificate file is modified, e.g. by cert-manager.
+type certificateReloader struct {
+	sync.Mutex
+	certFile    string
+	keyFile     string
+	modTime     time.Time
+	certificate *tls.Certificate
+}
+
+func newCertificateReloader(certFile, keyFile string) (*certificateReloader, error) {
+	r := &certificateReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	if _, err := r.GetCertificate(nil); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.Lock()
+	defer r.Unlock()
+
+	info, err := os.Stat(r.certFile)
+	if err != nil {
+		return nil, fmt.Errorf("error checking certificate file: %w", err)
+	}
+	if r.certificate != nil && info.ModTime().Equal(r.modTime) {
+		return r.certificate, nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		if r.certificate != nil {
+			// Keep serving the previous certificate while the files are
+			// being rotated
+			klog.ErrorS(err, "Error reloading TLS certificate, keeping the previous one")
+			return r.certificate, nil
+		}
+		return nil, fmt.Errorf("error loading key pair: %w", err)
+	}
+	klog.InfoS("Loaded TLS certificate", "certFile", r.certFile)
+	r.certificate = &certificate
+	r.modTime = info.ModTime()
+	return r.certificate, nil
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	resourcelisters "k8s.io/client-go/listers/resource/v1alpha2"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	fakev1alpha1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1alpha1"
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	fakelisters "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/listers/fake/v1beta1"
+)
+
+const (
+	// parametersRefIndex is the name of the ResourceClaim index keyed on the
+	// claim parameters they refer to
+	parametersRefIndex = "parametersRef"
+)
+
+type validator struct {
+	claims              cache.Indexer
+	resourceClasses     resourcelisters.ResourceClassLister
+	fakeClaimParameters fakelisters.FakeClaimParametersLister
+	fakeDeviceQuotas    fakelisters.FakeDeviceQuotaLister
+	// driverNames are the drivers served alongside the webhook
+	driverNames sets.Set[string]
+}
+
+func (v *validator) validateFakeClaimParameters(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	logger := klog.FromContext(ctx)
+
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return allowed()
+	}
+
+	specPath := field.NewPath("spec")
+	fakeClaimParameters, allErrs, err := decodeFakeClaimParameters(req.Kind.Version, req.Object.Raw, specPath)
+	if err != nil {
+		return denied(fmt.Errorf("error decoding FakeClaimParameters: %w", err))
+	}
+
+	if req.Operation == admissionv1.Update {
+		oldFakeClaimParameters, _, err := decodeFakeClaimParameters(req.Kind.Version, req.OldObject.Raw, specPath)
+		if err != nil {
+			return denied(fmt.Errorf("error decoding old FakeClaimParameters: %w", err))
+		}
+
+		// Objects stored before defaulting was enabled are defaulted on
+		// their next update, which does not change their effective spec
+		oldSpec, newSpec := oldFakeClaimParameters.Spec.DeepCopy(), fakeClaimParameters.Spec.DeepCopy()
+		fakecrd.SetDefaultsFakeClaimParametersSpec(oldSpec)
+		fakecrd.SetDefaultsFakeClaimParametersSpec(newSpec)
+		if !apiequality.Semantic.DeepEqual(oldSpec, newSpec) {
+			claims, err := v.allocatedClaimsReferencing(req.Namespace, fakecrd.FakeClaimParametersKind, req.Name)
+			if err != nil {
+				return denied(apierrors.NewInternalError(err))
+			}
+			if len(claims) > 0 {
+				allErrs = append(allErrs, field.Forbidden(specPath,
+					fmt.Sprintf("cannot be changed while referenced by allocated ResourceClaims: %s", strings.Join(claims, ", "))))
+			}
+		}
+	}
+
+	for i, request := range fakeClaimParameters.Spec.Requests {
+		if request.DriverName != "" && !v.driverNames.Has(request.DriverName) {
+			allErrs = append(allErrs, field.NotSupported(specPath.Child("requests").Index(i).Child("driverName"), request.DriverName, sets.List(v.driverNames)))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		// Claims using the parameters are checked against the current
+		// usage when they are created, here they only need to fit at all
+		spec := fakeClaimParameters.Spec.DeepCopy()
+		fakecrd.SetDefaultsFakeClaimParametersSpec(spec)
+		quotaErrs, err := v.checkFakeClaimParametersQuotas(req.Namespace, spec, specPath)
+		if err != nil {
+			return denied(apierrors.NewInternalError(err))
+		}
+		allErrs = append(allErrs, quotaErrs...)
+	}
+
+	if len(allErrs) > 0 {
+		logger.V(2).Info("Rejecting FakeClaimParameters", "errors", allErrs.ToAggregate().Error())
+		return denied(apierrors.NewInvalid(fakecrd.SchemeGroupVersion.WithKind(fakecrd.FakeClaimParametersKind).GroupKind(), req.Name, allErrs))
+	}
+	return allowed()
+}
+
+func (v *validator) validateDeviceClassParameters(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	logger := klog.FromContext(ctx)
+
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return allowed()
+	}
+
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	switch req.Kind.Version {
+	case fakecrd.Version:
+		var deviceClassParameters fakecrd.DeviceClassParameters
+		if err := json.Unmarshal(req.Object.Raw, &deviceClassParameters); err != nil {
+			return denied(fmt.Errorf("error decoding DeviceClassParameters: %w", err))
+		}
+		allErrs = fakecrd.ValidateDeviceClassParametersSpec(&deviceClassParameters.Spec, specPath)
+	case fakev1alpha1.Version:
+		var deviceClassParameters fakev1alpha1.DeviceClassParameters
+		if err := json.Unmarshal(req.Object.Raw, &deviceClassParameters); err != nil {
+			return denied(fmt.Errorf("error decoding DeviceClassParameters: %w", err))
+		}
+		allErrs = fakev1alpha1.ValidateDeviceClassParametersSpec(&deviceClassParameters.Spec, specPath)
+	default:
+		return denied(fmt.Errorf("unsupported version %q", req.Kind.Version))
+	}
+
+	if len(allErrs) > 0 {
+		logger.V(2).Info("Rejecting DeviceClassParameters", "errors", allErrs.ToAggregate().Error())
+		return denied(apierrors.NewInvalid(fakecrd.SchemeGroupVersion.WithKind(fakecrd.DeviceClassParametersKind).GroupKind(), req.Name, allErrs))
+	}
+	return allowed()
+}
+
+// decodeFakeClaimParameters decodes a FakeClaimParameters object of the given
+// version and converts it to the hub version. The spec is validated in the
+// version it was sent in so that errors refer to the fields of the request.
+func decodeFakeClaimParameters(version string, raw []byte, fldPath *field.Path) (*fakecrd.FakeClaimParameters, field.ErrorList, error) {
+	switch version {
+	case fakecrd.Version:
+		fakeClaimParameters := &fakecrd.FakeClaimParameters{}
+		if err := json.Unmarshal(raw, fakeClaimParameters); err != nil {
+			return nil, nil, err
+		}
+		return fakeClaimParameters, fakecrd.ValidateFakeClaimParametersSpec(&fakeClaimParameters.Spec, fldPath), nil
+	case fakev1alpha1.Version:
+		v1alpha1FakeClaimParameters := &fakev1alpha1.FakeClaimParameters{}
+		if err := json.Unmarshal(raw, v1alpha1FakeClaimParameters); err != nil {
+			return nil, nil, err
+		}
+		allErrs := fakev1alpha1.ValidateFakeClaimParametersSpec(&v1alpha1FakeClaimParameters.Spec, fldPath)
+		fakeClaimParameters := &fakecrd.FakeClaimParameters{}
+		if err := v1alpha1FakeClaimParameters.ConvertTo(fakeClaimParameters); err != nil {
+			return nil, nil, err
+		}
+		return fakeClaimParameters, allErrs, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported version %q", version)
+	}
+}
+
+// allocatedClaimsReferencing returns the names of the allocated ResourceClaims
+// using the named claim parameters.
+func (v *validator) allocatedClaimsReferencing(namespace, kind, name string) ([]string, error) {
+	objs, err := v.claims.ByIndex(parametersRefIndex, parametersRefIndexKey(namespace, fakecrd.GroupName, kind, name))
+	if err != nil {
+		return nil, fmt.Errorf("error getting ResourceClaims from cache: %w", err)
+	}
+
+	var claims []string
+	for _, obj := range objs {
+		claim := obj.(*resourceapi.ResourceClaim)
+		if claim.Status.Allocation != nil {
+			claims = append(claims, claim.Name)
+		}
+	}
+	sort.Strings(claims)
+	return claims, nil
+}
+
+func parametersRefIndexFunc(obj any) ([]string, error) {
+	claim, ok := obj.(*resourceapi.ResourceClaim)
+	if !ok || claim.Spec.ParametersRef == nil {
+		return nil, nil
+	}
+	ref := claim.Spec.ParametersRef
+	return []string{parametersRefIndexKey(claim.Namespace, ref.APIGroup, ref.Kind, ref.Name)}, nil
+}
+
+func parametersRefIndexKey(namespace, apiGroup, kind, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", namespace, apiGroup, kind, name)
+}
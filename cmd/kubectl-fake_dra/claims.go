@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cliflag "k8s.io/component-base/cli/flag"
+)
+
+// States of claims
+const (
+	claimStateAllocated    = "Allocated"
+	claimStatePending      = "Pending"
+	claimStateDeallocating = "Deallocating"
+)
+
+// Claim is a claim of the drivers with the devices allocated to it
+type Claim struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Class     string `json:"class"`
+	State     string `json:"state"`
+	// Parameters is the kind/name of the claim parameters
+	Parameters string `json:"parameters,omitempty"`
+	// Pods are the names of the Pods the claim is reserved for
+	Pods    []string          `json:"pods,omitempty"`
+	Devices []AllocatedDevice `json:"devices,omitempty"`
+}
+
+func NewClaimsCommand(config *Config, sharedFlagSets cliflag.NamedFlagSets) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "claims [NAME...]",
+		Short: "Show the devices allocated to claims",
+		Long:  "claims shows the devices allocated to the ResourceClaims of the drivers, the partitions they are split into and the Pods consuming them",
+		Example: `  # Show the claims of the current namespace
+  kubectl fake-dra claims
+
+  # Show the claims of all namespaces as JSON
+  kubectl fake-dra claims -A -o json`,
+	}
+
+	namedFlagSets := cliflag.NamedFlagSets{}
+	fs := namedFlagSets.FlagSet("claims")
+	allNamespaces := fs.BoolP("all-namespaces", "A", false, "Show the claims of all namespaces.")
+	cmd.Flags().AddFlagSet(fs)
+	setUsageAndHelpFunc(cmd, sharedFlagSets, namedFlagSets)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		namespace := config.namespace
+		if *allNamespaces {
+			namespace = metav1.NamespaceAll
+		}
+		resourceClaims, err := listResourceClaims(ctx, config, namespace)
+		if err != nil {
+			return err
+		}
+		classDrivers, err := listClassDrivers(ctx, config)
+		if err != nil {
+			return err
+		}
+		_, published, err := listPublishedDevices(ctx, config, "")
+		if err != nil {
+			return err
+		}
+
+		names := map[string]bool{}
+		for _, name := range args {
+			names[name] = true
+		}
+		var claims []Claim
+		for i := range resourceClaims {
+			resourceClaim := &resourceClaims[i]
+			if len(names) > 0 && !names[resourceClaim.Name] {
+				continue
+			}
+			if !config.driverNames.Has(classDrivers[resourceClaim.Spec.ResourceClassName]) && !config.driverNames.Has(resourceClaim.Status.DriverName) {
+				continue
+			}
+			claims = append(claims, newClaim(resourceClaim, allocatedDevices(ctx, config, resourceClaim, published)))
+		}
+
+		if config.output == outputJSON {
+			return printJSON(cmd.OutOrStdout(), claims)
+		}
+		return printClaims(cmd, claims, *allNamespaces)
+	}
+
+	return cmd
+}
+
+func newClaim(resourceClaim *resourceapi.ResourceClaim, devices []AllocatedDevice) Claim {
+	claim := Claim{
+		Namespace: resourceClaim.Namespace,
+		Name:      resourceClaim.Name,
+		Class:     resourceClaim.Spec.ResourceClassName,
+		State:     claimStatePending,
+		Devices:   devices,
+	}
+	switch {
+	case resourceClaim.Status.DeallocationRequested:
+		claim.State = claimStateDeallocating
+	case resourceClaim.Status.Allocation != nil:
+		claim.State = claimStateAllocated
+	}
+	if ref := resourceClaim.Spec.ParametersRef; ref != nil {
+		claim.Parameters = ref.Kind + "/" + ref.Name
+	}
+	for _, consumer := range resourceClaim.Status.ReservedFor {
+		if consumer.APIGroup == "" && consumer.Resource == "pods" {
+			claim.Pods = append(claim.Pods, consumer.Name)
+		}
+	}
+	return claim
+}
+
+func printClaims(cmd *cobra.Command, claims []Claim, allNamespaces bool) error {
+	headers := []string{"CLAIM", "STATE", "NODE", "REQUEST", "DEVICE", "MODEL", "SPLIT", "PARTITIONS", "PODS"}
+	if allNamespaces {
+		headers = append([]string{"NAMESPACE"}, headers...)
+	}
+	t := newTable(cmd.OutOrStdout(), headers...)
+	row := func(claim Claim, cells ...string) {
+		if allNamespaces {
+			cells = append([]string{claim.Namespace}, cells...)
+		}
+		t.row(cells...)
+	}
+
+	for _, claim := range claims {
+		pods := strings.Join(claim.Pods, ",")
+		if len(claim.Devices) == 0 {
+			row(claim, claim.Name, claim.State, "", "", "", "", "", "", pods)
+			continue
+		}
+		for _, device := range claim.Devices {
+			row(claim, claim.Name, claim.State, device.Node, device.Request, deviceID(device.UUID, device.Name),
+				device.Model, fmt.Sprint(device.Split), strings.Join(device.Partitions, ","), pods)
+		}
+	}
+	return t.flush()
+}
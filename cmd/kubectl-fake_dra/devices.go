@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/klog/v2"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	"github.com/toVersus/fake-dra-driver/pkg/deviceuuid"
+	"github.com/toVersus/fake-dra-driver/pkg/vendorparameters"
+)
+
+// deviceKey identifies a device published by a driver for a node
+type deviceKey struct {
+	driver string
+	node   string
+	name   string
+}
+
+// PublishedDevice is a device published in the ResourceSlice of a node
+type PublishedDevice struct {
+	Node   string `json:"node"`
+	Driver string `json:"driver"`
+	Name   string `json:"name"`
+	UUID   string `json:"uuid,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Model  string `json:"model,omitempty"`
+	// Attributes holds the type specific attributes of the device
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// AllocatedDevice is a device allocated to a claim
+type AllocatedDevice struct {
+	Node    string `json:"node"`
+	Driver  string `json:"driver"`
+	Request string `json:"request"`
+	Name    string `json:"name"`
+	UUID    string `json:"uuid,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Model   string `json:"model,omitempty"`
+	Split   int    `json:"split"`
+	// Partitions are the UUIDs of the partitions the device is split into
+	// for the claim, which are handed to the containers instead of the device
+	Partitions []string `json:"partitions,omitempty"`
+}
+
+// listPublishedDevices returns the devices published by the drivers, for a
+// single node if nodeName is set
+func listPublishedDevices(ctx context.Context, config *Config, nodeName string) ([]*PublishedDevice, map[deviceKey]*PublishedDevice, error) {
+	opts := metav1.ListOptions{}
+	if nodeName != "" {
+		opts.FieldSelector = fields.OneTermEqualSelector("nodeName", nodeName).String()
+	}
+	resourceSlices, err := config.coreclient.ResourceV1alpha2().ResourceSlices().List(ctx, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing ResourceSlices: %w", err)
+	}
+
+	var devices []*PublishedDevice
+	index := map[deviceKey]*PublishedDevice{}
+	for _, resourceSlice := range resourceSlices.Items {
+		if !config.driverNames.Has(resourceSlice.DriverName) || resourceSlice.NamedResources == nil {
+			continue
+		}
+		for _, instance := range resourceSlice.NamedResources.Instances {
+			device := &PublishedDevice{
+				Node:   resourceSlice.NodeName,
+				Driver: resourceSlice.DriverName,
+				Name:   instance.Name,
+			}
+			for _, attribute := range instance.Attributes {
+				value := attributeValue(attribute)
+				switch attribute.Name {
+				case "uuid":
+					device.UUID = value
+				case "type":
+					device.Type = value
+				case "model":
+					device.Model = value
+				default:
+					if device.Attributes == nil {
+						device.Attributes = map[string]string{}
+					}
+					device.Attributes[attribute.Name] = value
+				}
+			}
+			devices = append(devices, device)
+			index[deviceKey{driver: device.Driver, node: device.Node, name: device.Name}] = device
+		}
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		if devices[i].Node != devices[j].Node {
+			return devices[i].Node < devices[j].Node
+		}
+		if devices[i].Driver != devices[j].Driver {
+			return devices[i].Driver < devices[j].Driver
+		}
+		return devices[i].Name < devices[j].Name
+	})
+	return devices, index, nil
+}
+
+// attributeValue formats the value of an attribute of a named resource
+func attributeValue(attribute resourceapi.NamedResourcesAttribute) string {
+	switch {
+	case attribute.StringValue != nil:
+		return *attribute.StringValue
+	case attribute.IntValue != nil:
+		return fmt.Sprint(*attribute.IntValue)
+	case attribute.BoolValue != nil:
+		return fmt.Sprint(*attribute.BoolValue)
+	case attribute.QuantityValue != nil:
+		return attribute.QuantityValue.String()
+	case attribute.VersionValue != nil:
+		return *attribute.VersionValue
+	case attribute.StringSliceValue != nil:
+		return fmt.Sprint(attribute.StringSliceValue.Strings)
+	case attribute.IntSliceValue != nil:
+		return fmt.Sprint(attribute.IntSliceValue.Ints)
+	default:
+		return ""
+	}
+}
+
+// allocatedDevices decodes the devices allocated to a claim by the drivers
+// from its structured resource handles. The devices are completed with what
+// their ResourceSlices publish about them, if they are still published.
+func allocatedDevices(ctx context.Context, config *Config, claim *resourceapi.ResourceClaim, published map[deviceKey]*PublishedDevice) []AllocatedDevice {
+	logger := klog.FromContext(ctx)
+	if claim.Status.Allocation == nil {
+		return nil
+	}
+
+	var devices []AllocatedDevice
+	for _, handle := range claim.Status.Allocation.ResourceHandles {
+		driverName := handle.DriverName
+		if driverName == "" {
+			driverName = claim.Status.DriverName
+		}
+		if !config.driverNames.Has(driverName) || handle.StructuredData == nil {
+			continue
+		}
+
+		nodeName := handle.StructuredData.NodeName
+		_, allocated, err := vendorparameters.DecodeAllocation(handle.StructuredData)
+		if err != nil {
+			logger.Error(err, "Error decoding allocation", "resourceClaim", klog.KObj(claim), "driverName", driverName)
+			continue
+		}
+		for _, device := range allocated {
+			allocatedDevice := AllocatedDevice{
+				Node:    nodeName,
+				Driver:  driverName,
+				Request: device.Request.Name,
+				Name:    device.Name,
+				Split:   device.Request.Split,
+			}
+			if publishedDevice, ok := published[deviceKey{driver: driverName, node: nodeName, name: device.Name}]; ok {
+				allocatedDevice.UUID = publishedDevice.UUID
+				allocatedDevice.Type = publishedDevice.Type
+				allocatedDevice.Model = publishedDevice.Model
+				if allocatedDevice.Type == "" {
+					allocatedDevice.Type = fakecrd.ModelDeviceType(publishedDevice.Model)
+				}
+				if device.Request.Split > 0 && allocatedDevice.UUID != "" {
+					allocatedDevice.Partitions = deviceuuid.Partitions(allocatedDevice.Type, allocatedDevice.UUID, device.Request.Split)
+				}
+			}
+			devices = append(devices, allocatedDevice)
+		}
+	}
+	return devices
+}
+
+// listResourceClaims returns the ResourceClaims of a namespace, or of all
+// namespaces if namespace is empty
+func listResourceClaims(ctx context.Context, config *Config, namespace string) ([]resourceapi.ResourceClaim, error) {
+	resourceClaims, err := config.coreclient.ResourceV1alpha2().ResourceClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing ResourceClaims: %w", err)
+	}
+	claims := resourceClaims.Items
+	sort.Slice(claims, func(i, j int) bool {
+		if claims[i].Namespace != claims[j].Namespace {
+			return claims[i].Namespace < claims[j].Namespace
+		}
+		return claims[i].Name < claims[j].Name
+	})
+	return claims, nil
+}
+
+// allocatedTo maps the devices allocated from the drivers to the claims they
+// are allocated to
+func allocatedTo(ctx context.Context, config *Config, claims []resourceapi.ResourceClaim) map[deviceKey]*resourceapi.ResourceClaim {
+	owners := map[deviceKey]*resourceapi.ResourceClaim{}
+	for i := range claims {
+		claim := &claims[i]
+		for _, device := range allocatedDevices(ctx, config, claim, nil) {
+			owners[deviceKey{driver: device.Driver, node: device.Node, name: device.Name}] = claim
+		}
+	}
+	return owners
+}
+
+// listClassDrivers maps the names of the ResourceClasses to their drivers
+func listClassDrivers(ctx context.Context, config *Config) (map[string]string, error) {
+	resourceClasses, err := config.coreclient.ResourceV1alpha2().ResourceClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing ResourceClasses: %w", err)
+	}
+	drivers := make(map[string]string, len(resourceClasses.Items))
+	for _, resourceClass := range resourceClasses.Items {
+		drivers[resourceClass.Name] = resourceClass.DriverName
+	}
+	return drivers, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cliflag "k8s.io/component-base/cli/flag"
+)
+
+// InventoryDevice is a published device together with the claim it is
+// allocated to
+type InventoryDevice struct {
+	*PublishedDevice
+	// Claim is the namespace/name of the claim the device is allocated to
+	Claim string `json:"claim,omitempty"`
+}
+
+func NewInventoryCommand(config *Config, sharedFlagSets cliflag.NamedFlagSets) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "List the devices published for each node",
+		Long:  "inventory lists the devices the drivers publish in the ResourceSlices of each node, together with the claims they are allocated to",
+		Example: `  # List the devices of all nodes
+  kubectl fake-dra inventory
+
+  # List the devices of a node as JSON
+  kubectl fake-dra inventory --node kind-worker -o json`,
+	}
+
+	namedFlagSets := cliflag.NamedFlagSets{}
+	fs := namedFlagSets.FlagSet("inventory")
+	nodeName := fs.String("node", "", "Name of the node to list the devices of. Devices of all nodes are listed if empty.")
+	cmd.Flags().AddFlagSet(fs)
+	setUsageAndHelpFunc(cmd, sharedFlagSets, namedFlagSets)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		published, _, err := listPublishedDevices(ctx, config, *nodeName)
+		if err != nil {
+			return err
+		}
+		claims, err := listResourceClaims(ctx, config, metav1.NamespaceAll)
+		if err != nil {
+			return err
+		}
+		owners := allocatedTo(ctx, config, claims)
+
+		devices := make([]InventoryDevice, 0, len(published))
+		for _, device := range published {
+			inventoryDevice := InventoryDevice{PublishedDevice: device}
+			if claim, ok := owners[deviceKey{driver: device.Driver, node: device.Node, name: device.Name}]; ok {
+				inventoryDevice.Claim = claimKey(claim)
+			}
+			devices = append(devices, inventoryDevice)
+		}
+
+		if config.output == outputJSON {
+			return printJSON(cmd.OutOrStdout(), devices)
+		}
+		t := newTable(cmd.OutOrStdout(), "NODE", "DRIVER", "DEVICE", "TYPE", "MODEL", "CLAIM")
+		for _, device := range devices {
+			t.row(device.Node, device.Driver, deviceID(device.UUID, device.Name), device.Type, device.Model, device.Claim)
+		}
+		return t.flush()
+	}
+
+	return cmd
+}
+
+// claimKey returns the namespace/name of a claim
+func claimKey(claim *resourceapi.ResourceClaim) string {
+	return claim.Namespace + "/" + claim.Name
+}
+
+// deviceID returns the UUID of a device, which is what containers are given,
+// or the name of the named resource if the UUID is unknown
+func deviceID(uuid, name string) string {
+	if uuid != "" {
+		return uuid
+	}
+	return name
+}
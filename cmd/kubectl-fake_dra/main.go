@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	coreclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/component-base/term"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	shakeclientset "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned"
+)
+
+const (
+	DefaultDriverName = fakecrd.GroupName
+
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+type Flags struct {
+	kubeconfig  *string
+	kubecontext *string
+	namespace   *string
+
+	driverNames *[]string
+	output      *string
+}
+
+// Config holds the clients and the options shared by all subcommands
+type Config struct {
+	coreclient  coreclientset.Interface
+	shakeclient shakeclientset.Interface
+
+	// namespace is the namespace of the current context unless set
+	namespace   string
+	driverNames sets.Set[string]
+	// defaultDriverName is the driver the controller allocates requests from
+	// when they do not name one
+	defaultDriverName string
+	output            string
+}
+
+func main() {
+	command := NewCommand()
+	if err := command.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "kubectl-fake_dra",
+		Short: "Inspect the devices allocated by the fake DRA driver",
+		Long: "kubectl-fake_dra shows the devices published by the fake DRA driver, the devices allocated to ResourceClaims " +
+			"and the ResourceClaimParameters generated from FakeClaimParameters, without reading structured resource handles.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	flags, sharedFlagSets := AddFlags(cmd)
+
+	config := &Config{}
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return flags.complete(config)
+	}
+
+	cmd.AddCommand(
+		NewInventoryCommand(config, sharedFlagSets),
+		NewClaimsCommand(config, sharedFlagSets),
+		NewParamsCommand(config, sharedFlagSets),
+		NewWhyPendingCommand(config, sharedFlagSets),
+	)
+
+	return cmd
+}
+
+// AddFlags adds the flags shared by all subcommands, which are returned
+// grouped for the help of the subcommands
+func AddFlags(cmd *cobra.Command) (*Flags, cliflag.NamedFlagSets) {
+	flags := &Flags{}
+	sharedFlagSets := cliflag.NamedFlagSets{}
+
+	fs := sharedFlagSets.FlagSet("Kubernetes client")
+	flags.kubeconfig = fs.String("kubeconfig", "", "Path to the kubeconfig file. Defaults to KUBECONFIG or ~/.kube/config.")
+	flags.kubecontext = fs.String("context", "", "Name of the kubeconfig context to use.")
+	flags.namespace = fs.StringP("namespace", "n", "", "Namespace of the objects. Defaults to the namespace of the context.")
+
+	fs = sharedFlagSets.FlagSet("driver")
+	flags.driverNames = fs.StringSlice("driver-names", []string{DefaultDriverName}, "Comma separated names of the drivers to inspect, the first one being the driver requests without a driverName are allocated from.")
+
+	fs = sharedFlagSets.FlagSet("output")
+	flags.output = fs.StringP("output", "o", outputTable, "Output format, table or json.")
+
+	fs = cmd.PersistentFlags()
+	for _, f := range sharedFlagSets.FlagSets {
+		fs.AddFlagSet(f)
+	}
+
+	cols, _, _ := term.TerminalSize(cmd.OutOrStdout())
+	cliflag.SetUsageAndHelpFunc(cmd, sharedFlagSets, cols)
+
+	return flags, sharedFlagSets
+}
+
+// complete validates the flags and creates the clients
+func (f *Flags) complete(config *Config) error {
+	if *f.output != outputTable && *f.output != outputJSON {
+		return fmt.Errorf("invalid --output %q: must be %s or %s", *f.output, outputTable, outputJSON)
+	}
+	if len(*f.driverNames) == 0 {
+		return fmt.Errorf("--driver-names must not be empty")
+	}
+	for _, driverName := range *f.driverNames {
+		if msgs := validation.IsDNS1123Subdomain(driverName); len(msgs) > 0 {
+			return fmt.Errorf("invalid driver name %q: %s", driverName, strings.Join(msgs, ", "))
+		}
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = *f.kubeconfig
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: *f.kubecontext,
+		Context:        clientcmdapi.Context{Namespace: *f.namespace},
+	}
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return fmt.Errorf("error getting namespace: %w", err)
+	}
+	csconfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("error creating client configuration: %w", err)
+	}
+
+	config.coreclient, err = coreclientset.NewForConfig(csconfig)
+	if err != nil {
+		return fmt.Errorf("error creating core client: %w", err)
+	}
+	config.shakeclient, err = shakeclientset.NewForConfig(csconfig)
+	if err != nil {
+		return fmt.Errorf("error creating 3-shake.com client: %w", err)
+	}
+
+	config.namespace = namespace
+	config.driverNames = sets.New(*f.driverNames...)
+	config.defaultDriverName = (*f.driverNames)[0]
+	config.output = *f.output
+	return nil
+}
+
+// setUsageAndHelpFunc prints the flags of a subcommand grouped before the
+// shared ones
+func setUsageAndHelpFunc(cmd *cobra.Command, sharedFlagSets, namedFlagSets cliflag.NamedFlagSets) {
+	allFlagSets := cliflag.NamedFlagSets{}
+	for _, name := range namedFlagSets.Order {
+		allFlagSets.FlagSet(name).AddFlagSet(namedFlagSets.FlagSets[name])
+	}
+	for _, name := range sharedFlagSets.Order {
+		allFlagSets.FlagSet(name).AddFlagSet(sharedFlagSets.FlagSets[name])
+	}
+	cols, _, _ := term.TerminalSize(cmd.OutOrStdout())
+	cliflag.SetUsageAndHelpFunc(cmd, allFlagSets, cols)
+}
+
+// printJSON prints the items of a subcommand as JSON
+func printJSON(w io.Writer, items interface{}) error {
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling output: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// table prints aligned columns the way kubectl does
+type table struct {
+	w *tabwriter.Writer
+}
+
+func newTable(w io.Writer, headers ...string) *table {
+	t := &table{w: tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)}
+	t.row(headers...)
+	return t
+}
+
+func (t *table) row(cells ...string) {
+	for i, cell := range cells {
+		if cell == "" {
+			cells[i] = "<none>"
+		}
+	}
+	fmt.Fprintln(t.w, strings.Join(cells, "\t"))
+}
+
+func (t *table) flush() error {
+	return t.w.Flush()
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/klog/v2"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	"github.com/toVersus/fake-dra-driver/pkg/vendorparameters"
+)
+
+// Params maps FakeClaimParameters to the ResourceClaimParameters generated
+// from them
+type Params struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Status summarizes the conditions reported by the controller
+	Status string `json:"status"`
+	// ResourceClaimParameters is the name of the generated object, empty
+	// until it has been generated
+	ResourceClaimParameters string          `json:"resourceClaimParameters,omitempty"`
+	Requests                []ParamsRequest `json:"requests,omitempty"`
+}
+
+// ParamsRequest is a request of FakeClaimParameters together with the number
+// of ResourceRequests generated for it
+type ParamsRequest struct {
+	Name     string `json:"name"`
+	Driver   string `json:"driver"`
+	Count    int    `json:"count"`
+	Split    int    `json:"split"`
+	Selector string `json:"selector"`
+	// Generated is the number of ResourceRequests generated for the request
+	Generated int `json:"generated"`
+}
+
+func NewParamsCommand(config *Config, sharedFlagSets cliflag.NamedFlagSets) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "params [NAME...]",
+		Short: "Map FakeClaimParameters to the generated ResourceClaimParameters",
+		Long:  "params shows the requests of FakeClaimParameters and the ResourceRequests the controller generated for them in ResourceClaimParameters",
+		Example: `  # Show the FakeClaimParameters of the current namespace
+  kubectl fake-dra params
+
+  # Show a single FakeClaimParameters as JSON
+  kubectl fake-dra params my-parameters -o json`,
+	}
+
+	namedFlagSets := cliflag.NamedFlagSets{}
+	fs := namedFlagSets.FlagSet("params")
+	allNamespaces := fs.BoolP("all-namespaces", "A", false, "Show the FakeClaimParameters of all namespaces.")
+	cmd.Flags().AddFlagSet(fs)
+	setUsageAndHelpFunc(cmd, sharedFlagSets, namedFlagSets)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		namespace := config.namespace
+		if *allNamespaces {
+			namespace = metav1.NamespaceAll
+		}
+		fakeClaimParametersList, err := config.shakeclient.FakeV1beta1().FakeClaimParameters(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("error listing FakeClaimParameters: %w", err)
+		}
+
+		names := map[string]bool{}
+		for _, name := range args {
+			names[name] = true
+		}
+		var params []Params
+		for i := range fakeClaimParametersList.Items {
+			fakeClaimParameters := &fakeClaimParametersList.Items[i]
+			if len(names) > 0 && !names[fakeClaimParameters.Name] {
+				continue
+			}
+			p, err := newParams(ctx, config, fakeClaimParameters)
+			if err != nil {
+				return err
+			}
+			params = append(params, p)
+		}
+
+		if config.output == outputJSON {
+			return printJSON(cmd.OutOrStdout(), params)
+		}
+		return printParams(cmd, params, *allNamespaces)
+	}
+
+	return cmd
+}
+
+func newParams(ctx context.Context, config *Config, fakeClaimParameters *fakecrd.FakeClaimParameters) (Params, error) {
+	params := Params{
+		Namespace: fakeClaimParameters.Namespace,
+		Name:      fakeClaimParameters.Name,
+		Status:    fakeClaimParametersStatus(fakeClaimParameters),
+	}
+
+	generated := map[string]int{}
+	if ref := fakeClaimParameters.Status.Generated; ref != nil {
+		params.ResourceClaimParameters = ref.Name
+		resourceClaimParameters, err := config.coreclient.ResourceV1alpha2().ResourceClaimParameters(fakeClaimParameters.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			params.ResourceClaimParameters = ""
+		case err != nil:
+			return params, fmt.Errorf("error getting ResourceClaimParameters %s/%s: %w", fakeClaimParameters.Namespace, ref.Name, err)
+		default:
+			generated = generatedRequests(ctx, resourceClaimParameters)
+		}
+	}
+
+	spec := fakeClaimParameters.Spec.DeepCopy()
+	fakecrd.SetDefaultsFakeClaimParametersSpec(spec)
+	for _, request := range spec.GetRequests() {
+		driverName := request.DriverName
+		if driverName == "" {
+			driverName = config.defaultDriverName
+		}
+		selector := fakecrd.FakeSelector{}
+		if request.Selector != nil {
+			selector = *request.Selector
+		}
+		params.Requests = append(params.Requests, ParamsRequest{
+			Name:      request.Name,
+			Driver:    driverName,
+			Count:     request.Count,
+			Split:     request.Split,
+			Selector:  selector.ToNamedResourcesSelector(),
+			Generated: generated[driverName+"/"+request.Name],
+		})
+	}
+	return params, nil
+}
+
+// generatedRequests counts the ResourceRequests generated for each driver and
+// request, keyed by driver/request
+func generatedRequests(ctx context.Context, resourceClaimParameters *resourceapi.ResourceClaimParameters) map[string]int {
+	logger := klog.FromContext(ctx)
+
+	counts := map[string]int{}
+	for _, driverRequests := range resourceClaimParameters.DriverRequests {
+		for _, request := range driverRequests.Requests {
+			name, err := vendorparameters.DecodeRequestParameters(request.VendorParameters.Raw)
+			if err != nil {
+				logger.Error(err, "Error decoding request parameters", "resourceClaimParameters", klog.KObj(resourceClaimParameters), "driverName", driverRequests.DriverName)
+				continue
+			}
+			counts[driverRequests.DriverName+"/"+name]++
+		}
+	}
+	return counts
+}
+
+// fakeClaimParametersStatus summarizes the conditions of FakeClaimParameters
+func fakeClaimParametersStatus(fakeClaimParameters *fakecrd.FakeClaimParameters) string {
+	conditions := fakeClaimParameters.Status.Conditions
+	if accepted := meta.FindStatusCondition(conditions, fakecrd.FakeClaimParametersAccepted); accepted != nil && accepted.Status == metav1.ConditionFalse {
+		return accepted.Reason
+	}
+	generated := meta.FindStatusCondition(conditions, fakecrd.FakeClaimParametersGenerated)
+	switch {
+	case generated == nil:
+		return "Pending"
+	case generated.Status == metav1.ConditionTrue:
+		return fakecrd.FakeClaimParametersGenerated
+	default:
+		return generated.Reason
+	}
+}
+
+func printParams(cmd *cobra.Command, params []Params, allNamespaces bool) error {
+	headers := []string{"NAME", "STATUS", "REQUEST", "DRIVER", "COUNT", "SPLIT", "SELECTOR", "RESOURCECLAIMPARAMETERS", "GENERATED"}
+	if allNamespaces {
+		headers = append([]string{"NAMESPACE"}, headers...)
+	}
+	t := newTable(cmd.OutOrStdout(), headers...)
+	row := func(p Params, cells ...string) {
+		if allNamespaces {
+			cells = append([]string{p.Namespace}, cells...)
+		}
+		t.row(cells...)
+	}
+
+	for _, p := range params {
+		if len(p.Requests) == 0 {
+			// Admin access parameters request no devices
+			row(p, p.Name, p.Status, "", "", "", "", "", p.ResourceClaimParameters, "")
+			continue
+		}
+		for _, request := range p.Requests {
+			row(p, p.Name, p.Status, request.Name, request.Driver, fmt.Sprint(request.Count), fmt.Sprint(request.Split),
+				request.Selector, p.ResourceClaimParameters, fmt.Sprint(request.Generated))
+		}
+	}
+	return t.flush()
+}
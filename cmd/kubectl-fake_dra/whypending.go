@@ -0,0 +1,375 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	cliflag "k8s.io/component-base/cli/flag"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+)
+
+// Diagnosis explains why a claim is not allocated
+type Diagnosis struct {
+	Namespace string `json:"namespace"`
+	Claim     string `json:"claim"`
+	Allocated bool   `json:"allocated"`
+	// Node is the node the claim is allocated on, if it is
+	Node string `json:"node,omitempty"`
+	// Problems prevent the claim from being allocated on any node
+	Problems []string          `json:"problems,omitempty"`
+	Nodes    []NodeFeasibility `json:"nodes,omitempty"`
+}
+
+// NodeFeasibility tells whether the requests of a claim fit on a node
+type NodeFeasibility struct {
+	Node     string               `json:"node"`
+	Feasible bool                 `json:"feasible"`
+	Requests []RequestFeasibility `json:"requests"`
+}
+
+// RequestFeasibility tells whether a request of a claim fits on a node
+type RequestFeasibility struct {
+	Request string `json:"request"`
+	Driver  string `json:"driver"`
+	Count   int    `json:"count"`
+	// Matching is the number of devices matching the class and the request
+	Matching int `json:"matching"`
+	// Free is the number of matching devices allocated to no other claim
+	// nor to a previous request of the claim
+	Free     int    `json:"free"`
+	Feasible bool   `json:"feasible"`
+	Note     string `json:"note,omitempty"`
+}
+
+// pendingRequest is a request of a claim to check against the nodes
+type pendingRequest struct {
+	name   string
+	driver string
+	count  int
+	split  int
+	models []string
+}
+
+func NewWhyPendingCommand(config *Config, sharedFlagSets cliflag.NamedFlagSets) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "why-pending CLAIM",
+		Short: "Explain why a claim is not allocated",
+		Long: "why-pending checks the class and the parameters of a ResourceClaim and whether the devices published for each node " +
+			"can satisfy its requests. The check is simpler than the one of the scheduler, it only knows about the fake device selectors.",
+		Example: `  # Explain why a claim of the current namespace is pending
+  kubectl fake-dra why-pending my-claim`,
+		Args: cobra.ExactArgs(1),
+	}
+	setUsageAndHelpFunc(cmd, sharedFlagSets, cliflag.NamedFlagSets{})
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		diagnosis, err := diagnose(cmd.Context(), config, config.namespace, args[0])
+		if err != nil {
+			return err
+		}
+		if config.output == outputJSON {
+			return printJSON(cmd.OutOrStdout(), diagnosis)
+		}
+		return printDiagnosis(cmd, diagnosis)
+	}
+
+	return cmd
+}
+
+func diagnose(ctx context.Context, config *Config, namespace, name string) (*Diagnosis, error) {
+	claim, err := config.coreclient.ResourceV1alpha2().ResourceClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting ResourceClaim %s/%s: %w", namespace, name, err)
+	}
+
+	diagnosis := &Diagnosis{Namespace: namespace, Claim: name}
+	if allocation := claim.Status.Allocation; allocation != nil {
+		diagnosis.Allocated = true
+		for _, handle := range allocation.ResourceHandles {
+			if handle.StructuredData != nil && handle.StructuredData.NodeName != "" {
+				diagnosis.Node = handle.StructuredData.NodeName
+				break
+			}
+		}
+		return diagnosis, nil
+	}
+	problem := func(format string, args ...interface{}) {
+		diagnosis.Problems = append(diagnosis.Problems, fmt.Sprintf(format, args...))
+	}
+
+	class, err := config.coreclient.ResourceV1alpha2().ResourceClasses().Get(ctx, claim.Spec.ResourceClassName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		problem("ResourceClass %s does not exist", claim.Spec.ResourceClassName)
+		return diagnosis, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting ResourceClass %s: %w", claim.Spec.ResourceClassName, err)
+	}
+	if !config.driverNames.Has(class.DriverName) {
+		problem("ResourceClass %s uses driver %s, which is not one of %v", class.Name, class.DriverName, sets.List(config.driverNames))
+		return diagnosis, nil
+	}
+	if class.StructuredParameters == nil || !*class.StructuredParameters {
+		problem("ResourceClass %s does not set structuredParameters, the claim is left to a control plane controller", class.Name)
+	}
+
+	classSpec, err := deviceClassParametersSpec(ctx, config, class, problem)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := claimRequests(ctx, config, claim, class, problem)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkConsumers(ctx, config, claim, problem); err != nil {
+		return nil, err
+	}
+	if classSpec == nil || requests == nil {
+		return diagnosis, nil
+	}
+
+	published, _, err := listPublishedDevices(ctx, config, "")
+	if err != nil {
+		return nil, err
+	}
+	claims, err := listResourceClaims(ctx, config, metav1.NamespaceAll)
+	if err != nil {
+		return nil, err
+	}
+	owners := allocatedTo(ctx, config, claims)
+
+	devicesByNode := map[string][]*PublishedDevice{}
+	var nodeNames []string
+	for _, device := range published {
+		if _, ok := devicesByNode[device.Node]; !ok {
+			nodeNames = append(nodeNames, device.Node)
+		}
+		devicesByNode[device.Node] = append(devicesByNode[device.Node], device)
+	}
+	if len(nodeNames) == 0 {
+		problem("No ResourceSlice of %v publishes any device", sets.List(config.driverNames))
+	}
+
+	for _, nodeName := range nodeNames {
+		diagnosis.Nodes = append(diagnosis.Nodes, checkNode(nodeName, devicesByNode[nodeName], classSpec, requests, owners))
+	}
+	return diagnosis, nil
+}
+
+// deviceClassParametersSpec returns the defaulted DeviceClassParametersSpec
+// of a class, which selects every fake device if the class has no parameters
+func deviceClassParametersSpec(ctx context.Context, config *Config, class *resourceapi.ResourceClass, problem func(string, ...interface{})) (*fakecrd.DeviceClassParametersSpec, error) {
+	ref := class.ParametersRef
+	if ref == nil {
+		return fakecrd.DefaultDeviceClassParametersSpec(), nil
+	}
+	if ref.APIGroup != fakecrd.GroupName || ref.Kind != fakecrd.DeviceClassParametersKind {
+		problem("ResourceClass %s refers to unsupported parameters %s.%s", class.Name, ref.Kind, ref.APIGroup)
+		return nil, nil
+	}
+
+	deviceClassParameters, err := config.shakeclient.FakeV1beta1().DeviceClassParameters().Get(ctx, ref.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		problem("DeviceClassParameters %s of ResourceClass %s does not exist", ref.Name, class.Name)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting DeviceClassParameters %s: %w", ref.Name, err)
+	}
+	spec := deviceClassParameters.Spec.DeepCopy()
+	fakecrd.SetDefaultsDeviceClassParametersSpec(spec)
+	return spec, nil
+}
+
+// claimRequests returns the requests of a claim. A claim without parameters
+// requests a single device of the driver of its class.
+func claimRequests(ctx context.Context, config *Config, claim *resourceapi.ResourceClaim, class *resourceapi.ResourceClass, problem func(string, ...interface{})) ([]pendingRequest, error) {
+	ref := claim.Spec.ParametersRef
+	if ref == nil {
+		return []pendingRequest{{name: fakecrd.DefaultRequestName, driver: class.DriverName, count: 1}}, nil
+	}
+	if ref.APIGroup != fakecrd.GroupName || ref.Kind != fakecrd.FakeClaimParametersKind {
+		problem("Claim parameters %s.%s are not supported by this check", ref.Kind, ref.APIGroup)
+		return nil, nil
+	}
+
+	fakeClaimParameters, err := config.shakeclient.FakeV1beta1().FakeClaimParameters(claim.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		problem("FakeClaimParameters %s does not exist", ref.Name)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting FakeClaimParameters %s/%s: %w", claim.Namespace, ref.Name, err)
+	}
+	conditions := fakeClaimParameters.Status.Conditions
+	if accepted := meta.FindStatusCondition(conditions, fakecrd.FakeClaimParametersAccepted); accepted != nil && accepted.Status == metav1.ConditionFalse {
+		problem("FakeClaimParameters %s are not accepted: %s: %s", ref.Name, accepted.Reason, accepted.Message)
+	} else if !meta.IsStatusConditionTrue(conditions, fakecrd.FakeClaimParametersGenerated) {
+		problem("ResourceClaimParameters have not been generated from FakeClaimParameters %s: %s", ref.Name, fakeClaimParametersStatus(fakeClaimParameters))
+	}
+
+	spec := fakeClaimParameters.Spec.DeepCopy()
+	fakecrd.SetDefaultsFakeClaimParametersSpec(spec)
+	// Admin access claims request no devices
+	requests := []pendingRequest{}
+	for _, request := range spec.GetRequests() {
+		driverName := request.DriverName
+		if driverName == "" {
+			driverName = config.defaultDriverName
+		}
+		pending := pendingRequest{name: request.Name, driver: driverName, count: request.Count, split: request.Split}
+		if request.Selector != nil {
+			pending.models = request.Selector.Models
+		}
+		requests = append(requests, pending)
+	}
+	return requests, nil
+}
+
+// checkConsumers reports a claim waiting for its first consumer without any
+// Pod referring to it
+func checkConsumers(ctx context.Context, config *Config, claim *resourceapi.ResourceClaim, problem func(string, ...interface{})) error {
+	if claim.Spec.AllocationMode != resourceapi.AllocationModeWaitForFirstConsumer {
+		return nil
+	}
+	pods, err := config.coreclient.CoreV1().Pods(claim.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error listing Pods: %w", err)
+	}
+	for i := range pods.Items {
+		if podUsesClaim(&pods.Items[i], claim.Name) {
+			return nil
+		}
+	}
+	problem("The claim waits for its first consumer and no Pod refers to it")
+	return nil
+}
+
+// podUsesClaim returns whether a Pod refers to a claim, directly or through
+// the claim generated from one of its templates
+func podUsesClaim(pod *corev1.Pod, claimName string) bool {
+	for _, podClaim := range pod.Spec.ResourceClaims {
+		if name := podClaim.Source.ResourceClaimName; name != nil && *name == claimName {
+			return true
+		}
+	}
+	for _, status := range pod.Status.ResourceClaimStatuses {
+		if name := status.ResourceClaimName; name != nil && *name == claimName {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNode allocates the requests greedily from the devices of a node, the
+// way the scheduler would for selectors without overlaps
+func checkNode(nodeName string, devices []*PublishedDevice, classSpec *fakecrd.DeviceClassParametersSpec, requests []pendingRequest, owners map[deviceKey]*resourceapi.ResourceClaim) NodeFeasibility {
+	node := NodeFeasibility{Node: nodeName, Feasible: true}
+	picked := map[deviceKey]bool{}
+	for _, request := range requests {
+		feasibility := RequestFeasibility{Request: request.name, Driver: request.driver, Count: request.count}
+		tooSmall := 0
+		for _, device := range devices {
+			key := deviceKey{driver: device.Driver, node: device.Node, name: device.Name}
+			if device.Driver != request.driver || !classSelects(classSpec, device) ||
+				(len(request.models) > 0 && !slices.Contains(request.models, device.Model)) {
+				continue
+			}
+			feasibility.Matching++
+			if maxSplit, ok := fakecrd.MaxSplit(device.Model); ok && request.split > maxSplit {
+				tooSmall++
+				continue
+			}
+			if _, ok := owners[key]; ok || picked[key] {
+				continue
+			}
+			feasibility.Free++
+			if feasibility.Free <= request.count {
+				picked[key] = true
+			}
+		}
+		feasibility.Feasible = feasibility.Free >= request.count
+		switch {
+		case tooSmall > 0:
+			feasibility.Note = fmt.Sprintf("%d matching devices cannot be split into %d", tooSmall, request.split)
+		case feasibility.Matching == 0:
+			feasibility.Note = "no device matches the class and the request"
+		}
+		if !feasibility.Feasible {
+			node.Feasible = false
+		}
+		node.Requests = append(node.Requests, feasibility)
+	}
+	return node
+}
+
+// classSelects returns whether a device matches any selector of a class
+func classSelects(spec *fakecrd.DeviceClassParametersSpec, device *PublishedDevice) bool {
+	for _, selector := range spec.DeviceSelector {
+		if selector.Type != device.Type {
+			continue
+		}
+		if selector.Name != "" && selector.Name != "*" && selector.Name != device.UUID {
+			continue
+		}
+		if len(selector.Models) > 0 && !slices.Contains(selector.Models, device.Model) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func printDiagnosis(cmd *cobra.Command, diagnosis *Diagnosis) error {
+	w := cmd.OutOrStdout()
+	if diagnosis.Allocated {
+		_, err := fmt.Fprintf(w, "ResourceClaim %s/%s is allocated on node %s\n", diagnosis.Namespace, diagnosis.Claim, diagnosis.Node)
+		return err
+	}
+
+	for _, problem := range diagnosis.Problems {
+		fmt.Fprintf(w, "- %s\n", problem)
+	}
+	if len(diagnosis.Nodes) > 0 {
+		if len(diagnosis.Problems) > 0 {
+			fmt.Fprintln(w)
+		}
+		t := newTable(w, "NODE", "DRIVER", "REQUEST", "NEEDED", "MATCHING", "FREE", "FEASIBLE", "NOTE")
+		for _, node := range diagnosis.Nodes {
+			for _, request := range node.Requests {
+				t.row(node.Node, request.Driver, request.Request, fmt.Sprint(request.Count), fmt.Sprint(request.Matching),
+					fmt.Sprint(request.Free), fmt.Sprint(request.Feasible), request.Note)
+			}
+		}
+		if err := t.flush(); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+
+	var feasible []string
+	for _, node := range diagnosis.Nodes {
+		if node.Feasible {
+			feasible = append(feasible, node.Node)
+		}
+	}
+	switch {
+	case len(feasible) > 0:
+		_, err := fmt.Fprintf(w, "The requests fit on %d node(s): %v\n", len(feasible), feasible)
+		return err
+	case len(diagnosis.Problems) == 0 && len(diagnosis.Nodes) > 0:
+		_, err := fmt.Fprintln(w, "No node has enough free devices for the requests")
+		return err
+	}
+	return nil
+}
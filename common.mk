@@ -13,7 +13,9 @@
 # limitations under the License.
 
 VENDOR := 3-shake.com
-APIS := fake/v1alpha1
+APIS := fake/v1alpha1 fake/v1beta1
+# CRDs served in several versions and converted by fake-dra-webhook
+CONVERTED_CRDS := fake.resource.3-shake.com_fakeclaimparameters fake.resource.3-shake.com_deviceclassparameters
 
 PLURAL_EXCEPTIONS  = DeviceClassParameters:DeviceClassParameters
 PLURAL_EXCEPTIONS += FakeClaimParameters:FakeClaimParameters
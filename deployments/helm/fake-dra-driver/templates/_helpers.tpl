@@ -95,3 +95,51 @@ Create the name of the service account to use
 {{- default "default" .Values.serviceAccount.name }}
 {{- end }}
 {{- end }}
+
+{{/*
+Name of the secret holding the webhook serving certificate
+*/}}
+{{- define "fake-dra-driver.webhookTLSSecretName" -}}
+{{- default (printf "%s-webhook-tls" (include "fake-dra-driver.fullname" .)) .Values.webhook.tls.secretName }}
+{{- end }}
+
+{{/*
+Webhook selector labels
+*/}}
+{{- define "fake-dra-driver.webhookSelectorLabels" -}}
+{{ include "fake-dra-driver.selectorLabels" . }}
+app.kubernetes.io/component: webhook
+{{- end }}
+
+{{/*
+Virtual nodes selector labels
+*/}}
+{{- define "fake-dra-driver.virtualNodesSelectorLabels" -}}
+{{ include "fake-dra-driver.selectorLabels" . }}
+app.kubernetes.io/component: virtual-nodes
+{{- end }}
+
+{{/*
+Controller selector labels, only used by the Service as the Deployment
+selector predates the component label
+*/}}
+{{- define "fake-dra-driver.controllerSelectorLabels" -}}
+{{ include "fake-dra-driver.selectorLabels" . }}
+app.kubernetes.io/component: controller
+{{- end }}
+
+{{/*
+Kubelet plugin selector labels, only used by the PodMonitor as the DaemonSet
+selector predates the component label
+*/}}
+{{- define "fake-dra-driver.kubeletPluginSelectorLabels" -}}
+{{ include "fake-dra-driver.selectorLabels" . }}
+app.kubernetes.io/component: kubeletplugin
+{{- end }}
+
+{{/*
+Comma separated names of the drivers served by the controller and webhook
+*/}}
+{{- define "fake-dra-driver.driverNames" -}}
+{{- prepend .Values.additionalDriverNames .Values.driverName | join "," }}
+{{- end }}
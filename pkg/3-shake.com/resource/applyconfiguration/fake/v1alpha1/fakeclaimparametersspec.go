@@ -0,0 +1,56 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+// FakeClaimParametersSpecApplyConfiguration represents an declarative configuration of the FakeClaimParametersSpec type for use
+// with apply.
+type FakeClaimParametersSpecApplyConfiguration struct {
+	Count    *int                            `json:"count,omitempty"`
+	Split    *int                            `json:"split,omitempty"`
+	Selector *FakeSelectorApplyConfiguration `json:"selector,omitempty"`
+}
+
+// FakeClaimParametersSpecApplyConfiguration constructs an declarative configuration of the FakeClaimParametersSpec type for use with
+// apply.
+func FakeClaimParametersSpec() *FakeClaimParametersSpecApplyConfiguration {
+	return &FakeClaimParametersSpecApplyConfiguration{}
+}
+
+// WithCount sets the Count field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Count field is set to the value of the last call.
+func (b *FakeClaimParametersSpecApplyConfiguration) WithCount(value int) *FakeClaimParametersSpecApplyConfiguration {
+	b.Count = &value
+	return b
+}
+
+// WithSplit sets the Split field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Split field is set to the value of the last call.
+func (b *FakeClaimParametersSpecApplyConfiguration) WithSplit(value int) *FakeClaimParametersSpecApplyConfiguration {
+	b.Split = &value
+	return b
+}
+
+// WithSelector sets the Selector field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Selector field is set to the value of the last call.
+func (b *FakeClaimParametersSpecApplyConfiguration) WithSelector(value *FakeSelectorApplyConfiguration) *FakeClaimParametersSpecApplyConfiguration {
+	b.Selector = value
+	return b
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/client-go/applyconfigurations/meta/v1"
+)
+
+// FakeClaimParametersStatusApplyConfiguration represents an declarative configuration of the FakeClaimParametersStatus type for use
+// with apply.
+type FakeClaimParametersStatusApplyConfiguration struct {
+	ObservedGeneration *int64                                      `json:"observedGeneration,omitempty"`
+	Generated          *GeneratedObjectReferenceApplyConfiguration `json:"generated,omitempty"`
+	Selector           *string                                     `json:"selector,omitempty"`
+	Requests           []FakeRequestStatusApplyConfiguration       `json:"requests,omitempty"`
+	Conditions         []v1.ConditionApplyConfiguration            `json:"conditions,omitempty"`
+}
+
+// FakeClaimParametersStatusApplyConfiguration constructs an declarative configuration of the FakeClaimParametersStatus type for use with
+// apply.
+func FakeClaimParametersStatus() *FakeClaimParametersStatusApplyConfiguration {
+	return &FakeClaimParametersStatusApplyConfiguration{}
+}
+
+// WithObservedGeneration sets the ObservedGeneration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ObservedGeneration field is set to the value of the last call.
+func (b *FakeClaimParametersStatusApplyConfiguration) WithObservedGeneration(value int64) *FakeClaimParametersStatusApplyConfiguration {
+	b.ObservedGeneration = &value
+	return b
+}
+
+// WithGenerated sets the Generated field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Generated field is set to the value of the last call.
+func (b *FakeClaimParametersStatusApplyConfiguration) WithGenerated(value *GeneratedObjectReferenceApplyConfiguration) *FakeClaimParametersStatusApplyConfiguration {
+	b.Generated = value
+	return b
+}
+
+// WithSelector sets the Selector field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Selector field is set to the value of the last call.
+func (b *FakeClaimParametersStatusApplyConfiguration) WithSelector(value string) *FakeClaimParametersStatusApplyConfiguration {
+	b.Selector = &value
+	return b
+}
+
+// WithRequests adds the given value to the Requests field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Requests field.
+func (b *FakeClaimParametersStatusApplyConfiguration) WithRequests(values ...*FakeRequestStatusApplyConfiguration) *FakeClaimParametersStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithRequests")
+		}
+		b.Requests = append(b.Requests, *values[i])
+	}
+	return b
+}
+
+// WithConditions adds the given value to the Conditions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Conditions field.
+func (b *FakeClaimParametersStatusApplyConfiguration) WithConditions(values ...*v1.ConditionApplyConfiguration) *FakeClaimParametersStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithConditions")
+		}
+		b.Conditions = append(b.Conditions, *values[i])
+	}
+	return b
+}
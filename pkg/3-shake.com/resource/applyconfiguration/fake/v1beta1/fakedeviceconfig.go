@@ -0,0 +1,70 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+// FakeDeviceConfigApplyConfiguration represents an declarative configuration of the FakeDeviceConfig type for use
+// with apply.
+type FakeDeviceConfigApplyConfiguration struct {
+	Env             []FakeEnvVarApplyConfiguration `json:"env,omitempty"`
+	Mode            *string                        `json:"mode,omitempty"`
+	PowerLimitWatts *int                           `json:"powerLimitWatts,omitempty"`
+	ClockProfile    *string                        `json:"clockProfile,omitempty"`
+}
+
+// FakeDeviceConfigApplyConfiguration constructs an declarative configuration of the FakeDeviceConfig type for use with
+// apply.
+func FakeDeviceConfig() *FakeDeviceConfigApplyConfiguration {
+	return &FakeDeviceConfigApplyConfiguration{}
+}
+
+// WithEnv adds the given value to the Env field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Env field.
+func (b *FakeDeviceConfigApplyConfiguration) WithEnv(values ...*FakeEnvVarApplyConfiguration) *FakeDeviceConfigApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithEnv")
+		}
+		b.Env = append(b.Env, *values[i])
+	}
+	return b
+}
+
+// WithMode sets the Mode field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Mode field is set to the value of the last call.
+func (b *FakeDeviceConfigApplyConfiguration) WithMode(value string) *FakeDeviceConfigApplyConfiguration {
+	b.Mode = &value
+	return b
+}
+
+// WithPowerLimitWatts sets the PowerLimitWatts field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PowerLimitWatts field is set to the value of the last call.
+func (b *FakeDeviceConfigApplyConfiguration) WithPowerLimitWatts(value int) *FakeDeviceConfigApplyConfiguration {
+	b.PowerLimitWatts = &value
+	return b
+}
+
+// WithClockProfile sets the ClockProfile field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ClockProfile field is set to the value of the last call.
+func (b *FakeDeviceConfigApplyConfiguration) WithClockProfile(value string) *FakeDeviceConfigApplyConfiguration {
+	b.ClockProfile = &value
+	return b
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+// FakeDeviceQuotaSpecApplyConfiguration represents an declarative configuration of the FakeDeviceQuotaSpec type for use
+// with apply.
+type FakeDeviceQuotaSpecApplyConfiguration struct {
+	Devices    *int     `json:"devices,omitempty"`
+	Partitions *int     `json:"partitions,omitempty"`
+	Models     []string `json:"models,omitempty"`
+}
+
+// FakeDeviceQuotaSpecApplyConfiguration constructs an declarative configuration of the FakeDeviceQuotaSpec type for use with
+// apply.
+func FakeDeviceQuotaSpec() *FakeDeviceQuotaSpecApplyConfiguration {
+	return &FakeDeviceQuotaSpecApplyConfiguration{}
+}
+
+// WithDevices sets the Devices field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Devices field is set to the value of the last call.
+func (b *FakeDeviceQuotaSpecApplyConfiguration) WithDevices(value int) *FakeDeviceQuotaSpecApplyConfiguration {
+	b.Devices = &value
+	return b
+}
+
+// WithPartitions sets the Partitions field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Partitions field is set to the value of the last call.
+func (b *FakeDeviceQuotaSpecApplyConfiguration) WithPartitions(value int) *FakeDeviceQuotaSpecApplyConfiguration {
+	b.Partitions = &value
+	return b
+}
+
+// WithModels adds the given value to the Models field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Models field.
+func (b *FakeDeviceQuotaSpecApplyConfiguration) WithModels(values ...string) *FakeDeviceQuotaSpecApplyConfiguration {
+	for i := range values {
+		b.Models = append(b.Models, values[i])
+	}
+	return b
+}
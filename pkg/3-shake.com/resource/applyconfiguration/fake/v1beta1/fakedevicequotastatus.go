@@ -0,0 +1,56 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+// FakeDeviceQuotaStatusApplyConfiguration represents an declarative configuration of the FakeDeviceQuotaStatus type for use
+// with apply.
+type FakeDeviceQuotaStatusApplyConfiguration struct {
+	ObservedGeneration *int64                             `json:"observedGeneration,omitempty"`
+	Used               *FakeDeviceUsageApplyConfiguration `json:"used,omitempty"`
+	Claims             *int                               `json:"claims,omitempty"`
+}
+
+// FakeDeviceQuotaStatusApplyConfiguration constructs an declarative configuration of the FakeDeviceQuotaStatus type for use with
+// apply.
+func FakeDeviceQuotaStatus() *FakeDeviceQuotaStatusApplyConfiguration {
+	return &FakeDeviceQuotaStatusApplyConfiguration{}
+}
+
+// WithObservedGeneration sets the ObservedGeneration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ObservedGeneration field is set to the value of the last call.
+func (b *FakeDeviceQuotaStatusApplyConfiguration) WithObservedGeneration(value int64) *FakeDeviceQuotaStatusApplyConfiguration {
+	b.ObservedGeneration = &value
+	return b
+}
+
+// WithUsed sets the Used field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Used field is set to the value of the last call.
+func (b *FakeDeviceQuotaStatusApplyConfiguration) WithUsed(value *FakeDeviceUsageApplyConfiguration) *FakeDeviceQuotaStatusApplyConfiguration {
+	b.Used = value
+	return b
+}
+
+// WithClaims sets the Claims field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Claims field is set to the value of the last call.
+func (b *FakeDeviceQuotaStatusApplyConfiguration) WithClaims(value int) *FakeDeviceQuotaStatusApplyConfiguration {
+	b.Claims = &value
+	return b
+}
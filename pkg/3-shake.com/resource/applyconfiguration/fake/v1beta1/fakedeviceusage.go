@@ -0,0 +1,62 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+// FakeDeviceUsageApplyConfiguration represents an declarative configuration of the FakeDeviceUsage type for use
+// with apply.
+type FakeDeviceUsageApplyConfiguration struct {
+	Devices    *int           `json:"devices,omitempty"`
+	Partitions *int           `json:"partitions,omitempty"`
+	Models     map[string]int `json:"models,omitempty"`
+}
+
+// FakeDeviceUsageApplyConfiguration constructs an declarative configuration of the FakeDeviceUsage type for use with
+// apply.
+func FakeDeviceUsage() *FakeDeviceUsageApplyConfiguration {
+	return &FakeDeviceUsageApplyConfiguration{}
+}
+
+// WithDevices sets the Devices field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Devices field is set to the value of the last call.
+func (b *FakeDeviceUsageApplyConfiguration) WithDevices(value int) *FakeDeviceUsageApplyConfiguration {
+	b.Devices = &value
+	return b
+}
+
+// WithPartitions sets the Partitions field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Partitions field is set to the value of the last call.
+func (b *FakeDeviceUsageApplyConfiguration) WithPartitions(value int) *FakeDeviceUsageApplyConfiguration {
+	b.Partitions = &value
+	return b
+}
+
+// WithModels puts the entries into the Models field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, the entries provided by each call will be put on the Models field,
+// overwriting an existing map entries in Models field with the same key.
+func (b *FakeDeviceUsageApplyConfiguration) WithModels(entries map[string]int) *FakeDeviceUsageApplyConfiguration {
+	if b.Models == nil && len(entries) > 0 {
+		b.Models = make(map[string]int, len(entries))
+	}
+	for k, v := range entries {
+		b.Models[k] = v
+	}
+	return b
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+// FakeRequestApplyConfiguration represents an declarative configuration of the FakeRequest type for use
+// with apply.
+type FakeRequestApplyConfiguration struct {
+	Name       *string                         `json:"name,omitempty"`
+	Count      *int                            `json:"count,omitempty"`
+	Split      *int                            `json:"split,omitempty"`
+	Selector   *FakeSelectorApplyConfiguration `json:"selector,omitempty"`
+	DriverName *string                         `json:"driverName,omitempty"`
+}
+
+// FakeRequestApplyConfiguration constructs an declarative configuration of the FakeRequest type for use with
+// apply.
+func FakeRequest() *FakeRequestApplyConfiguration {
+	return &FakeRequestApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *FakeRequestApplyConfiguration) WithName(value string) *FakeRequestApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithCount sets the Count field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Count field is set to the value of the last call.
+func (b *FakeRequestApplyConfiguration) WithCount(value int) *FakeRequestApplyConfiguration {
+	b.Count = &value
+	return b
+}
+
+// WithSplit sets the Split field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Split field is set to the value of the last call.
+func (b *FakeRequestApplyConfiguration) WithSplit(value int) *FakeRequestApplyConfiguration {
+	b.Split = &value
+	return b
+}
+
+// WithSelector sets the Selector field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Selector field is set to the value of the last call.
+func (b *FakeRequestApplyConfiguration) WithSelector(value *FakeSelectorApplyConfiguration) *FakeRequestApplyConfiguration {
+	b.Selector = value
+	return b
+}
+
+// WithDriverName sets the DriverName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DriverName field is set to the value of the last call.
+func (b *FakeRequestApplyConfiguration) WithDriverName(value string) *FakeRequestApplyConfiguration {
+	b.DriverName = &value
+	return b
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+// FakeRequestStatusApplyConfiguration represents an declarative configuration of the FakeRequestStatus type for use
+// with apply.
+type FakeRequestStatusApplyConfiguration struct {
+	Name     *string `json:"name,omitempty"`
+	Selector *string `json:"selector,omitempty"`
+}
+
+// FakeRequestStatusApplyConfiguration constructs an declarative configuration of the FakeRequestStatus type for use with
+// apply.
+func FakeRequestStatus() *FakeRequestStatusApplyConfiguration {
+	return &FakeRequestStatusApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *FakeRequestStatusApplyConfiguration) WithName(value string) *FakeRequestStatusApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithSelector sets the Selector field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Selector field is set to the value of the last call.
+func (b *FakeRequestStatusApplyConfiguration) WithSelector(value string) *FakeRequestStatusApplyConfiguration {
+	b.Selector = &value
+	return b
+}
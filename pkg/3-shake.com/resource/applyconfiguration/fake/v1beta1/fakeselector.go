@@ -0,0 +1,40 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+// FakeSelectorApplyConfiguration represents an declarative configuration of the FakeSelector type for use
+// with apply.
+type FakeSelectorApplyConfiguration struct {
+	Models []string `json:"models,omitempty"`
+}
+
+// FakeSelectorApplyConfiguration constructs an declarative configuration of the FakeSelector type for use with
+// apply.
+func FakeSelector() *FakeSelectorApplyConfiguration {
+	return &FakeSelectorApplyConfiguration{}
+}
+
+// WithModels adds the given value to the Models field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Models field.
+func (b *FakeSelectorApplyConfiguration) WithModels(values ...string) *FakeSelectorApplyConfiguration {
+	for i := range values {
+		b.Models = append(b.Models, values[i])
+	}
+	return b
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package applyconfiguration
+
+import (
+	v1alpha1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1alpha1"
+	v1beta1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	fakev1alpha1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/applyconfiguration/fake/v1alpha1"
+	fakev1beta1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/applyconfiguration/fake/v1beta1"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ForKind returns an apply configuration type for the given GroupVersionKind, or nil if no
+// apply configuration type exists for the given GroupVersionKind.
+func ForKind(kind schema.GroupVersionKind) interface{} {
+	switch kind {
+	// Group=fake.resource.3-shake.com, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithKind("DeviceClassParameters"):
+		return &fakev1alpha1.DeviceClassParametersApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("DeviceClassParametersSpec"):
+		return &fakev1alpha1.DeviceClassParametersSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("DeviceSelector"):
+		return &fakev1alpha1.DeviceSelectorApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("FakeClaimParameters"):
+		return &fakev1alpha1.FakeClaimParametersApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("FakeClaimParametersSpec"):
+		return &fakev1alpha1.FakeClaimParametersSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("FakeClaimParametersStatus"):
+		return &fakev1alpha1.FakeClaimParametersStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("FakeSelector"):
+		return &fakev1alpha1.FakeSelectorApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("GeneratedObjectReference"):
+		return &fakev1alpha1.GeneratedObjectReferenceApplyConfiguration{}
+
+		// Group=fake.resource.3-shake.com, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithKind("DeviceClassParameters"):
+		return &fakev1beta1.DeviceClassParametersApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("DeviceClassParametersSpec"):
+		return &fakev1beta1.DeviceClassParametersSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("DeviceSelector"):
+		return &fakev1beta1.DeviceSelectorApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FakeClaimParameters"):
+		return &fakev1beta1.FakeClaimParametersApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FakeClaimParametersSpec"):
+		return &fakev1beta1.FakeClaimParametersSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FakeClaimParametersStatus"):
+		return &fakev1beta1.FakeClaimParametersStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FakeDeviceConfig"):
+		return &fakev1beta1.FakeDeviceConfigApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FakeDeviceQuota"):
+		return &fakev1beta1.FakeDeviceQuotaApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FakeDeviceQuotaSpec"):
+		return &fakev1beta1.FakeDeviceQuotaSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FakeDeviceQuotaStatus"):
+		return &fakev1beta1.FakeDeviceQuotaStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FakeDeviceUsage"):
+		return &fakev1beta1.FakeDeviceUsageApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FakeEnvVar"):
+		return &fakev1beta1.FakeEnvVarApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FakeRequest"):
+		return &fakev1beta1.FakeRequestApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FakeRequestStatus"):
+		return &fakev1beta1.FakeRequestStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("FakeSelector"):
+		return &fakev1beta1.FakeSelectorApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("GeneratedObjectReference"):
+		return &fakev1beta1.GeneratedObjectReferenceApplyConfiguration{}
+
+	}
+	return nil
+}
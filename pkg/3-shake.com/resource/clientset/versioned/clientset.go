@@ -22,6 +22,7 @@ import (
 	"net/http"
 
 	fakev1alpha1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned/typed/fake/v1alpha1"
+	fakev1beta1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned/typed/fake/v1beta1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -30,12 +31,14 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	FakeV1alpha1() fakev1alpha1.FakeV1alpha1Interface
+	FakeV1beta1() fakev1beta1.FakeV1beta1Interface
 }
 
 // Clientset contains the clients for groups.
 type Clientset struct {
 	*discovery.DiscoveryClient
 	fakeV1alpha1 *fakev1alpha1.FakeV1alpha1Client
+	fakeV1beta1  *fakev1beta1.FakeV1beta1Client
 }
 
 // FakeV1alpha1 retrieves the FakeV1alpha1Client
@@ -43,6 +46,11 @@ func (c *Clientset) FakeV1alpha1() fakev1alpha1.FakeV1alpha1Interface {
 	return c.fakeV1alpha1
 }
 
+// FakeV1beta1 retrieves the FakeV1beta1Client
+func (c *Clientset) FakeV1beta1() fakev1beta1.FakeV1beta1Interface {
+	return c.fakeV1beta1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -91,6 +99,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.fakeV1beta1, err = fakev1beta1.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
@@ -113,6 +125,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.fakeV1alpha1 = fakev1alpha1.New(c)
+	cs.fakeV1beta1 = fakev1beta1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
@@ -21,6 +21,8 @@ import (
 	clientset "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned"
 	fakev1alpha1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned/typed/fake/v1alpha1"
 	fakefakev1alpha1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned/typed/fake/v1alpha1/fake"
+	fakev1beta1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned/typed/fake/v1beta1"
+	fakefakev1beta1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned/typed/fake/v1beta1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -82,3 +84,8 @@ var (
 func (c *Clientset) FakeV1alpha1() fakev1alpha1.FakeV1alpha1Interface {
 	return &fakefakev1alpha1.FakeFakeV1alpha1{Fake: &c.Fake}
 }
+
+// FakeV1beta1 retrieves the FakeV1beta1Client
+func (c *Clientset) FakeV1beta1() fakev1beta1.FakeV1beta1Interface {
+	return &fakefakev1beta1.FakeFakeV1beta1{Fake: &c.Fake}
+}
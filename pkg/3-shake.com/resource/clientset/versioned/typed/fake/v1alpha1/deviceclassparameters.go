@@ -19,9 +19,12 @@ package v1alpha1
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 	"time"
 
 	v1alpha1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1alpha1"
+	fakev1alpha1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/applyconfiguration/fake/v1alpha1"
 	scheme "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned/scheme"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
@@ -45,6 +48,7 @@ type DeviceClassParametersInterface interface {
 	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.DeviceClassParametersList, error)
 	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
 	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.DeviceClassParameters, err error)
+	Apply(ctx context.Context, deviceClassParameters *fakev1alpha1.DeviceClassParametersApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.DeviceClassParameters, err error)
 	DeviceClassParametersExpansion
 }
 
@@ -165,3 +169,28 @@ func (c *deviceClassParameters) Patch(ctx context.Context, name string, pt types
 		Into(result)
 	return
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied deviceClassParameters.
+func (c *deviceClassParameters) Apply(ctx context.Context, deviceClassParameters *fakev1alpha1.DeviceClassParametersApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.DeviceClassParameters, err error) {
+	if deviceClassParameters == nil {
+		return nil, fmt.Errorf("deviceClassParameters provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(deviceClassParameters)
+	if err != nil {
+		return nil, err
+	}
+	name := deviceClassParameters.Name
+	if name == nil {
+		return nil, fmt.Errorf("deviceClassParameters.Name must be provided to Apply")
+	}
+	result = &v1alpha1.DeviceClassParameters{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Resource("deviceclassparameters").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -19,8 +19,11 @@ package fake
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 
 	v1alpha1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1alpha1"
+	fakev1alpha1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/applyconfiguration/fake/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
 	types "k8s.io/apimachinery/pkg/types"
@@ -118,3 +121,24 @@ func (c *FakeDeviceClassParameters) Patch(ctx context.Context, name string, pt t
 	}
 	return obj.(*v1alpha1.DeviceClassParameters), err
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied deviceClassParameters.
+func (c *FakeDeviceClassParameters) Apply(ctx context.Context, deviceClassParameters *fakev1alpha1.DeviceClassParametersApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.DeviceClassParameters, err error) {
+	if deviceClassParameters == nil {
+		return nil, fmt.Errorf("deviceClassParameters provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(deviceClassParameters)
+	if err != nil {
+		return nil, err
+	}
+	name := deviceClassParameters.Name
+	if name == nil {
+		return nil, fmt.Errorf("deviceClassParameters.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(deviceclassparametersResource, *name, types.ApplyPatchType, data), &v1alpha1.DeviceClassParameters{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.DeviceClassParameters), err
+}
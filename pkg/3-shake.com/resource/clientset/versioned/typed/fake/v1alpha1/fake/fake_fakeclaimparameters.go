@@ -19,8 +19,11 @@ package fake
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 
 	v1alpha1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1alpha1"
+	fakev1alpha1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/applyconfiguration/fake/v1alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
 	types "k8s.io/apimachinery/pkg/types"
@@ -100,6 +103,18 @@ func (c *FakeFakeClaimParameters) Update(ctx context.Context, fakeClaimParameter
 	return obj.(*v1alpha1.FakeClaimParameters), err
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeFakeClaimParameters) UpdateStatus(ctx context.Context, fakeClaimParameters *v1alpha1.FakeClaimParameters, opts v1.UpdateOptions) (*v1alpha1.FakeClaimParameters, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(fakeclaimparametersResource, "status", c.ns, fakeClaimParameters), &v1alpha1.FakeClaimParameters{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.FakeClaimParameters), err
+}
+
 // Delete takes name of the fakeClaimParameters and deletes it. Returns an error if one occurs.
 func (c *FakeFakeClaimParameters) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	_, err := c.Fake.
@@ -126,3 +141,48 @@ func (c *FakeFakeClaimParameters) Patch(ctx context.Context, name string, pt typ
 	}
 	return obj.(*v1alpha1.FakeClaimParameters), err
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied fakeClaimParameters.
+func (c *FakeFakeClaimParameters) Apply(ctx context.Context, fakeClaimParameters *fakev1alpha1.FakeClaimParametersApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.FakeClaimParameters, err error) {
+	if fakeClaimParameters == nil {
+		return nil, fmt.Errorf("fakeClaimParameters provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(fakeClaimParameters)
+	if err != nil {
+		return nil, err
+	}
+	name := fakeClaimParameters.Name
+	if name == nil {
+		return nil, fmt.Errorf("fakeClaimParameters.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(fakeclaimparametersResource, c.ns, *name, types.ApplyPatchType, data), &v1alpha1.FakeClaimParameters{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.FakeClaimParameters), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakeFakeClaimParameters) ApplyStatus(ctx context.Context, fakeClaimParameters *fakev1alpha1.FakeClaimParametersApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.FakeClaimParameters, err error) {
+	if fakeClaimParameters == nil {
+		return nil, fmt.Errorf("fakeClaimParameters provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(fakeClaimParameters)
+	if err != nil {
+		return nil, err
+	}
+	name := fakeClaimParameters.Name
+	if name == nil {
+		return nil, fmt.Errorf("fakeClaimParameters.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(fakeclaimparametersResource, c.ns, *name, types.ApplyPatchType, data, "status"), &v1alpha1.FakeClaimParameters{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.FakeClaimParameters), err
+}
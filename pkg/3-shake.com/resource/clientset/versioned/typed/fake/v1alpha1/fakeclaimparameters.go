@@ -19,9 +19,12 @@ package v1alpha1
 
 import (
 	"context"
+	json "encoding/json"
+	"fmt"
 	"time"
 
 	v1alpha1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1alpha1"
+	fakev1alpha1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/applyconfiguration/fake/v1alpha1"
 	scheme "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned/scheme"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
@@ -39,12 +42,15 @@ type FakeClaimParametersGetter interface {
 type FakeClaimParametersInterface interface {
 	Create(ctx context.Context, fakeClaimParameters *v1alpha1.FakeClaimParameters, opts v1.CreateOptions) (*v1alpha1.FakeClaimParameters, error)
 	Update(ctx context.Context, fakeClaimParameters *v1alpha1.FakeClaimParameters, opts v1.UpdateOptions) (*v1alpha1.FakeClaimParameters, error)
+	UpdateStatus(ctx context.Context, fakeClaimParameters *v1alpha1.FakeClaimParameters, opts v1.UpdateOptions) (*v1alpha1.FakeClaimParameters, error)
 	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
 	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
 	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.FakeClaimParameters, error)
 	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.FakeClaimParametersList, error)
 	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
 	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.FakeClaimParameters, err error)
+	Apply(ctx context.Context, fakeClaimParameters *fakev1alpha1.FakeClaimParametersApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.FakeClaimParameters, err error)
+	ApplyStatus(ctx context.Context, fakeClaimParameters *fakev1alpha1.FakeClaimParametersApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.FakeClaimParameters, err error)
 	FakeClaimParametersExpansion
 }
 
@@ -134,6 +140,22 @@ func (c *fakeClaimParameters) Update(ctx context.Context, fakeClaimParameters *v
 	return
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *fakeClaimParameters) UpdateStatus(ctx context.Context, fakeClaimParameters *v1alpha1.FakeClaimParameters, opts v1.UpdateOptions) (result *v1alpha1.FakeClaimParameters, err error) {
+	result = &v1alpha1.FakeClaimParameters{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("fakeclaimparameters").
+		Name(fakeClaimParameters.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(fakeClaimParameters).
+		Do(ctx).
+		Into(result)
+	return
+}
+
 // Delete takes name of the fakeClaimParameters and deletes it. Returns an error if one occurs.
 func (c *fakeClaimParameters) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	return c.client.Delete().
@@ -175,3 +197,59 @@ func (c *fakeClaimParameters) Patch(ctx context.Context, name string, pt types.P
 		Into(result)
 	return
 }
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied fakeClaimParameters.
+func (c *fakeClaimParameters) Apply(ctx context.Context, fakeClaimParameters *fakev1alpha1.FakeClaimParametersApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.FakeClaimParameters, err error) {
+	if fakeClaimParameters == nil {
+		return nil, fmt.Errorf("fakeClaimParameters provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(fakeClaimParameters)
+	if err != nil {
+		return nil, err
+	}
+	name := fakeClaimParameters.Name
+	if name == nil {
+		return nil, fmt.Errorf("fakeClaimParameters.Name must be provided to Apply")
+	}
+	result = &v1alpha1.FakeClaimParameters{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("fakeclaimparameters").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *fakeClaimParameters) ApplyStatus(ctx context.Context, fakeClaimParameters *fakev1alpha1.FakeClaimParametersApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.FakeClaimParameters, err error) {
+	if fakeClaimParameters == nil {
+		return nil, fmt.Errorf("fakeClaimParameters provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(fakeClaimParameters)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fakeClaimParameters.Name
+	if name == nil {
+		return nil, fmt.Errorf("fakeClaimParameters.Name must be provided to Apply")
+	}
+
+	result = &v1alpha1.FakeClaimParameters{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("fakeclaimparameters").
+		Name(*name).
+		SubResource("status").
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
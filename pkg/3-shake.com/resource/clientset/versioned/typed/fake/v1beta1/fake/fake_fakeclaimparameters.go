@@ -0,0 +1,188 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	fakev1beta1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/applyconfiguration/fake/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeFakeClaimParameters implements FakeClaimParametersInterface
+type FakeFakeClaimParameters struct {
+	Fake *FakeFakeV1beta1
+	ns   string
+}
+
+var fakeclaimparametersResource = v1beta1.SchemeGroupVersion.WithResource("fakeclaimparameters")
+
+var fakeclaimparametersKind = v1beta1.SchemeGroupVersion.WithKind("FakeClaimParameters")
+
+// Get takes name of the fakeClaimParameters, and returns the corresponding fakeClaimParameters object, and an error if there is any.
+func (c *FakeFakeClaimParameters) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.FakeClaimParameters, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(fakeclaimparametersResource, c.ns, name), &v1beta1.FakeClaimParameters{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FakeClaimParameters), err
+}
+
+// List takes label and field selectors, and returns the list of FakeClaimParameters that match those selectors.
+func (c *FakeFakeClaimParameters) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.FakeClaimParametersList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(fakeclaimparametersResource, fakeclaimparametersKind, c.ns, opts), &v1beta1.FakeClaimParametersList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1beta1.FakeClaimParametersList{ListMeta: obj.(*v1beta1.FakeClaimParametersList).ListMeta}
+	for _, item := range obj.(*v1beta1.FakeClaimParametersList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested fakeClaimParameters.
+func (c *FakeFakeClaimParameters) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(fakeclaimparametersResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a fakeClaimParameters and creates it.  Returns the server's representation of the fakeClaimParameters, and an error, if there is any.
+func (c *FakeFakeClaimParameters) Create(ctx context.Context, fakeClaimParameters *v1beta1.FakeClaimParameters, opts v1.CreateOptions) (result *v1beta1.FakeClaimParameters, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(fakeclaimparametersResource, c.ns, fakeClaimParameters), &v1beta1.FakeClaimParameters{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FakeClaimParameters), err
+}
+
+// Update takes the representation of a fakeClaimParameters and updates it. Returns the server's representation of the fakeClaimParameters, and an error, if there is any.
+func (c *FakeFakeClaimParameters) Update(ctx context.Context, fakeClaimParameters *v1beta1.FakeClaimParameters, opts v1.UpdateOptions) (result *v1beta1.FakeClaimParameters, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(fakeclaimparametersResource, c.ns, fakeClaimParameters), &v1beta1.FakeClaimParameters{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FakeClaimParameters), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeFakeClaimParameters) UpdateStatus(ctx context.Context, fakeClaimParameters *v1beta1.FakeClaimParameters, opts v1.UpdateOptions) (*v1beta1.FakeClaimParameters, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(fakeclaimparametersResource, "status", c.ns, fakeClaimParameters), &v1beta1.FakeClaimParameters{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FakeClaimParameters), err
+}
+
+// Delete takes name of the fakeClaimParameters and deletes it. Returns an error if one occurs.
+func (c *FakeFakeClaimParameters) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(fakeclaimparametersResource, c.ns, name, opts), &v1beta1.FakeClaimParameters{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeFakeClaimParameters) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(fakeclaimparametersResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1beta1.FakeClaimParametersList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched fakeClaimParameters.
+func (c *FakeFakeClaimParameters) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.FakeClaimParameters, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(fakeclaimparametersResource, c.ns, name, pt, data, subresources...), &v1beta1.FakeClaimParameters{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FakeClaimParameters), err
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied fakeClaimParameters.
+func (c *FakeFakeClaimParameters) Apply(ctx context.Context, fakeClaimParameters *fakev1beta1.FakeClaimParametersApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.FakeClaimParameters, err error) {
+	if fakeClaimParameters == nil {
+		return nil, fmt.Errorf("fakeClaimParameters provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(fakeClaimParameters)
+	if err != nil {
+		return nil, err
+	}
+	name := fakeClaimParameters.Name
+	if name == nil {
+		return nil, fmt.Errorf("fakeClaimParameters.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(fakeclaimparametersResource, c.ns, *name, types.ApplyPatchType, data), &v1beta1.FakeClaimParameters{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FakeClaimParameters), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakeFakeClaimParameters) ApplyStatus(ctx context.Context, fakeClaimParameters *fakev1beta1.FakeClaimParametersApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.FakeClaimParameters, err error) {
+	if fakeClaimParameters == nil {
+		return nil, fmt.Errorf("fakeClaimParameters provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(fakeClaimParameters)
+	if err != nil {
+		return nil, err
+	}
+	name := fakeClaimParameters.Name
+	if name == nil {
+		return nil, fmt.Errorf("fakeClaimParameters.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(fakeclaimparametersResource, c.ns, *name, types.ApplyPatchType, data, "status"), &v1beta1.FakeClaimParameters{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FakeClaimParameters), err
+}
@@ -0,0 +1,188 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+
+	v1beta1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	fakev1beta1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/applyconfiguration/fake/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeFakeDeviceQuotas implements FakeDeviceQuotaInterface
+type FakeFakeDeviceQuotas struct {
+	Fake *FakeFakeV1beta1
+	ns   string
+}
+
+var fakedevicequotasResource = v1beta1.SchemeGroupVersion.WithResource("fakedevicequotas")
+
+var fakedevicequotasKind = v1beta1.SchemeGroupVersion.WithKind("FakeDeviceQuota")
+
+// Get takes name of the fakeDeviceQuota, and returns the corresponding fakeDeviceQuota object, and an error if there is any.
+func (c *FakeFakeDeviceQuotas) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.FakeDeviceQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(fakedevicequotasResource, c.ns, name), &v1beta1.FakeDeviceQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FakeDeviceQuota), err
+}
+
+// List takes label and field selectors, and returns the list of FakeDeviceQuotas that match those selectors.
+func (c *FakeFakeDeviceQuotas) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.FakeDeviceQuotaList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(fakedevicequotasResource, fakedevicequotasKind, c.ns, opts), &v1beta1.FakeDeviceQuotaList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1beta1.FakeDeviceQuotaList{ListMeta: obj.(*v1beta1.FakeDeviceQuotaList).ListMeta}
+	for _, item := range obj.(*v1beta1.FakeDeviceQuotaList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested fakeDeviceQuotas.
+func (c *FakeFakeDeviceQuotas) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(fakedevicequotasResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a fakeDeviceQuota and creates it.  Returns the server's representation of the fakeDeviceQuota, and an error, if there is any.
+func (c *FakeFakeDeviceQuotas) Create(ctx context.Context, fakeDeviceQuota *v1beta1.FakeDeviceQuota, opts v1.CreateOptions) (result *v1beta1.FakeDeviceQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(fakedevicequotasResource, c.ns, fakeDeviceQuota), &v1beta1.FakeDeviceQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FakeDeviceQuota), err
+}
+
+// Update takes the representation of a fakeDeviceQuota and updates it. Returns the server's representation of the fakeDeviceQuota, and an error, if there is any.
+func (c *FakeFakeDeviceQuotas) Update(ctx context.Context, fakeDeviceQuota *v1beta1.FakeDeviceQuota, opts v1.UpdateOptions) (result *v1beta1.FakeDeviceQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(fakedevicequotasResource, c.ns, fakeDeviceQuota), &v1beta1.FakeDeviceQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FakeDeviceQuota), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeFakeDeviceQuotas) UpdateStatus(ctx context.Context, fakeDeviceQuota *v1beta1.FakeDeviceQuota, opts v1.UpdateOptions) (*v1beta1.FakeDeviceQuota, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(fakedevicequotasResource, "status", c.ns, fakeDeviceQuota), &v1beta1.FakeDeviceQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FakeDeviceQuota), err
+}
+
+// Delete takes name of the fakeDeviceQuota and deletes it. Returns an error if one occurs.
+func (c *FakeFakeDeviceQuotas) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(fakedevicequotasResource, c.ns, name, opts), &v1beta1.FakeDeviceQuota{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeFakeDeviceQuotas) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(fakedevicequotasResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1beta1.FakeDeviceQuotaList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched fakeDeviceQuota.
+func (c *FakeFakeDeviceQuotas) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.FakeDeviceQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(fakedevicequotasResource, c.ns, name, pt, data, subresources...), &v1beta1.FakeDeviceQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FakeDeviceQuota), err
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied fakeDeviceQuota.
+func (c *FakeFakeDeviceQuotas) Apply(ctx context.Context, fakeDeviceQuota *fakev1beta1.FakeDeviceQuotaApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.FakeDeviceQuota, err error) {
+	if fakeDeviceQuota == nil {
+		return nil, fmt.Errorf("fakeDeviceQuota provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(fakeDeviceQuota)
+	if err != nil {
+		return nil, err
+	}
+	name := fakeDeviceQuota.Name
+	if name == nil {
+		return nil, fmt.Errorf("fakeDeviceQuota.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(fakedevicequotasResource, c.ns, *name, types.ApplyPatchType, data), &v1beta1.FakeDeviceQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FakeDeviceQuota), err
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *FakeFakeDeviceQuotas) ApplyStatus(ctx context.Context, fakeDeviceQuota *fakev1beta1.FakeDeviceQuotaApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.FakeDeviceQuota, err error) {
+	if fakeDeviceQuota == nil {
+		return nil, fmt.Errorf("fakeDeviceQuota provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(fakeDeviceQuota)
+	if err != nil {
+		return nil, err
+	}
+	name := fakeDeviceQuota.Name
+	if name == nil {
+		return nil, fmt.Errorf("fakeDeviceQuota.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(fakedevicequotasResource, c.ns, *name, types.ApplyPatchType, data, "status"), &v1beta1.FakeDeviceQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1beta1.FakeDeviceQuota), err
+}
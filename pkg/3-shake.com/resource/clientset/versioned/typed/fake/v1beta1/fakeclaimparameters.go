@@ -0,0 +1,255 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+	"time"
+
+	v1beta1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	fakev1beta1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/applyconfiguration/fake/v1beta1"
+	scheme "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// FakeClaimParametersGetter has a method to return a FakeClaimParametersInterface.
+// A group's client should implement this interface.
+type FakeClaimParametersGetter interface {
+	FakeClaimParameters(namespace string) FakeClaimParametersInterface
+}
+
+// FakeClaimParametersInterface has methods to work with FakeClaimParameters resources.
+type FakeClaimParametersInterface interface {
+	Create(ctx context.Context, fakeClaimParameters *v1beta1.FakeClaimParameters, opts v1.CreateOptions) (*v1beta1.FakeClaimParameters, error)
+	Update(ctx context.Context, fakeClaimParameters *v1beta1.FakeClaimParameters, opts v1.UpdateOptions) (*v1beta1.FakeClaimParameters, error)
+	UpdateStatus(ctx context.Context, fakeClaimParameters *v1beta1.FakeClaimParameters, opts v1.UpdateOptions) (*v1beta1.FakeClaimParameters, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1beta1.FakeClaimParameters, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1beta1.FakeClaimParametersList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.FakeClaimParameters, err error)
+	Apply(ctx context.Context, fakeClaimParameters *fakev1beta1.FakeClaimParametersApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.FakeClaimParameters, err error)
+	ApplyStatus(ctx context.Context, fakeClaimParameters *fakev1beta1.FakeClaimParametersApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.FakeClaimParameters, err error)
+	FakeClaimParametersExpansion
+}
+
+// fakeClaimParameters implements FakeClaimParametersInterface
+type fakeClaimParameters struct {
+	client rest.Interface
+	ns     string
+}
+
+// newFakeClaimParameters returns a FakeClaimParameters
+func newFakeClaimParameters(c *FakeV1beta1Client, namespace string) *fakeClaimParameters {
+	return &fakeClaimParameters{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the fakeClaimParameters, and returns the corresponding fakeClaimParameters object, and an error if there is any.
+func (c *fakeClaimParameters) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.FakeClaimParameters, err error) {
+	result = &v1beta1.FakeClaimParameters{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("fakeclaimparameters").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of FakeClaimParameters that match those selectors.
+func (c *fakeClaimParameters) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.FakeClaimParametersList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1beta1.FakeClaimParametersList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("fakeclaimparameters").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested fakeClaimParameters.
+func (c *fakeClaimParameters) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("fakeclaimparameters").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a fakeClaimParameters and creates it.  Returns the server's representation of the fakeClaimParameters, and an error, if there is any.
+func (c *fakeClaimParameters) Create(ctx context.Context, fakeClaimParameters *v1beta1.FakeClaimParameters, opts v1.CreateOptions) (result *v1beta1.FakeClaimParameters, err error) {
+	result = &v1beta1.FakeClaimParameters{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("fakeclaimparameters").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(fakeClaimParameters).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a fakeClaimParameters and updates it. Returns the server's representation of the fakeClaimParameters, and an error, if there is any.
+func (c *fakeClaimParameters) Update(ctx context.Context, fakeClaimParameters *v1beta1.FakeClaimParameters, opts v1.UpdateOptions) (result *v1beta1.FakeClaimParameters, err error) {
+	result = &v1beta1.FakeClaimParameters{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("fakeclaimparameters").
+		Name(fakeClaimParameters.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(fakeClaimParameters).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *fakeClaimParameters) UpdateStatus(ctx context.Context, fakeClaimParameters *v1beta1.FakeClaimParameters, opts v1.UpdateOptions) (result *v1beta1.FakeClaimParameters, err error) {
+	result = &v1beta1.FakeClaimParameters{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("fakeclaimparameters").
+		Name(fakeClaimParameters.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(fakeClaimParameters).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the fakeClaimParameters and deletes it. Returns an error if one occurs.
+func (c *fakeClaimParameters) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("fakeclaimparameters").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *fakeClaimParameters) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("fakeclaimparameters").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched fakeClaimParameters.
+func (c *fakeClaimParameters) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.FakeClaimParameters, err error) {
+	result = &v1beta1.FakeClaimParameters{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("fakeclaimparameters").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied fakeClaimParameters.
+func (c *fakeClaimParameters) Apply(ctx context.Context, fakeClaimParameters *fakev1beta1.FakeClaimParametersApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.FakeClaimParameters, err error) {
+	if fakeClaimParameters == nil {
+		return nil, fmt.Errorf("fakeClaimParameters provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(fakeClaimParameters)
+	if err != nil {
+		return nil, err
+	}
+	name := fakeClaimParameters.Name
+	if name == nil {
+		return nil, fmt.Errorf("fakeClaimParameters.Name must be provided to Apply")
+	}
+	result = &v1beta1.FakeClaimParameters{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("fakeclaimparameters").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *fakeClaimParameters) ApplyStatus(ctx context.Context, fakeClaimParameters *fakev1beta1.FakeClaimParametersApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.FakeClaimParameters, err error) {
+	if fakeClaimParameters == nil {
+		return nil, fmt.Errorf("fakeClaimParameters provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(fakeClaimParameters)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fakeClaimParameters.Name
+	if name == nil {
+		return nil, fmt.Errorf("fakeClaimParameters.Name must be provided to Apply")
+	}
+
+	result = &v1beta1.FakeClaimParameters{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("fakeclaimparameters").
+		Name(*name).
+		SubResource("status").
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,255 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+	"time"
+
+	v1beta1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	fakev1beta1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/applyconfiguration/fake/v1beta1"
+	scheme "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// FakeDeviceQuotasGetter has a method to return a FakeDeviceQuotaInterface.
+// A group's client should implement this interface.
+type FakeDeviceQuotasGetter interface {
+	FakeDeviceQuotas(namespace string) FakeDeviceQuotaInterface
+}
+
+// FakeDeviceQuotaInterface has methods to work with FakeDeviceQuota resources.
+type FakeDeviceQuotaInterface interface {
+	Create(ctx context.Context, fakeDeviceQuota *v1beta1.FakeDeviceQuota, opts v1.CreateOptions) (*v1beta1.FakeDeviceQuota, error)
+	Update(ctx context.Context, fakeDeviceQuota *v1beta1.FakeDeviceQuota, opts v1.UpdateOptions) (*v1beta1.FakeDeviceQuota, error)
+	UpdateStatus(ctx context.Context, fakeDeviceQuota *v1beta1.FakeDeviceQuota, opts v1.UpdateOptions) (*v1beta1.FakeDeviceQuota, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1beta1.FakeDeviceQuota, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1beta1.FakeDeviceQuotaList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.FakeDeviceQuota, err error)
+	Apply(ctx context.Context, fakeDeviceQuota *fakev1beta1.FakeDeviceQuotaApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.FakeDeviceQuota, err error)
+	ApplyStatus(ctx context.Context, fakeDeviceQuota *fakev1beta1.FakeDeviceQuotaApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.FakeDeviceQuota, err error)
+	FakeDeviceQuotaExpansion
+}
+
+// fakeDeviceQuotas implements FakeDeviceQuotaInterface
+type fakeDeviceQuotas struct {
+	client rest.Interface
+	ns     string
+}
+
+// newFakeDeviceQuotas returns a FakeDeviceQuotas
+func newFakeDeviceQuotas(c *FakeV1beta1Client, namespace string) *fakeDeviceQuotas {
+	return &fakeDeviceQuotas{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the fakeDeviceQuota, and returns the corresponding fakeDeviceQuota object, and an error if there is any.
+func (c *fakeDeviceQuotas) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1beta1.FakeDeviceQuota, err error) {
+	result = &v1beta1.FakeDeviceQuota{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("fakedevicequotas").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of FakeDeviceQuotas that match those selectors.
+func (c *fakeDeviceQuotas) List(ctx context.Context, opts v1.ListOptions) (result *v1beta1.FakeDeviceQuotaList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1beta1.FakeDeviceQuotaList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("fakedevicequotas").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested fakeDeviceQuotas.
+func (c *fakeDeviceQuotas) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("fakedevicequotas").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a fakeDeviceQuota and creates it.  Returns the server's representation of the fakeDeviceQuota, and an error, if there is any.
+func (c *fakeDeviceQuotas) Create(ctx context.Context, fakeDeviceQuota *v1beta1.FakeDeviceQuota, opts v1.CreateOptions) (result *v1beta1.FakeDeviceQuota, err error) {
+	result = &v1beta1.FakeDeviceQuota{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("fakedevicequotas").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(fakeDeviceQuota).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a fakeDeviceQuota and updates it. Returns the server's representation of the fakeDeviceQuota, and an error, if there is any.
+func (c *fakeDeviceQuotas) Update(ctx context.Context, fakeDeviceQuota *v1beta1.FakeDeviceQuota, opts v1.UpdateOptions) (result *v1beta1.FakeDeviceQuota, err error) {
+	result = &v1beta1.FakeDeviceQuota{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("fakedevicequotas").
+		Name(fakeDeviceQuota.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(fakeDeviceQuota).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *fakeDeviceQuotas) UpdateStatus(ctx context.Context, fakeDeviceQuota *v1beta1.FakeDeviceQuota, opts v1.UpdateOptions) (result *v1beta1.FakeDeviceQuota, err error) {
+	result = &v1beta1.FakeDeviceQuota{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("fakedevicequotas").
+		Name(fakeDeviceQuota.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(fakeDeviceQuota).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the fakeDeviceQuota and deletes it. Returns an error if one occurs.
+func (c *fakeDeviceQuotas) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("fakedevicequotas").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *fakeDeviceQuotas) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("fakedevicequotas").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched fakeDeviceQuota.
+func (c *fakeDeviceQuotas) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1beta1.FakeDeviceQuota, err error) {
+	result = &v1beta1.FakeDeviceQuota{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("fakedevicequotas").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied fakeDeviceQuota.
+func (c *fakeDeviceQuotas) Apply(ctx context.Context, fakeDeviceQuota *fakev1beta1.FakeDeviceQuotaApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.FakeDeviceQuota, err error) {
+	if fakeDeviceQuota == nil {
+		return nil, fmt.Errorf("fakeDeviceQuota provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(fakeDeviceQuota)
+	if err != nil {
+		return nil, err
+	}
+	name := fakeDeviceQuota.Name
+	if name == nil {
+		return nil, fmt.Errorf("fakeDeviceQuota.Name must be provided to Apply")
+	}
+	result = &v1beta1.FakeDeviceQuota{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("fakedevicequotas").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// ApplyStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating ApplyStatus().
+func (c *fakeDeviceQuotas) ApplyStatus(ctx context.Context, fakeDeviceQuota *fakev1beta1.FakeDeviceQuotaApplyConfiguration, opts v1.ApplyOptions) (result *v1beta1.FakeDeviceQuota, err error) {
+	if fakeDeviceQuota == nil {
+		return nil, fmt.Errorf("fakeDeviceQuota provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(fakeDeviceQuota)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fakeDeviceQuota.Name
+	if name == nil {
+		return nil, fmt.Errorf("fakeDeviceQuota.Name must be provided to Apply")
+	}
+
+	result = &v1beta1.FakeDeviceQuota{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("fakedevicequotas").
+		Name(*name).
+		SubResource("status").
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
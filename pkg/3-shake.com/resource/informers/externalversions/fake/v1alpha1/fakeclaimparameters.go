@@ -0,0 +1,89 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	time "time"
+
+	fakev1alpha1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1alpha1"
+	versioned "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned"
+	internalinterfaces "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/informers/externalversions/internalinterfaces"
+	v1alpha1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/listers/fake/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// FakeClaimParametersInformer provides access to a shared informer and lister for
+// FakeClaimParameters.
+type FakeClaimParametersInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha1.FakeClaimParametersLister
+}
+
+type fakeClaimParametersInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewFakeClaimParametersInformer constructs a new informer for FakeClaimParameters type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFakeClaimParametersInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredFakeClaimParametersInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredFakeClaimParametersInformer constructs a new informer for FakeClaimParameters type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredFakeClaimParametersInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.FakeV1alpha1().FakeClaimParameters(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.FakeV1alpha1().FakeClaimParameters(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&fakev1alpha1.FakeClaimParameters{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *fakeClaimParametersInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredFakeClaimParametersInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *fakeClaimParametersInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&fakev1alpha1.FakeClaimParameters{}, f.defaultInformer)
+}
+
+func (f *fakeClaimParametersInformer) Lister() v1alpha1.FakeClaimParametersLister {
+	return v1alpha1.NewFakeClaimParametersLister(f.Informer().GetIndexer())
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"context"
+	time "time"
+
+	fakev1beta1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	versioned "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned"
+	internalinterfaces "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/informers/externalversions/internalinterfaces"
+	v1beta1 "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/listers/fake/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// FakeDeviceQuotaInformer provides access to a shared informer and lister for
+// FakeDeviceQuotas.
+type FakeDeviceQuotaInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1beta1.FakeDeviceQuotaLister
+}
+
+type fakeDeviceQuotaInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewFakeDeviceQuotaInformer constructs a new informer for FakeDeviceQuota type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFakeDeviceQuotaInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredFakeDeviceQuotaInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredFakeDeviceQuotaInformer constructs a new informer for FakeDeviceQuota type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredFakeDeviceQuotaInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.FakeV1beta1().FakeDeviceQuotas(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.FakeV1beta1().FakeDeviceQuotas(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&fakev1beta1.FakeDeviceQuota{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *fakeDeviceQuotaInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredFakeDeviceQuotaInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *fakeDeviceQuotaInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&fakev1beta1.FakeDeviceQuota{}, f.defaultInformer)
+}
+
+func (f *fakeDeviceQuotaInformer) Lister() v1beta1.FakeDeviceQuotaLister {
+	return v1beta1.NewFakeDeviceQuotaLister(f.Informer().GetIndexer())
+}
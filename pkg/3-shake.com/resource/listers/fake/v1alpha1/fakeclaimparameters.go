@@ -0,0 +1,98 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// FakeClaimParametersLister helps list FakeClaimParameters.
+// All objects returned here must be treated as read-only.
+type FakeClaimParametersLister interface {
+	// List lists all FakeClaimParameters in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.FakeClaimParameters, err error)
+	// FakeClaimParameters returns an object that can list and get FakeClaimParameters.
+	FakeClaimParameters(namespace string) FakeClaimParametersNamespaceLister
+	FakeClaimParametersListerExpansion
+}
+
+// fakeClaimParametersLister implements the FakeClaimParametersLister interface.
+type fakeClaimParametersLister struct {
+	indexer cache.Indexer
+}
+
+// NewFakeClaimParametersLister returns a new FakeClaimParametersLister.
+func NewFakeClaimParametersLister(indexer cache.Indexer) FakeClaimParametersLister {
+	return &fakeClaimParametersLister{indexer: indexer}
+}
+
+// List lists all FakeClaimParameters in the indexer.
+func (s *fakeClaimParametersLister) List(selector labels.Selector) (ret []*v1alpha1.FakeClaimParameters, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.FakeClaimParameters))
+	})
+	return ret, err
+}
+
+// FakeClaimParameters returns an object that can list and get FakeClaimParameters.
+func (s *fakeClaimParametersLister) FakeClaimParameters(namespace string) FakeClaimParametersNamespaceLister {
+	return fakeClaimParametersNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// FakeClaimParametersNamespaceLister helps list and get FakeClaimParameters.
+// All objects returned here must be treated as read-only.
+type FakeClaimParametersNamespaceLister interface {
+	// List lists all FakeClaimParameters in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.FakeClaimParameters, err error)
+	// Get retrieves the FakeClaimParameters from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.FakeClaimParameters, error)
+	FakeClaimParametersNamespaceListerExpansion
+}
+
+// fakeClaimParametersNamespaceLister implements the FakeClaimParametersNamespaceLister
+// interface.
+type fakeClaimParametersNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all FakeClaimParameters in the indexer for a given namespace.
+func (s fakeClaimParametersNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.FakeClaimParameters, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.FakeClaimParameters))
+	})
+	return ret, err
+}
+
+// Get retrieves the FakeClaimParameters from the indexer for a given namespace and name.
+func (s fakeClaimParametersNamespaceLister) Get(name string) (*v1alpha1.FakeClaimParameters, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("fakeclaimparameters"), name)
+	}
+	return obj.(*v1alpha1.FakeClaimParameters), nil
+}
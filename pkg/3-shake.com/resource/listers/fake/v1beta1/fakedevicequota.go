@@ -0,0 +1,98 @@
+/*
+ * Copyright Year The Kubernetes Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1beta1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// FakeDeviceQuotaLister helps list FakeDeviceQuotas.
+// All objects returned here must be treated as read-only.
+type FakeDeviceQuotaLister interface {
+	// List lists all FakeDeviceQuotas in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1beta1.FakeDeviceQuota, err error)
+	// FakeDeviceQuotas returns an object that can list and get FakeDeviceQuotas.
+	FakeDeviceQuotas(namespace string) FakeDeviceQuotaNamespaceLister
+	FakeDeviceQuotaListerExpansion
+}
+
+// fakeDeviceQuotaLister implements the FakeDeviceQuotaLister interface.
+type fakeDeviceQuotaLister struct {
+	indexer cache.Indexer
+}
+
+// NewFakeDeviceQuotaLister returns a new FakeDeviceQuotaLister.
+func NewFakeDeviceQuotaLister(indexer cache.Indexer) FakeDeviceQuotaLister {
+	return &fakeDeviceQuotaLister{indexer: indexer}
+}
+
+// List lists all FakeDeviceQuotas in the indexer.
+func (s *fakeDeviceQuotaLister) List(selector labels.Selector) (ret []*v1beta1.FakeDeviceQuota, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1beta1.FakeDeviceQuota))
+	})
+	return ret, err
+}
+
+// FakeDeviceQuotas returns an object that can list and get FakeDeviceQuotas.
+func (s *fakeDeviceQuotaLister) FakeDeviceQuotas(namespace string) FakeDeviceQuotaNamespaceLister {
+	return fakeDeviceQuotaNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// FakeDeviceQuotaNamespaceLister helps list and get FakeDeviceQuotas.
+// All objects returned here must be treated as read-only.
+type FakeDeviceQuotaNamespaceLister interface {
+	// List lists all FakeDeviceQuotas in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1beta1.FakeDeviceQuota, err error)
+	// Get retrieves the FakeDeviceQuota from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1beta1.FakeDeviceQuota, error)
+	FakeDeviceQuotaNamespaceListerExpansion
+}
+
+// fakeDeviceQuotaNamespaceLister implements the FakeDeviceQuotaNamespaceLister
+// interface.
+type fakeDeviceQuotaNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all FakeDeviceQuotas in the indexer for a given namespace.
+func (s fakeDeviceQuotaNamespaceLister) List(selector labels.Selector) (ret []*v1beta1.FakeDeviceQuota, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1beta1.FakeDeviceQuota))
+	})
+	return ret, err
+}
+
+// Get retrieves the FakeDeviceQuota from the indexer for a given namespace and name.
+func (s fakeDeviceQuotaNamespaceLister) Get(name string) (*v1beta1.FakeDeviceQuota, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1beta1.Resource("fakedevicequota"), name)
+	}
+	return obj.(*v1beta1.FakeDeviceQuota), nil
+}
@@ -0,0 +1,745 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	corev1 "k8s.io/api/core/v1"
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/wait"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	resourceac "k8s.io/client-go/applyconfigurations/resource/v1alpha2"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	fakeac "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/applyconfiguration/fake/v1beta1"
+	shakeclientset "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned"
+	shakeinformers "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/informers/externalversions"
+	fakelisters "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/listers/fake/v1beta1"
+	"github.com/toVersus/fake-dra-driver/pkg/vendorparameters"
+)
+
+const (
+	generatedResourceClaimParametersPrefix = "resource-claim-parameters-"
+
+	// fieldManager owns the fields applied by the generator
+	fieldManager = "fake-dra-controller"
+
+	// generatedFromIndex is the name of the ResourceClaimParameters index
+	// keyed on the object they were generated from
+	generatedFromIndex = "generatedFrom"
+
+	// mutationCacheTTL is how long written objects are kept in the mutation
+	// cache until the informer is expected to have caught up with them
+	mutationCacheTTL = time.Hour
+)
+
+// ClaimParametersGenerator reconciles FakeClaimParameters objects into
+// ResourceClaimParameters objects understood by the scheduler.
+type ClaimParametersGenerator struct {
+	clientset      kubernetes.Interface
+	shakeclientset shakeclientset.Interface
+	workers        int
+	// driverNames are the drivers requests may allocate devices from,
+	// requests without a driver name use defaultDriverName
+	driverNames       sets.Set[string]
+	defaultDriverName string
+	// adminAccessNamespaces are the namespaces FakeClaimParameters may
+	// request admin access in
+	adminAccessNamespaces sets.Set[string]
+	// dryRun is passed to every write so that the API server validates but
+	// does not persist it
+	dryRun []string
+
+	recorder record.EventRecorder
+
+	fakeClaimParametersInformer     cache.SharedIndexInformer
+	fakeClaimParametersLister       fakelisters.FakeClaimParametersLister
+	resourceClaimParametersInformer cache.SharedIndexInformer
+	// resourceClaimInformer is only used to count the claims referring to
+	// each FakeClaimParameters for metrics
+	resourceClaimInformer cache.SharedIndexInformer
+	informerFactory       informers.SharedInformerFactory
+	shakeInformerFactory  shakeinformers.SharedInformerFactory
+
+	// resourceClaimParametersCache serves reads of ResourceClaimParameters
+	// and also holds the objects written by the generator until the informer
+	// has observed them
+	resourceClaimParametersCache cache.MutationCache
+
+	queue workqueue.RateLimitingInterface
+
+	// reconcileDuration and reconcileTotal are updated by the workers
+	reconcileDuration *prometheus.HistogramVec
+	reconcileTotal    *prometheus.CounterVec
+}
+
+func NewClaimParametersGenerator(clientset kubernetes.Interface, shakeclientset shakeclientset.Interface, opts Options) (*ClaimParametersGenerator, error) {
+	if err := opts.complete(); err != nil {
+		return nil, err
+	}
+
+	// Set up informer to watch for ResourceClaimParameters objects so that
+	// manual edits and deletions of generated objects can be corrected
+	informerFactory := informers.NewSharedInformerFactory(clientset, 0)
+	shakeInformerFactory := shakeinformers.NewSharedInformerFactory(shakeclientset, 0)
+	fakeClaimParameters := shakeInformerFactory.Fake().V1beta1().FakeClaimParameters()
+
+	g := &ClaimParametersGenerator{
+		clientset:                       clientset,
+		shakeclientset:                  shakeclientset,
+		workers:                         opts.Workers,
+		driverNames:                     sets.New(opts.DriverNames...),
+		defaultDriverName:               opts.DriverNames[0],
+		adminAccessNamespaces:           sets.New(opts.AdminAccessNamespaces...),
+		dryRun:                          opts.dryRun(),
+		recorder:                        opts.Recorder,
+		fakeClaimParametersInformer:     fakeClaimParameters.Informer(),
+		fakeClaimParametersLister:       fakeClaimParameters.Lister(),
+		resourceClaimParametersInformer: informerFactory.Resource().V1alpha2().ResourceClaimParameters().Informer(),
+		resourceClaimInformer:           informerFactory.Resource().V1alpha2().ResourceClaims().Informer(),
+		informerFactory:                 informerFactory,
+		shakeInformerFactory:            shakeInformerFactory,
+		queue: workqueue.NewRateLimitingQueueWithConfig(
+			workqueue.DefaultControllerRateLimiter(),
+			workqueue.RateLimitingQueueConfig{Name: "fakeclaimparameters"},
+		),
+		reconcileDuration: newReconcileDuration(),
+		reconcileTotal:    newReconcileTotal(),
+	}
+
+	// Index ResourceClaimParameters on the object they were generated from so
+	// that they can be looked up without listing the namespace
+	err := g.resourceClaimParametersInformer.AddIndexers(cache.Indexers{generatedFromIndex: generatedFromIndexFunc})
+	if err != nil {
+		return nil, fmt.Errorf("error adding ResourceClaimParameters indexer: %w", err)
+	}
+	err = g.resourceClaimInformer.AddIndexers(cache.Indexers{parametersRefIndex: parametersRefIndexFunc})
+	if err != nil {
+		return nil, fmt.Errorf("error adding ResourceClaim indexer: %w", err)
+	}
+	g.resourceClaimParametersCache = cache.NewIntegerResourceVersionMutationCache(
+		g.resourceClaimParametersInformer.GetStore(),
+		g.resourceClaimParametersInformer.GetIndexer(),
+		mutationCacheTTL,
+		// Objects missing from the informer must be reported as missing so
+		// that deleted ResourceClaimParameters get recreated
+		false, // includeAdds
+	)
+
+	// Set up handler for events
+	_, err = g.fakeClaimParametersInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: g.enqueue,
+		UpdateFunc: func(oldObj any, newObj any) {
+			g.enqueue(newObj)
+		},
+		DeleteFunc: g.enqueue,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error adding FakeClaimParameters event handler: %w", err)
+	}
+
+	// Only react to ResourceClaimParameters generated from FakeClaimParameters
+	_, err = g.resourceClaimParametersInformer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: isGeneratedFromFakeClaimParameters,
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: g.enqueueGeneratedFrom,
+			UpdateFunc: func(oldObj any, newObj any) {
+				g.enqueueGeneratedFrom(newObj)
+			},
+			DeleteFunc: g.enqueueGeneratedFrom,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error adding ResourceClaimParameters event handler: %w", err)
+	}
+
+	return g, nil
+}
+
+// Run starts the informers and workers and blocks until the context is done.
+func (g *ClaimParametersGenerator) Run(ctx context.Context) error {
+	logger := klog.FromContext(ctx)
+	defer utilruntime.HandleCrash()
+	defer g.queue.ShutDown()
+
+	// Start informers
+	g.informerFactory.Start(ctx.Done())
+	defer g.informerFactory.Shutdown()
+	g.shakeInformerFactory.Start(ctx.Done())
+	defer g.shakeInformerFactory.Shutdown()
+
+	logger.V(2).Info("Waiting for informer caches to sync")
+	if !cache.WaitForNamedCacheSync("fakeclaimparameters", ctx.Done(),
+		g.fakeClaimParametersInformer.HasSynced,
+		g.resourceClaimParametersInformer.HasSynced,
+		g.resourceClaimInformer.HasSynced,
+	) {
+		return fmt.Errorf("error syncing informer caches")
+	}
+
+	for i := 0; i < g.workers; i++ {
+		go wait.UntilWithContext(ctx, g.runWorker, time.Second)
+	}
+
+	<-ctx.Done()
+	logger.Info("Shutting down ResourceClaimParameters generator")
+	return nil
+}
+
+func (g *ClaimParametersGenerator) enqueue(obj any) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("error getting key for FakeClaimParameters: %w", err))
+		return
+	}
+	g.queue.Add(key)
+}
+
+func (g *ClaimParametersGenerator) enqueueGeneratedFrom(obj any) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	resourceClaimParameters, ok := obj.(*resourceapi.ResourceClaimParameters)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("unexpected object type %T", obj))
+		return
+	}
+	g.queue.Add(resourceClaimParameters.Namespace + "/" + resourceClaimParameters.GeneratedFrom.Name)
+}
+
+func (g *ClaimParametersGenerator) runWorker(ctx context.Context) {
+	for g.processNextWorkItem(ctx) {
+	}
+}
+
+func (g *ClaimParametersGenerator) processNextWorkItem(ctx context.Context) bool {
+	key, quit := g.queue.Get()
+	if quit {
+		return false
+	}
+	defer g.queue.Done(key)
+
+	logger := klog.FromContext(ctx).WithValues("fakeClaimParameters", key)
+	ctx = klog.NewContext(ctx, logger)
+
+	start := time.Now()
+	err := g.sync(ctx, key.(string))
+	result := reconcileResultSuccess
+	if err != nil {
+		result = reconcileResultError
+	}
+	g.reconcileDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+	g.reconcileTotal.WithLabelValues(result).Inc()
+
+	if err != nil {
+		logger.Error(err, "Error syncing FakeClaimParameters, requeuing", "retries", g.queue.NumRequeues(key))
+		g.queue.AddRateLimited(key)
+		return true
+	}
+
+	g.queue.Forget(key)
+	return true
+}
+
+// sync puts the ResourceClaimParameters generated from the given
+// FakeClaimParameters into the desired state. It is idempotent and safe to
+// retry: generated objects have a deterministic name, objects without an
+// owning FakeClaimParameters are deleted and hand-edited objects are
+// corrected.
+func (g *ClaimParametersGenerator) sync(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return fmt.Errorf("error splitting key %q: %w", key, err)
+	}
+
+	fakeClaimParameters, err := g.fakeClaimParametersLister.FakeClaimParameters(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		logger.V(2).Info("FakeClaimParameters no longer exists, deleting generated ResourceClaimParameters")
+		return g.deleteResourceClaimParameters(ctx, namespace, name, "")
+	}
+	if err != nil {
+		return fmt.Errorf("error getting FakeClaimParameters from cache: %w", err)
+	}
+
+	status := fakeClaimParameters.Status.DeepCopy()
+	status.ObservedGeneration = fakeClaimParameters.Generation
+	syncErr := g.syncResourceClaimParameters(ctx, fakeClaimParameters, status)
+	g.recordGenerationEvent(fakeClaimParameters, status)
+	if err := g.updateStatus(ctx, fakeClaimParameters, status); err != nil {
+		return fmt.Errorf("error updating FakeClaimParameters status: %w", err)
+	}
+	return syncErr
+}
+
+// recordGenerationEvent records an Event on the FakeClaimParameters when the
+// outcome of the generation changed, so that retries and status updates do
+// not repeat it.
+func (g *ClaimParametersGenerator) recordGenerationEvent(fakeClaimParameters *fakecrd.FakeClaimParameters, status *fakecrd.FakeClaimParametersStatus) {
+	previous := meta.FindStatusCondition(fakeClaimParameters.Status.Conditions, fakecrd.FakeClaimParametersGenerated)
+	current := meta.FindStatusCondition(status.Conditions, fakecrd.FakeClaimParametersGenerated)
+	if current == nil {
+		return
+	}
+	if previous != nil && previous.Status == current.Status && previous.Reason == current.Reason &&
+		previous.Message == current.Message && previous.ObservedGeneration == current.ObservedGeneration {
+		return
+	}
+
+	switch {
+	case current.Status == metav1.ConditionTrue && status.Generated != nil:
+		g.recorder.Eventf(fakeClaimParameters, corev1.EventTypeNormal, fakecrd.FakeClaimParametersReasonGenerationSucceeded,
+			"Generated ResourceClaimParameters %s", status.Generated.Name)
+	case current.Reason == fakecrd.FakeClaimParametersReasonInvalidSpec:
+		message := current.Message
+		if invalid := meta.FindStatusCondition(status.Conditions, fakecrd.FakeClaimParametersInvalid); invalid != nil {
+			message = invalid.Message
+		}
+		g.recorder.Event(fakeClaimParameters, corev1.EventTypeWarning, fakecrd.FakeClaimParametersReasonInvalidSpec, message)
+	case current.Status == metav1.ConditionFalse:
+		g.recorder.Event(fakeClaimParameters, corev1.EventTypeWarning, fakecrd.FakeClaimParametersReasonGenerationFailed, current.Message)
+	}
+}
+
+// syncResourceClaimParameters generates the ResourceClaimParameters for a
+// FakeClaimParameters object and records the outcome in status.
+func (g *ClaimParametersGenerator) syncResourceClaimParameters(ctx context.Context, fakeClaimParameters *fakecrd.FakeClaimParameters, status *fakecrd.FakeClaimParametersStatus) error {
+	logger := klog.FromContext(ctx)
+	namespace, name, generation := fakeClaimParameters.Namespace, fakeClaimParameters.Name, fakeClaimParameters.Generation
+
+	var reason, message string
+	if errs := fakecrd.ValidateFakeClaimParametersSpec(&fakeClaimParameters.Spec, field.NewPath("spec")); len(errs) > 0 {
+		reason, message = fakecrd.FakeClaimParametersReasonInvalidSpec, errs.ToAggregate().Error()
+	} else if fakeClaimParameters.Spec.AdminAccess && !g.adminAccessNamespaces.Has(namespace) {
+		reason, message = fakecrd.FakeClaimParametersReasonAdminAccessDenied, fmt.Sprintf("admin access is not allowed in namespace %s", namespace)
+	} else if request, ok := g.unknownDriverRequest(&fakeClaimParameters.Spec); ok {
+		reason, message = fakecrd.FakeClaimParametersReasonUnknownDriver, fmt.Sprintf("request %s allocates from driver %s, which is not among %s", request.Name, request.DriverName, strings.Join(sets.List(g.driverNames), ", "))
+	}
+	if reason != "" {
+		logger.Info("FakeClaimParameters is invalid", "reason", message)
+		setCondition(status, generation, fakecrd.FakeClaimParametersAccepted, metav1.ConditionFalse, reason, message)
+		setCondition(status, generation, fakecrd.FakeClaimParametersInvalid, metav1.ConditionTrue, reason, message)
+		setCondition(status, generation, fakecrd.FakeClaimParametersGenerated, metav1.ConditionFalse, reason, "ResourceClaimParameters are not generated from a rejected spec")
+		status.Generated = nil
+		status.Selector = ""
+		status.Requests = nil
+
+		// Do not leave parameters for an earlier spec behind, claims would
+		// otherwise be allocated with them. Retrying is pointless until the
+		// spec changes.
+		return g.deleteResourceClaimParameters(ctx, namespace, name, "")
+	}
+	setCondition(status, generation, fakecrd.FakeClaimParametersAccepted, metav1.ConditionTrue, fakecrd.FakeClaimParametersReasonValidSpec, "")
+	setCondition(status, generation, fakecrd.FakeClaimParametersInvalid, metav1.ConditionFalse, fakecrd.FakeClaimParametersReasonValidSpec, "")
+
+	resourceClaimParameters, err := newResourceClaimParametersFromFakeClaimParameters(fakeClaimParameters, g.defaultDriverName)
+	if err != nil {
+		err = fmt.Errorf("error building new ResourceClaimParameters object from a FakeClaimParameters object: %w", err)
+		setCondition(status, generation, fakecrd.FakeClaimParametersGenerated, metav1.ConditionFalse, fakecrd.FakeClaimParametersReasonGenerationFailed, err.Error())
+		return err
+	}
+
+	generated, err := g.applyResourceClaimParameters(ctx, resourceClaimParameters)
+	if err == nil {
+		// Remove leftovers such as objects created with a generated name by
+		// earlier versions of the generator
+		err = g.deleteResourceClaimParameters(ctx, namespace, name, resourceClaimParameters.Name)
+	}
+	if err != nil {
+		setCondition(status, generation, fakecrd.FakeClaimParametersGenerated, metav1.ConditionFalse, fakecrd.FakeClaimParametersReasonGenerationFailed, err.Error())
+		return err
+	}
+
+	setCondition(status, generation, fakecrd.FakeClaimParametersGenerated, metav1.ConditionTrue, fakecrd.FakeClaimParametersReasonGenerationSucceeded, "")
+	status.Generated = &fakecrd.GeneratedObjectReference{
+		APIGroup: resourceapi.GroupName,
+		Kind:     "ResourceClaimParameters",
+		Name:     generated.Name,
+		UID:      generated.UID,
+	}
+	setRequestSelectors(status, defaultedFakeClaimParametersSpec(&fakeClaimParameters.Spec))
+	return nil
+}
+
+// unknownDriverRequest returns the first request of the spec allocating from
+// a driver the generator is not configured with.
+func (g *ClaimParametersGenerator) unknownDriverRequest(spec *fakecrd.FakeClaimParametersSpec) (fakecrd.FakeRequest, bool) {
+	for _, request := range spec.GetRequests() {
+		if request.DriverName != "" && !g.driverNames.Has(request.DriverName) {
+			return request, true
+		}
+	}
+	return fakecrd.FakeRequest{}, false
+}
+
+// setRequestSelectors records the rendered selector of each request of the
+// defaulted spec in status.
+func setRequestSelectors(status *fakecrd.FakeClaimParametersStatus, spec *fakecrd.FakeClaimParametersSpec) {
+	status.Selector = ""
+	if len(spec.Requests) == 0 && !spec.AdminAccess {
+		status.Selector = spec.Selector.ToNamedResourcesSelector()
+	}
+	status.Requests = nil
+	for _, request := range spec.GetRequests() {
+		status.Requests = append(status.Requests, fakecrd.FakeRequestStatus{
+			Name:     request.Name,
+			Selector: request.Selector.ToNamedResourcesSelector(),
+		})
+	}
+}
+
+func (g *ClaimParametersGenerator) updateStatus(ctx context.Context, fakeClaimParameters *fakecrd.FakeClaimParameters, status *fakecrd.FakeClaimParametersStatus) error {
+	if apiequality.Semantic.DeepEqual(&fakeClaimParameters.Status, status) {
+		return nil
+	}
+
+	apply := fakeac.FakeClaimParameters(fakeClaimParameters.Name, fakeClaimParameters.Namespace).
+		WithStatus(fakeClaimParametersStatusApplyConfiguration(status))
+	_, err := g.shakeclientset.FakeV1beta1().FakeClaimParameters(fakeClaimParameters.Namespace).ApplyStatus(ctx, apply, g.applyOptions())
+	if err != nil {
+		return err
+	}
+	klog.FromContext(ctx).V(4).Info("Applied FakeClaimParameters status")
+	return nil
+}
+
+func (g *ClaimParametersGenerator) applyOptions() metav1.ApplyOptions {
+	return metav1.ApplyOptions{FieldManager: fieldManager, Force: true, DryRun: g.dryRun}
+}
+
+func fakeClaimParametersStatusApplyConfiguration(status *fakecrd.FakeClaimParametersStatus) *fakeac.FakeClaimParametersStatusApplyConfiguration {
+	apply := fakeac.FakeClaimParametersStatus().WithObservedGeneration(status.ObservedGeneration)
+	if status.Generated != nil {
+		apply.WithGenerated(fakeac.GeneratedObjectReference().
+			WithAPIGroup(status.Generated.APIGroup).
+			WithKind(status.Generated.Kind).
+			WithName(status.Generated.Name).
+			WithUID(status.Generated.UID))
+	}
+	if status.Selector != "" {
+		apply.WithSelector(status.Selector)
+	}
+	for _, request := range status.Requests {
+		apply.WithRequests(fakeac.FakeRequestStatus().
+			WithName(request.Name).
+			WithSelector(request.Selector))
+	}
+	for _, condition := range status.Conditions {
+		apply.WithConditions(metav1ac.Condition().
+			WithType(condition.Type).
+			WithStatus(condition.Status).
+			WithObservedGeneration(condition.ObservedGeneration).
+			WithLastTransitionTime(condition.LastTransitionTime).
+			WithReason(condition.Reason).
+			WithMessage(condition.Message))
+	}
+	return apply
+}
+
+func setCondition(status *fakecrd.FakeClaimParametersStatus, generation int64, conditionType string, conditionStatus metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// applyResourceClaimParameters applies the fields owned by the generator with
+// server-side apply when the cached object has drifted from them. Fields set
+// by other managers, such as labels and annotations, are left alone.
+func (g *ClaimParametersGenerator) applyResourceClaimParameters(ctx context.Context, resourceClaimParameters *resourceapi.ResourceClaimParameters) (*resourceapi.ResourceClaimParameters, error) {
+	logger := klog.FromContext(ctx).WithValues("resourceClaimParameters", klog.KObj(resourceClaimParameters))
+	client := g.clientset.ResourceV1alpha2().ResourceClaimParameters(resourceClaimParameters.Namespace)
+
+	obj, exists, err := g.resourceClaimParametersCache.GetByKey(resourceClaimParameters.Namespace + "/" + resourceClaimParameters.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting ResourceClaimParameters object from cache: %w", err)
+	}
+	if exists && !resourceClaimParametersDrifted(obj.(*resourceapi.ResourceClaimParameters), resourceClaimParameters) {
+		logger.V(4).Info("ResourceClaimParameters is up to date")
+		return obj.(*resourceapi.ResourceClaimParameters), nil
+	}
+
+	applied, err := client.Apply(ctx, resourceClaimParametersApplyConfiguration(resourceClaimParameters), g.applyOptions())
+	if err != nil {
+		return nil, fmt.Errorf("error applying ResourceClaimParameters object: %w", err)
+	}
+	if len(g.dryRun) > 0 {
+		logger.Info("Applied ResourceClaimParameters (dry run)")
+		return applied, nil
+	}
+	g.resourceClaimParametersCache.Mutation(applied)
+	logger.Info("Applied ResourceClaimParameters")
+	return applied, nil
+}
+
+// deleteResourceClaimParameters deletes all ResourceClaimParameters generated
+// from the named FakeClaimParameters except the one called keep.
+func (g *ClaimParametersGenerator) deleteResourceClaimParameters(ctx context.Context, namespace, fakeClaimParametersName, keep string) error {
+	logger := klog.FromContext(ctx)
+	client := g.clientset.ResourceV1alpha2().ResourceClaimParameters(namespace)
+
+	objs, err := g.resourceClaimParametersCache.ByIndex(generatedFromIndex, generatedFromIndexKey(namespace, fakecrd.GroupName, fakecrd.FakeClaimParametersKind, fakeClaimParametersName))
+	if err != nil {
+		return fmt.Errorf("error getting generated ResourceClaimParameters from cache: %w", err)
+	}
+
+	for _, obj := range objs {
+		item := obj.(*resourceapi.ResourceClaimParameters)
+		if item.Name == keep {
+			continue
+		}
+		err := client.Delete(ctx, item.Name, metav1.DeleteOptions{DryRun: g.dryRun})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error deleting ResourceClaimParameters object: %w", err)
+		}
+		logger.Info("Deleted ResourceClaimParameters", "resourceClaimParameters", klog.KObj(item), "dryRun", len(g.dryRun) > 0)
+	}
+
+	return nil
+}
+
+func isGeneratedFromFakeClaimParameters(obj any) bool {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	resourceClaimParameters, ok := obj.(*resourceapi.ResourceClaimParameters)
+	if !ok || resourceClaimParameters.GeneratedFrom == nil {
+		return false
+	}
+	return resourceClaimParameters.GeneratedFrom.APIGroup == fakecrd.GroupName &&
+		resourceClaimParameters.GeneratedFrom.Kind == fakecrd.FakeClaimParametersKind
+}
+
+func generatedFromIndexFunc(obj any) ([]string, error) {
+	resourceClaimParameters, ok := obj.(*resourceapi.ResourceClaimParameters)
+	if !ok || resourceClaimParameters.GeneratedFrom == nil {
+		return nil, nil
+	}
+	generatedFrom := resourceClaimParameters.GeneratedFrom
+	return []string{generatedFromIndexKey(resourceClaimParameters.Namespace, generatedFrom.APIGroup, generatedFrom.Kind, generatedFrom.Name)}, nil
+}
+
+func generatedFromIndexKey(namespace, apiGroup, kind, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", namespace, apiGroup, kind, name)
+}
+
+// generatedResourceClaimParametersName returns the deterministic name of the
+// ResourceClaimParameters generated from the named FakeClaimParameters.
+func generatedResourceClaimParametersName(fakeClaimParametersName string) string {
+	return generatedName(generatedResourceClaimParametersPrefix, fakeClaimParametersName)
+}
+
+// generatedName returns the deterministic name of an object generated from
+// the named object.
+func generatedName(prefix, name string) string {
+	generated := prefix + name
+	if len(generated) <= validation.DNS1123SubdomainMaxLength {
+		return generated
+	}
+	// Keep the name unique by replacing the overflowing part with a hash
+	sum := sha256.Sum256([]byte(name))
+	suffix := "-" + hex.EncodeToString(sum[:])[:10]
+	return generated[:validation.DNS1123SubdomainMaxLength-len(suffix)] + suffix
+}
+
+// resourceClaimParametersDrifted reports whether the fields owned by the
+// generator differ between the current and the desired object.
+func resourceClaimParametersDrifted(current, desired *resourceapi.ResourceClaimParameters) bool {
+	return !hasOwnerReferences(current, desired.OwnerReferences) ||
+		!apiequality.Semantic.DeepEqual(current.GeneratedFrom, desired.GeneratedFrom) ||
+		!apiequality.Semantic.DeepEqual(current.DriverRequests, desired.DriverRequests) ||
+		current.Shareable != desired.Shareable
+}
+
+// hasOwnerReferences reports whether all the owner references are set on the
+// object. Owner references added by others are ignored.
+func hasOwnerReferences(obj metav1.Object, ownerReferences []metav1.OwnerReference) bool {
+	for _, ownerReference := range ownerReferences {
+		found := false
+		for _, existing := range obj.GetOwnerReferences() {
+			if apiequality.Semantic.DeepEqual(existing, ownerReference) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// resourceClaimParametersApplyConfiguration returns the apply configuration
+// holding the fields owned by the generator.
+func resourceClaimParametersApplyConfiguration(resourceClaimParameters *resourceapi.ResourceClaimParameters) *resourceac.ResourceClaimParametersApplyConfiguration {
+	apply := resourceac.ResourceClaimParameters(resourceClaimParameters.Name, resourceClaimParameters.Namespace).
+		WithShareable(resourceClaimParameters.Shareable)
+
+	for _, ownerReference := range resourceClaimParameters.OwnerReferences {
+		owner := metav1ac.OwnerReference().
+			WithAPIVersion(ownerReference.APIVersion).
+			WithKind(ownerReference.Kind).
+			WithName(ownerReference.Name).
+			WithUID(ownerReference.UID)
+		if ownerReference.Controller != nil {
+			owner.WithController(*ownerReference.Controller)
+		}
+		if ownerReference.BlockOwnerDeletion != nil {
+			owner.WithBlockOwnerDeletion(*ownerReference.BlockOwnerDeletion)
+		}
+		apply.WithOwnerReferences(owner)
+	}
+
+	if generatedFrom := resourceClaimParameters.GeneratedFrom; generatedFrom != nil {
+		apply.WithGeneratedFrom(resourceac.ResourceClaimParametersReference().
+			WithAPIGroup(generatedFrom.APIGroup).
+			WithKind(generatedFrom.Kind).
+			WithName(generatedFrom.Name))
+	}
+
+	for _, driverRequests := range resourceClaimParameters.DriverRequests {
+		driverRequestsApply := resourceac.DriverRequests().
+			WithDriverName(driverRequests.DriverName).
+			WithVendorParameters(driverRequests.VendorParameters)
+		for _, request := range driverRequests.Requests {
+			requestApply := resourceac.ResourceRequest()
+			if request.VendorParameters.Raw != nil {
+				requestApply.WithVendorParameters(request.VendorParameters)
+			}
+			if request.NamedResources != nil {
+				requestApply.WithNamedResources(resourceac.NamedResourcesRequest().WithSelector(request.NamedResources.Selector))
+			}
+			driverRequestsApply.WithRequests(requestApply)
+		}
+		apply.WithDriverRequests(driverRequestsApply)
+	}
+
+	return apply
+}
+
+// newResourceClaimParametersFromFakeClaimParameters generates the
+// ResourceClaimParameters of a FakeClaimParameters object. The requests are
+// grouped by driver, the scheduler then allocates the devices of each group
+// from the ResourceSlices of its driver.
+func newResourceClaimParametersFromFakeClaimParameters(fakeClaimParameters *fakecrd.FakeClaimParameters, defaultDriverName string) (*resourceapi.ResourceClaimParameters, error) {
+	namespace := fakeClaimParameters.Namespace
+
+	spec := defaultedFakeClaimParametersSpec(&fakeClaimParameters.Spec)
+	rawSpec, err := vendorparameters.EncodeClaimParameters(spec)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling FakeClaimParamaters to JSON: %w", err)
+	}
+
+	shareable := true
+
+	// Admin access claims have no requests, the allocation only passes the
+	// vendor parameters on to the kubelet plugin of the default driver
+	driverNames := []string{defaultDriverName}
+	resourceRequests := map[string][]resourceapi.ResourceRequest{}
+	for _, request := range spec.GetRequests() {
+		driverName := request.DriverName
+		if driverName == "" {
+			driverName = defaultDriverName
+		}
+		if !slices.Contains(driverNames, driverName) {
+			driverNames = append(driverNames, driverName)
+		}
+
+		rawRequest, err := vendorparameters.EncodeRequestParameters(request.Name)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling request %s to JSON: %w", request.Name, err)
+		}
+
+		selector := request.Selector.ToNamedResourcesSelector()
+		// split を指定していても Fake リソースを動的に分割するためリソース要求に
+		// count で指定した個数だけ準備すれば良い
+		for i := 0; i < request.Count; i++ {
+			resourceRequests[driverName] = append(resourceRequests[driverName], resourceapi.ResourceRequest{
+				VendorParameters: runtime.RawExtension{Raw: rawRequest},
+				ResourceRequestModel: resourceapi.ResourceRequestModel{
+					NamedResources: &resourceapi.NamedResourcesRequest{
+						Selector: selector,
+					},
+				},
+			})
+		}
+	}
+
+	// Each driver is passed the whole spec, the kubelet plugins only prepare
+	// the devices allocated from their own driver
+	var driverRequests []resourceapi.DriverRequests
+	for _, driverName := range driverNames {
+		// The default driver is kept without requests only if no driver has
+		// any, otherwise the claim would also be prepared by its plugin
+		if len(resourceRequests[driverName]) == 0 && (driverName != defaultDriverName || len(resourceRequests) > 0) {
+			continue
+		}
+		driverRequests = append(driverRequests, resourceapi.DriverRequests{
+			DriverName:       driverName,
+			VendorParameters: runtime.RawExtension{Raw: rawSpec},
+			Requests:         resourceRequests[driverName],
+		})
+	}
+
+	resourceClaimParameters := &resourceapi.ResourceClaimParameters{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      generatedResourceClaimParametersName(fakeClaimParameters.Name),
+			Namespace: namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					// TypeMeta is not guaranteed to be populated after conversion
+					APIVersion:         fakecrd.SchemeGroupVersion.String(),
+					Kind:               fakecrd.FakeClaimParametersKind,
+					Name:               fakeClaimParameters.Name,
+					UID:                fakeClaimParameters.UID,
+					BlockOwnerDeletion: ptr.To(true),
+				},
+			},
+		},
+		GeneratedFrom: &resourceapi.ResourceClaimParametersReference{
+			APIGroup: fakecrd.GroupName,
+			Kind:     fakecrd.FakeClaimParametersKind,
+			Name:     fakeClaimParameters.Name,
+		},
+		DriverRequests: driverRequests,
+		Shareable:      shareable,
+	}
+
+	return resourceClaimParameters, nil
+}
+
+// defaultedFakeClaimParametersSpec returns a copy of spec with the API
+// defaults applied, in case the object was stored without the defaulting
+// webhook.
+func defaultedFakeClaimParametersSpec(spec *fakecrd.FakeClaimParametersSpec) *fakecrd.FakeClaimParametersSpec {
+	spec = spec.DeepCopy()
+	fakecrd.SetDefaultsFakeClaimParametersSpec(spec)
+	return spec
+}
@@ -0,0 +1,438 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	resourceac "k8s.io/client-go/applyconfigurations/resource/v1alpha2"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	resourcelisters "k8s.io/client-go/listers/resource/v1alpha2"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	shakeclientset "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned"
+	shakeinformers "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/informers/externalversions"
+	fakelisters "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/listers/fake/v1beta1"
+)
+
+const (
+	generatedResourceClassParametersPrefix = "resource-class-parameters-"
+
+	// ownerClassIndex is the name of the ResourceClassParameters index keyed
+	// on the ResourceClass owning them
+	ownerClassIndex = "ownerClass"
+)
+
+// ClassParametersGenerator reconciles the DeviceClassParameters referenced by
+// the ResourceClasses of the driver into ResourceClassParameters, whose
+// filters restrict the classes to the selected types of devices. The
+// ResourceClassParameters are generated per class in the namespace of its
+// parametersRef, which is where the scheduler looks them up.
+type ClassParametersGenerator struct {
+	clientset kubernetes.Interface
+	workers   int
+	dryRun    []string
+	// driverNames are the drivers whose ResourceClasses are reconciled
+	driverNames sets.Set[string]
+
+	resourceClassInformer           cache.SharedIndexInformer
+	resourceClassLister             resourcelisters.ResourceClassLister
+	deviceClassParametersInformer   cache.SharedIndexInformer
+	deviceClassParametersLister     fakelisters.DeviceClassParametersLister
+	resourceClassParametersInformer cache.SharedIndexInformer
+	informerFactory                 informers.SharedInformerFactory
+	shakeInformerFactory            shakeinformers.SharedInformerFactory
+
+	// queue holds the names of ResourceClasses
+	queue workqueue.RateLimitingInterface
+}
+
+func NewClassParametersGenerator(clientset kubernetes.Interface, shakeclientset shakeclientset.Interface, opts Options) (*ClassParametersGenerator, error) {
+	if err := opts.complete(); err != nil {
+		return nil, err
+	}
+
+	informerFactory := informers.NewSharedInformerFactory(clientset, 0)
+	shakeInformerFactory := shakeinformers.NewSharedInformerFactory(shakeclientset, 0)
+	resourceClasses := informerFactory.Resource().V1alpha2().ResourceClasses()
+	deviceClassParameters := shakeInformerFactory.Fake().V1beta1().DeviceClassParameters()
+
+	g := &ClassParametersGenerator{
+		clientset:                       clientset,
+		workers:                         opts.Workers,
+		dryRun:                          opts.dryRun(),
+		driverNames:                     sets.New(opts.DriverNames...),
+		resourceClassInformer:           resourceClasses.Informer(),
+		resourceClassLister:             resourceClasses.Lister(),
+		deviceClassParametersInformer:   deviceClassParameters.Informer(),
+		deviceClassParametersLister:     deviceClassParameters.Lister(),
+		resourceClassParametersInformer: informerFactory.Resource().V1alpha2().ResourceClassParameters().Informer(),
+		informerFactory:                 informerFactory,
+		shakeInformerFactory:            shakeInformerFactory,
+		queue: workqueue.NewRateLimitingQueueWithConfig(
+			workqueue.DefaultControllerRateLimiter(),
+			workqueue.RateLimitingQueueConfig{Name: "deviceclassparameters"},
+		),
+	}
+
+	err := g.resourceClassParametersInformer.AddIndexers(cache.Indexers{ownerClassIndex: ownerClassIndexFunc})
+	if err != nil {
+		return nil, fmt.Errorf("error adding ResourceClassParameters indexer: %w", err)
+	}
+
+	_, err = g.resourceClassInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: g.enqueue,
+		UpdateFunc: func(oldObj any, newObj any) {
+			g.enqueue(newObj)
+		},
+		DeleteFunc: g.enqueue,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error adding ResourceClass event handler: %w", err)
+	}
+
+	_, err = g.deviceClassParametersInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: g.enqueueReferencingClasses,
+		UpdateFunc: func(oldObj any, newObj any) {
+			g.enqueueReferencingClasses(newObj)
+		},
+		DeleteFunc: g.enqueueReferencingClasses,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error adding DeviceClassParameters event handler: %w", err)
+	}
+
+	// Manual edits and deletions of generated objects are corrected
+	_, err = g.resourceClassParametersInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: g.enqueueOwnerClass,
+		UpdateFunc: func(oldObj any, newObj any) {
+			g.enqueueOwnerClass(newObj)
+		},
+		DeleteFunc: g.enqueueOwnerClass,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error adding ResourceClassParameters event handler: %w", err)
+	}
+
+	return g, nil
+}
+
+// Run starts the informers and workers and blocks until the context is done.
+func (g *ClassParametersGenerator) Run(ctx context.Context) error {
+	logger := klog.FromContext(ctx)
+	defer utilruntime.HandleCrash()
+	defer g.queue.ShutDown()
+
+	g.informerFactory.Start(ctx.Done())
+	defer g.informerFactory.Shutdown()
+	g.shakeInformerFactory.Start(ctx.Done())
+	defer g.shakeInformerFactory.Shutdown()
+
+	logger.V(2).Info("Waiting for informer caches to sync")
+	if !cache.WaitForNamedCacheSync("deviceclassparameters", ctx.Done(),
+		g.resourceClassInformer.HasSynced,
+		g.deviceClassParametersInformer.HasSynced,
+		g.resourceClassParametersInformer.HasSynced,
+	) {
+		return fmt.Errorf("error syncing informer caches")
+	}
+
+	for i := 0; i < g.workers; i++ {
+		go wait.UntilWithContext(ctx, g.runWorker, time.Second)
+	}
+
+	<-ctx.Done()
+	logger.Info("Shutting down ResourceClassParameters generator")
+	return nil
+}
+
+func (g *ClassParametersGenerator) enqueue(obj any) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("error getting key for ResourceClass: %w", err))
+		return
+	}
+	g.queue.Add(key)
+}
+
+// enqueueReferencingClasses enqueues the ResourceClasses of the driver
+// referring to the DeviceClassParameters.
+func (g *ClassParametersGenerator) enqueueReferencingClasses(obj any) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	deviceClassParameters, ok := obj.(*fakecrd.DeviceClassParameters)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("unexpected object type %T", obj))
+		return
+	}
+
+	classes, err := g.resourceClassLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("error listing ResourceClasses from cache: %w", err))
+		return
+	}
+	for _, class := range classes {
+		if ref := g.deviceClassParametersRef(class); ref != nil && ref.Name == deviceClassParameters.Name {
+			g.queue.Add(class.Name)
+		}
+	}
+}
+
+func (g *ClassParametersGenerator) enqueueOwnerClass(obj any) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	keys, err := ownerClassIndexFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, key := range keys {
+		g.queue.Add(key)
+	}
+}
+
+func (g *ClassParametersGenerator) runWorker(ctx context.Context) {
+	for g.processNextWorkItem(ctx) {
+	}
+}
+
+func (g *ClassParametersGenerator) processNextWorkItem(ctx context.Context) bool {
+	key, quit := g.queue.Get()
+	if quit {
+		return false
+	}
+	defer g.queue.Done(key)
+
+	logger := klog.FromContext(ctx).WithValues("resourceClass", key)
+	ctx = klog.NewContext(ctx, logger)
+
+	if err := g.sync(ctx, key.(string)); err != nil {
+		logger.Error(err, "Error syncing ResourceClass, requeuing", "retries", g.queue.NumRequeues(key))
+		g.queue.AddRateLimited(key)
+		return true
+	}
+
+	g.queue.Forget(key)
+	return true
+}
+
+// sync puts the ResourceClassParameters generated for the named ResourceClass
+// into the desired state. Objects of deleted classes are garbage collected
+// through their owner reference.
+func (g *ClassParametersGenerator) sync(ctx context.Context, name string) error {
+	logger := klog.FromContext(ctx)
+
+	class, err := g.resourceClassLister.Get(name)
+	if apierrors.IsNotFound(err) {
+		logger.V(4).Info("ResourceClass no longer exists")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error getting ResourceClass from cache: %w", err)
+	}
+
+	ref := g.deviceClassParametersRef(class)
+	if ref == nil {
+		return g.deleteResourceClassParameters(ctx, class.Name, nil)
+	}
+	if ref.Namespace == "" {
+		logger.Info("ResourceClass refers to DeviceClassParameters without a namespace to generate ResourceClassParameters in")
+		return g.deleteResourceClassParameters(ctx, class.Name, nil)
+	}
+
+	deviceClassParameters, err := g.deviceClassParametersLister.Get(ref.Name)
+	if apierrors.IsNotFound(err) {
+		// Without parameters the scheduler does not allocate devices of
+		// the class, which is safer than allocating any type of device
+		logger.Info("DeviceClassParameters referenced by ResourceClass does not exist", "deviceClassParameters", ref.Name)
+		return g.deleteResourceClassParameters(ctx, class.Name, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("error getting DeviceClassParameters from cache: %w", err)
+	}
+
+	resourceClassParameters := newResourceClassParametersFromDeviceClassParameters(class, ref.Namespace, deviceClassParameters, sets.List(g.driverNames))
+	if err := g.applyResourceClassParameters(ctx, resourceClassParameters); err != nil {
+		return err
+	}
+	return g.deleteResourceClassParameters(ctx, class.Name, resourceClassParameters)
+}
+
+// applyResourceClassParameters applies the fields owned by the generator with
+// server-side apply when the cached object has drifted from them.
+func (g *ClassParametersGenerator) applyResourceClassParameters(ctx context.Context, resourceClassParameters *resourceapi.ResourceClassParameters) error {
+	logger := klog.FromContext(ctx).WithValues("resourceClassParameters", klog.KObj(resourceClassParameters))
+
+	obj, exists, err := g.resourceClassParametersInformer.GetStore().GetByKey(resourceClassParameters.Namespace + "/" + resourceClassParameters.Name)
+	if err != nil {
+		return fmt.Errorf("error getting ResourceClassParameters object from cache: %w", err)
+	}
+	if exists {
+		current := obj.(*resourceapi.ResourceClassParameters)
+		if hasOwnerReferences(current, resourceClassParameters.OwnerReferences) &&
+			apiequality.Semantic.DeepEqual(current.GeneratedFrom, resourceClassParameters.GeneratedFrom) &&
+			apiequality.Semantic.DeepEqual(current.Filters, resourceClassParameters.Filters) {
+			logger.V(4).Info("ResourceClassParameters is up to date")
+			return nil
+		}
+	}
+
+	_, err = g.clientset.ResourceV1alpha2().ResourceClassParameters(resourceClassParameters.Namespace).Apply(ctx,
+		resourceClassParametersApplyConfiguration(resourceClassParameters),
+		metav1.ApplyOptions{FieldManager: fieldManager, Force: true, DryRun: g.dryRun})
+	if err != nil {
+		return fmt.Errorf("error applying ResourceClassParameters object: %w", err)
+	}
+	logger.Info("Applied ResourceClassParameters", "dryRun", len(g.dryRun) > 0)
+	return nil
+}
+
+// deleteResourceClassParameters deletes the ResourceClassParameters generated
+// for the named ResourceClass except keep, which may be nil.
+func (g *ClassParametersGenerator) deleteResourceClassParameters(ctx context.Context, className string, keep *resourceapi.ResourceClassParameters) error {
+	logger := klog.FromContext(ctx)
+
+	objs, err := g.resourceClassParametersInformer.GetIndexer().ByIndex(ownerClassIndex, className)
+	if err != nil {
+		return fmt.Errorf("error getting generated ResourceClassParameters from cache: %w", err)
+	}
+	for _, obj := range objs {
+		item := obj.(*resourceapi.ResourceClassParameters)
+		if keep != nil && item.Namespace == keep.Namespace && item.Name == keep.Name {
+			continue
+		}
+		err := g.clientset.ResourceV1alpha2().ResourceClassParameters(item.Namespace).Delete(ctx, item.Name, metav1.DeleteOptions{DryRun: g.dryRun})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error deleting ResourceClassParameters object: %w", err)
+		}
+		logger.Info("Deleted ResourceClassParameters", "resourceClassParameters", klog.KObj(item), "dryRun", len(g.dryRun) > 0)
+	}
+	return nil
+}
+
+// deviceClassParametersRef returns the reference of a ResourceClass of one of
+// the drivers to DeviceClassParameters, or nil if it does not refer to any.
+func (g *ClassParametersGenerator) deviceClassParametersRef(class *resourceapi.ResourceClass) *resourceapi.ResourceClassParametersReference {
+	ref := class.ParametersRef
+	if !g.driverNames.Has(class.DriverName) || ref == nil ||
+		ref.APIGroup != fakecrd.GroupName || ref.Kind != fakecrd.DeviceClassParametersKind {
+		return nil
+	}
+	return ref
+}
+
+// newResourceClassParametersFromDeviceClassParameters generates the
+// ResourceClassParameters of a class. The devices are filtered the same way
+// for every driver, claims of the class may span several of them.
+func newResourceClassParametersFromDeviceClassParameters(class *resourceapi.ResourceClass, namespace string, deviceClassParameters *fakecrd.DeviceClassParameters, driverNames []string) *resourceapi.ResourceClassParameters {
+	spec := deviceClassParameters.Spec.DeepCopy()
+	fakecrd.SetDefaultsDeviceClassParametersSpec(spec)
+	selector := spec.ToNamedResourcesSelector()
+
+	// The driver of the class comes first, followed by the others
+	var filters []resourceapi.ResourceFilter
+	for _, driverName := range append([]string{class.DriverName}, driverNames...) {
+		if len(filters) > 0 && driverName == class.DriverName {
+			continue
+		}
+		filters = append(filters, resourceapi.ResourceFilter{
+			DriverName: driverName,
+			ResourceFilterModel: resourceapi.ResourceFilterModel{
+				NamedResources: &resourceapi.NamedResourcesFilter{
+					Selector: selector,
+				},
+			},
+		})
+	}
+
+	return &resourceapi.ResourceClassParameters{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      generatedName(generatedResourceClassParametersPrefix, class.Name),
+			Namespace: namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion:         resourceapi.SchemeGroupVersion.String(),
+					Kind:               "ResourceClass",
+					Name:               class.Name,
+					UID:                class.UID,
+					BlockOwnerDeletion: ptr.To(true),
+				},
+			},
+		},
+		GeneratedFrom: &resourceapi.ResourceClassParametersReference{
+			APIGroup: fakecrd.GroupName,
+			Kind:     fakecrd.DeviceClassParametersKind,
+			Name:     deviceClassParameters.Name,
+		},
+		Filters: filters,
+	}
+}
+
+// resourceClassParametersApplyConfiguration returns the apply configuration
+// holding the fields owned by the generator.
+func resourceClassParametersApplyConfiguration(resourceClassParameters *resourceapi.ResourceClassParameters) *resourceac.ResourceClassParametersApplyConfiguration {
+	apply := resourceac.ResourceClassParameters(resourceClassParameters.Name, resourceClassParameters.Namespace)
+
+	for _, ownerReference := range resourceClassParameters.OwnerReferences {
+		owner := metav1ac.OwnerReference().
+			WithAPIVersion(ownerReference.APIVersion).
+			WithKind(ownerReference.Kind).
+			WithName(ownerReference.Name).
+			WithUID(ownerReference.UID)
+		if ownerReference.BlockOwnerDeletion != nil {
+			owner.WithBlockOwnerDeletion(*ownerReference.BlockOwnerDeletion)
+		}
+		apply.WithOwnerReferences(owner)
+	}
+
+	if generatedFrom := resourceClassParameters.GeneratedFrom; generatedFrom != nil {
+		apply.WithGeneratedFrom(resourceac.ResourceClassParametersReference().
+			WithAPIGroup(generatedFrom.APIGroup).
+			WithKind(generatedFrom.Kind).
+			WithName(generatedFrom.Name))
+	}
+
+	for _, filter := range resourceClassParameters.Filters {
+		filterApply := resourceac.ResourceFilter().WithDriverName(filter.DriverName)
+		if filter.NamedResources != nil {
+			filterApply.WithNamedResources(resourceac.NamedResourcesFilter().WithSelector(filter.NamedResources.Selector))
+		}
+		apply.WithFilters(filterApply)
+	}
+
+	return apply
+}
+
+// ownerClassIndexFunc indexes the ResourceClassParameters generated from
+// DeviceClassParameters on the name of the ResourceClass owning them.
+func ownerClassIndexFunc(obj any) ([]string, error) {
+	resourceClassParameters, ok := obj.(*resourceapi.ResourceClassParameters)
+	if !ok || resourceClassParameters.GeneratedFrom == nil ||
+		resourceClassParameters.GeneratedFrom.APIGroup != fakecrd.GroupName ||
+		resourceClassParameters.GeneratedFrom.Kind != fakecrd.DeviceClassParametersKind {
+		return nil, nil
+	}
+	var classes []string
+	for _, ownerReference := range resourceClassParameters.OwnerReferences {
+		if ownerReference.APIVersion == resourceapi.SchemeGroupVersion.String() && ownerReference.Kind == "ResourceClass" {
+			classes = append(classes, ownerReference.Name)
+		}
+	}
+	return classes, nil
+}
@@ -0,0 +1,142 @@
+// Package controller implements the controllers of the fake DRA driver. They
+// generate the ResourceClaimParameters and ResourceClassParameters the
+// scheduler allocates devices with from FakeClaimParameters and
+// DeviceClassParameters, and account the allocated devices in the status of
+// FakeDeviceQuotas. Run runs all of them in process, so that test suites can
+// run the driver next to envtest without deploying fake-dra-controller.
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	shakeclientset "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned"
+)
+
+const (
+	// DefaultDriverName is the driver reconciled unless DriverNames is set
+	DefaultDriverName = fakecrd.GroupName
+	// DefaultWorkers is the number of workers of each controller unless
+	// Workers is set
+	DefaultWorkers = 10
+)
+
+// Options configures the controllers. Every field is optional, unset fields
+// are defaulted the way the flags of fake-dra-controller are.
+type Options struct {
+	// DriverNames are the drivers whose ResourceClasses, FakeClaimParameters
+	// and FakeDeviceQuotas are reconciled. Requests of FakeClaimParameters
+	// which do not name a driver allocate from the first one. Defaults to
+	// DefaultDriverName.
+	DriverNames []string
+	// AdminAccessNamespaces are the namespaces FakeClaimParameters may
+	// request admin access in
+	AdminAccessNamespaces []string
+	// Workers is the number of objects each controller reconciles
+	// concurrently, DefaultWorkers if zero
+	Workers int
+	// DryRun has the API server validate the writes of the controllers
+	// without persisting them
+	DryRun bool
+
+	// Recorder records the Events of FakeClaimParameters. The Events are
+	// dropped if nil.
+	Recorder record.EventRecorder
+	// Registerer registers the metrics of the controllers if set. They are
+	// unregistered when Run returns.
+	Registerer prometheus.Registerer
+}
+
+// complete validates the driver names and defaults the unset options
+func (o *Options) complete() error {
+	if len(o.DriverNames) == 0 {
+		o.DriverNames = []string{DefaultDriverName}
+	}
+	for _, driverName := range o.DriverNames {
+		if msgs := validation.IsDNS1123Subdomain(driverName); len(msgs) > 0 {
+			return fmt.Errorf("invalid driver name %q: %s", driverName, strings.Join(msgs, ", "))
+		}
+	}
+	if o.Workers <= 0 {
+		o.Workers = DefaultWorkers
+	}
+	if o.Recorder == nil {
+		o.Recorder = &record.FakeRecorder{}
+	}
+	return nil
+}
+
+// dryRun returns the DryRun of the API requests of the controllers
+func (o *Options) dryRun() []string {
+	if o.DryRun {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
+}
+
+// Run runs the ResourceClaimParameters generator, the ResourceClassParameters
+// generator and the FakeDeviceQuota controller until the context is done.
+// The first error stops all of them.
+func Run(ctx context.Context, clientset kubernetes.Interface, shakeclientset shakeclientset.Interface, opts Options) error {
+	logger := klog.FromContext(ctx)
+	if err := opts.complete(); err != nil {
+		return err
+	}
+
+	claimParametersGenerator, err := NewClaimParametersGenerator(clientset, shakeclientset, opts)
+	if err != nil {
+		return fmt.Errorf("error creating claim parameters generator: %w", err)
+	}
+	classParametersGenerator, err := NewClassParametersGenerator(clientset, shakeclientset, opts)
+	if err != nil {
+		return fmt.Errorf("error creating class parameters generator: %w", err)
+	}
+	quotaController, err := NewQuotaController(clientset, shakeclientset, opts)
+	if err != nil {
+		return fmt.Errorf("error creating FakeDeviceQuota controller: %w", err)
+	}
+
+	if opts.Registerer != nil {
+		unregisterMetrics, err := registerMetrics(opts.Registerer, claimParametersGenerator)
+		if err != nil {
+			return err
+		}
+		defer unregisterMetrics()
+	}
+
+	logger.Info("Starting controllers", "workers", opts.Workers, "driverNames", opts.DriverNames, "adminAccessNamespaces", opts.AdminAccessNamespaces, "dryRun", opts.DryRun)
+	return runControllers(ctx, claimParametersGenerator.Run, classParametersGenerator.Run, quotaController.Run)
+}
+
+// runControllers runs the controllers until the context is done. The first
+// error stops all of them.
+func runControllers(ctx context.Context, controllers ...func(context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errCh := make(chan error, len(controllers))
+	for _, run := range controllers {
+		go func() {
+			errCh <- run(ctx)
+		}()
+	}
+
+	var err error
+	for range controllers {
+		if controllerErr := <-errCh; controllerErr != nil && err == nil {
+			err = controllerErr
+			cancel()
+		}
+	}
+	return err
+}
@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+)
+
+const (
+	metricsNamespace = "fake_dra_controller"
+
+	// parametersRefIndex is the name of the ResourceClaim index keyed on the
+	// claim parameters they refer to
+	parametersRefIndex = "parametersRef"
+)
+
+// Result labels of the reconcile metrics
+const (
+	reconcileResultSuccess = "success"
+	reconcileResultError   = "error"
+)
+
+// Status labels of the FakeClaimParameters metric
+const (
+	fakeClaimParametersStatusGenerated = "generated"
+	fakeClaimParametersStatusPending   = "pending"
+	fakeClaimParametersStatusFailed    = "failed"
+	fakeClaimParametersStatusInvalid   = "invalid"
+)
+
+var (
+	fakeClaimParametersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "fakeclaimparameters"),
+		"Number of FakeClaimParameters objects, by generation status.",
+		[]string{"status"}, nil,
+	)
+	generatedResourceClaimParametersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "generated_resourceclaimparameters"),
+		"Number of ResourceClaimParameters objects generated from FakeClaimParameters.",
+		nil, nil,
+	)
+	requestsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "requests"),
+		"Number of devices requested by accepted FakeClaimParameters, by selected models. Models is empty if any model may be allocated.",
+		[]string{"models"}, nil,
+	)
+	fakeClaimParametersClaimsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "fakeclaimparameters_claims"),
+		"Number of ResourceClaims referencing each FakeClaimParameters object.",
+		[]string{"namespace", "name"}, nil,
+	)
+)
+
+func newReconcileDuration() *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "reconcile_duration_seconds",
+			Help:      "Time taken to reconcile a FakeClaimParameters object into ResourceClaimParameters, by result.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 15),
+		},
+		[]string{"result"},
+	)
+}
+
+func newReconcileTotal() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "reconcile_total",
+			Help:      "Number of FakeClaimParameters reconciles, by result.",
+		},
+		[]string{"result"},
+	)
+}
+
+// registerMetrics registers the metrics updated by the workers of the
+// generator and those computed from its informer caches. The returned
+// function unregisters them, so that the registerer can be reused once the
+// generator stopped.
+func registerMetrics(registerer prometheus.Registerer, generator *ClaimParametersGenerator) (func(), error) {
+	var registered []prometheus.Collector
+	unregister := func() {
+		for _, collector := range registered {
+			registerer.Unregister(collector)
+		}
+	}
+	for _, collector := range []prometheus.Collector{generator.reconcileDuration, generator.reconcileTotal, &generatorCollector{generator: generator}} {
+		if err := registerer.Register(collector); err != nil {
+			unregister()
+			return nil, fmt.Errorf("error registering metrics: %w", err)
+		}
+		registered = append(registered, collector)
+	}
+	return unregister, nil
+}
+
+// generatorCollector computes the object metrics from the informer caches of
+// the generator when scraped.
+type generatorCollector struct {
+	generator *ClaimParametersGenerator
+}
+
+var _ prometheus.Collector = &generatorCollector{}
+
+func (c *generatorCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- fakeClaimParametersDesc
+	ch <- generatedResourceClaimParametersDesc
+	ch <- requestsDesc
+	ch <- fakeClaimParametersClaimsDesc
+}
+
+func (c *generatorCollector) Collect(ch chan<- prometheus.Metric) {
+	g := c.generator
+
+	fakeClaimParametersList, err := g.fakeClaimParametersLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("error listing FakeClaimParameters for metrics: %w", err))
+		return
+	}
+
+	statuses := map[string]int{
+		fakeClaimParametersStatusGenerated: 0,
+		fakeClaimParametersStatusPending:   0,
+		fakeClaimParametersStatusFailed:    0,
+		fakeClaimParametersStatusInvalid:   0,
+	}
+	requests := map[string]int{}
+	for _, fakeClaimParameters := range fakeClaimParametersList {
+		status := fakeClaimParametersStatus(&fakeClaimParameters.Status)
+		statuses[status]++
+
+		if status != fakeClaimParametersStatusInvalid {
+			spec := defaultedFakeClaimParametersSpec(&fakeClaimParameters.Spec)
+			for _, request := range spec.GetRequests() {
+				requests[strings.Join(request.Selector.Models, ",")] += request.Count
+			}
+		}
+
+		claims, err := g.resourceClaimInformer.GetIndexer().ByIndex(parametersRefIndex,
+			parametersRefIndexKey(fakeClaimParameters.Namespace, fakecrd.GroupName, fakecrd.FakeClaimParametersKind, fakeClaimParameters.Name))
+		if err != nil {
+			utilruntime.HandleError(fmt.Errorf("error getting ResourceClaims for metrics: %w", err))
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(fakeClaimParametersClaimsDesc, prometheus.GaugeValue, float64(len(claims)),
+			fakeClaimParameters.Namespace, fakeClaimParameters.Name)
+	}
+
+	for status, count := range statuses {
+		ch <- prometheus.MustNewConstMetric(fakeClaimParametersDesc, prometheus.GaugeValue, float64(count), status)
+	}
+	for models, count := range requests {
+		ch <- prometheus.MustNewConstMetric(requestsDesc, prometheus.GaugeValue, float64(count), models)
+	}
+
+	generated := 0
+	for _, obj := range g.resourceClaimParametersInformer.GetStore().List() {
+		if isGeneratedFromFakeClaimParameters(obj) {
+			generated++
+		}
+	}
+	ch <- prometheus.MustNewConstMetric(generatedResourceClaimParametersDesc, prometheus.GaugeValue, float64(generated))
+}
+
+// fakeClaimParametersStatus summarizes the conditions into a metric label
+func fakeClaimParametersStatus(status *fakecrd.FakeClaimParametersStatus) string {
+	if meta.IsStatusConditionTrue(status.Conditions, fakecrd.FakeClaimParametersInvalid) {
+		return fakeClaimParametersStatusInvalid
+	}
+	generated := meta.FindStatusCondition(status.Conditions, fakecrd.FakeClaimParametersGenerated)
+	switch {
+	case generated == nil:
+		return fakeClaimParametersStatusPending
+	case generated.Status == metav1.ConditionTrue:
+		return fakeClaimParametersStatusGenerated
+	case generated.Reason == fakecrd.FakeClaimParametersReasonGenerationFailed:
+		return fakeClaimParametersStatusFailed
+	default:
+		return fakeClaimParametersStatusPending
+	}
+}
+
+func parametersRefIndexFunc(obj any) ([]string, error) {
+	claim, ok := obj.(*resourceapi.ResourceClaim)
+	if !ok || claim.Spec.ParametersRef == nil {
+		return nil, nil
+	}
+	ref := claim.Spec.ParametersRef
+	return []string{parametersRefIndexKey(claim.Namespace, ref.APIGroup, ref.Kind, ref.Name)}, nil
+}
+
+func parametersRefIndexKey(namespace, apiGroup, kind, name string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", namespace, apiGroup, kind, name)
+}
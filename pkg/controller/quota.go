@@ -0,0 +1,321 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	fakeac "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/applyconfiguration/fake/v1beta1"
+	shakeclientset "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned"
+	shakeinformers "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/informers/externalversions"
+	fakelisters "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/listers/fake/v1beta1"
+	"github.com/toVersus/fake-dra-driver/pkg/vendorparameters"
+)
+
+// resourceSliceNodeIndex is the name of the index of the ResourceSlices of
+// the drivers keyed on their node
+const resourceSliceNodeIndex = "node"
+
+// QuotaController keeps the usage in the status of FakeDeviceQuotas up to date
+// with the claims allocated in their namespace. Limits are enforced by the
+// admission webhook, which relies on this usage.
+type QuotaController struct {
+	shakeclientset shakeclientset.Interface
+	workers        int
+	dryRun         []string
+	// driverNames are the drivers whose allocations are accounted for
+	driverNames sets.Set[string]
+
+	fakeDeviceQuotaInformer cache.SharedIndexInformer
+	fakeDeviceQuotaLister   fakelisters.FakeDeviceQuotaLister
+	resourceClaimInformer   cache.SharedIndexInformer
+	// resourceSliceInformer resolves the model of allocated devices
+	resourceSliceInformer cache.SharedIndexInformer
+	informerFactory       informers.SharedInformerFactory
+	shakeInformerFactory  shakeinformers.SharedInformerFactory
+
+	// queue holds namespaces
+	queue workqueue.RateLimitingInterface
+}
+
+func NewQuotaController(clientset kubernetes.Interface, shakeclientset shakeclientset.Interface, opts Options) (*QuotaController, error) {
+	if err := opts.complete(); err != nil {
+		return nil, err
+	}
+
+	informerFactory := informers.NewSharedInformerFactory(clientset, 0)
+	shakeInformerFactory := shakeinformers.NewSharedInformerFactory(shakeclientset, 0)
+	fakeDeviceQuotas := shakeInformerFactory.Fake().V1beta1().FakeDeviceQuotas()
+
+	c := &QuotaController{
+		shakeclientset:          shakeclientset,
+		workers:                 opts.Workers,
+		dryRun:                  opts.dryRun(),
+		driverNames:             sets.New(opts.DriverNames...),
+		fakeDeviceQuotaInformer: fakeDeviceQuotas.Informer(),
+		fakeDeviceQuotaLister:   fakeDeviceQuotas.Lister(),
+		resourceClaimInformer:   informerFactory.Resource().V1alpha2().ResourceClaims().Informer(),
+		resourceSliceInformer:   informerFactory.Resource().V1alpha2().ResourceSlices().Informer(),
+		informerFactory:         informerFactory,
+		shakeInformerFactory:    shakeInformerFactory,
+		queue: workqueue.NewRateLimitingQueueWithConfig(
+			workqueue.DefaultControllerRateLimiter(),
+			workqueue.RateLimitingQueueConfig{Name: "fakedevicequota"},
+		),
+	}
+
+	err := c.resourceSliceInformer.AddIndexers(cache.Indexers{resourceSliceNodeIndex: c.resourceSliceNodeIndexFunc})
+	if err != nil {
+		return nil, fmt.Errorf("error adding ResourceSlice indexer: %w", err)
+	}
+
+	_, err = c.fakeDeviceQuotaInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueueNamespace,
+		UpdateFunc: func(oldObj any, newObj any) {
+			c.enqueueNamespace(newObj)
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error adding FakeDeviceQuota event handler: %w", err)
+	}
+
+	// Only allocations and deallocations change the usage
+	_, err = c.resourceClaimInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueueNamespace,
+		UpdateFunc: func(oldObj any, newObj any) {
+			oldClaim, ok := oldObj.(*resourceapi.ResourceClaim)
+			newClaim, ok2 := newObj.(*resourceapi.ResourceClaim)
+			if ok && ok2 && apiequality.Semantic.DeepEqual(oldClaim.Status.Allocation, newClaim.Status.Allocation) {
+				return
+			}
+			c.enqueueNamespace(newObj)
+		},
+		DeleteFunc: c.enqueueNamespace,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error adding ResourceClaim event handler: %w", err)
+	}
+
+	return c, nil
+}
+
+// Run starts the informers and workers and blocks until the context is done.
+func (c *QuotaController) Run(ctx context.Context) error {
+	logger := klog.FromContext(ctx)
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	c.informerFactory.Start(ctx.Done())
+	defer c.informerFactory.Shutdown()
+	c.shakeInformerFactory.Start(ctx.Done())
+	defer c.shakeInformerFactory.Shutdown()
+
+	logger.V(2).Info("Waiting for informer caches to sync")
+	if !cache.WaitForNamedCacheSync("fakedevicequota", ctx.Done(),
+		c.fakeDeviceQuotaInformer.HasSynced,
+		c.resourceClaimInformer.HasSynced,
+		c.resourceSliceInformer.HasSynced,
+	) {
+		return fmt.Errorf("error syncing informer caches")
+	}
+
+	for i := 0; i < c.workers; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	<-ctx.Done()
+	logger.Info("Shutting down FakeDeviceQuota controller")
+	return nil
+}
+
+func (c *QuotaController) enqueueNamespace(obj any) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	object, ok := obj.(metav1.Object)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("unexpected object type %T", obj))
+		return
+	}
+	c.queue.Add(object.GetNamespace())
+}
+
+func (c *QuotaController) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *QuotaController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	logger := klog.FromContext(ctx).WithValues("namespace", key)
+	ctx = klog.NewContext(ctx, logger)
+
+	if err := c.sync(ctx, key.(string)); err != nil {
+		logger.Error(err, "Error syncing FakeDeviceQuotas, requeuing", "retries", c.queue.NumRequeues(key))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+	return true
+}
+
+// sync recomputes the usage of the namespace and records it in the status of
+// all its FakeDeviceQuotas.
+func (c *QuotaController) sync(ctx context.Context, namespace string) error {
+	logger := klog.FromContext(ctx)
+
+	quotas, err := c.fakeDeviceQuotaLister.FakeDeviceQuotas(namespace).List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("error listing FakeDeviceQuotas from cache: %w", err)
+	}
+	if len(quotas) == 0 {
+		return nil
+	}
+
+	used, claims, err := c.namespaceUsage(namespace)
+	if err != nil {
+		return err
+	}
+	logger.V(4).Info("Computed fake device usage", "devices", used.Devices, "partitions", used.Partitions, "claims", claims)
+
+	for _, quota := range quotas {
+		status := fakecrd.FakeDeviceQuotaStatus{
+			ObservedGeneration: quota.Generation,
+			Used:               *used,
+			Claims:             claims,
+		}
+		if apiequality.Semantic.DeepEqual(&quota.Status, &status) {
+			continue
+		}
+
+		usedApply := fakeac.FakeDeviceUsage().
+			WithDevices(used.Devices).
+			WithPartitions(used.Partitions)
+		if len(used.Models) > 0 {
+			usedApply.WithModels(used.Models)
+		}
+		apply := fakeac.FakeDeviceQuota(quota.Name, quota.Namespace).
+			WithStatus(fakeac.FakeDeviceQuotaStatus().
+				WithObservedGeneration(status.ObservedGeneration).
+				WithUsed(usedApply).
+				WithClaims(status.Claims))
+		_, err := c.shakeclientset.FakeV1beta1().FakeDeviceQuotas(quota.Namespace).ApplyStatus(ctx, apply,
+			metav1.ApplyOptions{FieldManager: fieldManager, Force: true, DryRun: c.dryRun})
+		if err != nil {
+			return fmt.Errorf("error updating FakeDeviceQuota %s status: %w", quota.Name, err)
+		}
+		logger.V(4).Info("Applied FakeDeviceQuota status", "fakeDeviceQuota", klog.KObj(quota))
+	}
+	return nil
+}
+
+// namespaceUsage sums up the devices allocated to the claims of a namespace
+// and returns it together with the number of allocated claims.
+func (c *QuotaController) namespaceUsage(namespace string) (*fakecrd.FakeDeviceUsage, int, error) {
+	objs, err := c.resourceClaimInformer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing ResourceClaims from cache: %w", err)
+	}
+
+	used := &fakecrd.FakeDeviceUsage{}
+	claims := 0
+	for _, obj := range objs {
+		claim, ok := obj.(*resourceapi.ResourceClaim)
+		if !ok || claim.Status.Allocation == nil {
+			continue
+		}
+		allocated := false
+		for _, handle := range claim.Status.Allocation.ResourceHandles {
+			if !c.driverNames.Has(handle.DriverName) || handle.StructuredData == nil {
+				continue
+			}
+			usage, err := c.handleUsage(handle.DriverName, handle.StructuredData)
+			if err != nil {
+				// A single undecodable claim must not stall the accounting
+				// of the whole namespace
+				utilruntime.HandleError(fmt.Errorf("error computing usage of ResourceClaim %s: %w", klog.KObj(claim), err))
+				continue
+			}
+			used.Add(usage)
+			allocated = true
+		}
+		if allocated {
+			claims++
+		}
+	}
+	return used, claims, nil
+}
+
+func (c *QuotaController) handleUsage(driverName string, handle *resourceapi.StructuredResourceHandle) (*fakecrd.FakeDeviceUsage, error) {
+	_, devices, err := vendorparameters.DecodeAllocation(handle)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &fakecrd.FakeDeviceUsage{}
+	for _, device := range devices {
+		usage.Devices++
+		usage.Partitions += max(device.Request.Split, 1)
+		if model := c.deviceModel(driverName, handle.NodeName, device.Name); model != "" {
+			if usage.Models == nil {
+				usage.Models = map[string]int{}
+			}
+			usage.Models[model]++
+		}
+	}
+	return usage, nil
+}
+
+// deviceModel looks up the model of a device in the ResourceSlices of its
+// driver and node, it is empty if the device is no longer published.
+func (c *QuotaController) deviceModel(driverName, nodeName, name string) string {
+	objs, err := c.resourceSliceInformer.GetIndexer().ByIndex(resourceSliceNodeIndex, nodeName)
+	if err != nil {
+		return ""
+	}
+	for _, obj := range objs {
+		slice := obj.(*resourceapi.ResourceSlice)
+		if slice.DriverName != driverName || slice.NamedResources == nil {
+			continue
+		}
+		for _, instance := range slice.NamedResources.Instances {
+			if instance.Name != name {
+				continue
+			}
+			for _, attribute := range instance.Attributes {
+				if attribute.Name == "model" && attribute.StringValue != nil {
+					return *attribute.StringValue
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func (c *QuotaController) resourceSliceNodeIndexFunc(obj any) ([]string, error) {
+	slice, ok := obj.(*resourceapi.ResourceSlice)
+	if !ok || !c.driverNames.Has(slice.DriverName) {
+		return nil, nil
+	}
+	return []string{slice.NodeName}, nil
+}
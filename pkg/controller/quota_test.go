@@ -0,0 +1,212 @@
+package controller
+
+import (
+	"encoding/json"
+	"testing"
+
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/klog/v2/ktesting"
+	"k8s.io/utils/ptr"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	shakefake "github.com/toVersus/fake-dra-driver/pkg/3-shake.com/resource/clientset/versioned/fake"
+	"github.com/toVersus/fake-dra-driver/pkg/vendorparameters"
+)
+
+const (
+	testNamespace = "test"
+	testNodeName  = "test-node"
+)
+
+// testDevice is a device allocated for a request of a claim
+type testDevice struct {
+	request string
+	name    string
+}
+
+func TestQuotaControllerSync(t *testing.T) {
+	multiRequest := &fakecrd.FakeClaimParametersSpec{
+		Requests: []fakecrd.FakeRequest{
+			{Name: "whole", Count: 1},
+			{Name: "split", Count: 1, Split: 2},
+		},
+	}
+
+	testCases := map[string]struct {
+		claims []*resourceapi.ResourceClaim
+		// models are the models of the devices published on the node
+		models map[string]string
+
+		wantStatus *fakecrd.FakeDeviceQuotaStatus
+	}{
+		"no claims": {
+			wantStatus: &fakecrd.FakeDeviceQuotaStatus{ObservedGeneration: 1},
+		},
+		"allocated claims": {
+			claims: []*resourceapi.ResourceClaim{
+				allocatedClaim(t, "single", DefaultDriverName, &fakecrd.FakeClaimParametersSpec{}, testDevice{fakecrd.DefaultRequestName, "fake-0"}),
+				allocatedClaim(t, "multi", DefaultDriverName, multiRequest, testDevice{"whole", "fake-1"}, testDevice{"split", "fake-2"}),
+			},
+			models: map[string]string{
+				"fake-0": fakecrd.FakeModelUltra10,
+				"fake-1": fakecrd.FakeModelUltra10,
+				"fake-2": fakecrd.FakeModelUltra100,
+			},
+			wantStatus: &fakecrd.FakeDeviceQuotaStatus{
+				ObservedGeneration: 1,
+				Used: fakecrd.FakeDeviceUsage{
+					Devices:    3,
+					Partitions: 4,
+					Models:     map[string]int{fakecrd.FakeModelUltra10: 2, fakecrd.FakeModelUltra100: 1},
+				},
+				Claims: 2,
+			},
+		},
+		"pending claims and claims of other drivers": {
+			claims: []*resourceapi.ResourceClaim{
+				{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "pending"}},
+				allocatedClaim(t, "other", "other.example.com", &fakecrd.FakeClaimParametersSpec{}, testDevice{fakecrd.DefaultRequestName, "other-0"}),
+			},
+			wantStatus: &fakecrd.FakeDeviceQuotaStatus{ObservedGeneration: 1},
+		},
+		"devices no longer published": {
+			claims: []*resourceapi.ResourceClaim{
+				allocatedClaim(t, "single", DefaultDriverName, &fakecrd.FakeClaimParametersSpec{}, testDevice{fakecrd.DefaultRequestName, "fake-0"}),
+			},
+			wantStatus: &fakecrd.FakeDeviceQuotaStatus{
+				ObservedGeneration: 1,
+				Used:               fakecrd.FakeDeviceUsage{Devices: 1, Partitions: 1},
+				Claims:             1,
+			},
+		},
+		"undecodable claim skipped": {
+			claims: []*resourceapi.ResourceClaim{
+				allocatedClaim(t, "single", DefaultDriverName, &fakecrd.FakeClaimParametersSpec{}, testDevice{fakecrd.DefaultRequestName, "fake-0"}),
+				allocatedClaim(t, "unknown-request", DefaultDriverName, &fakecrd.FakeClaimParametersSpec{}, testDevice{"missing", "fake-1"}),
+			},
+			models: map[string]string{"fake-0": fakecrd.FakeModelUltra10},
+			wantStatus: &fakecrd.FakeDeviceQuotaStatus{
+				ObservedGeneration: 1,
+				Used: fakecrd.FakeDeviceUsage{
+					Devices:    1,
+					Partitions: 1,
+					Models:     map[string]int{fakecrd.FakeModelUltra10: 1},
+				},
+				Claims: 1,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, ctx := ktesting.NewTestContext(t)
+
+			shakeclient := shakefake.NewSimpleClientset()
+			var applied *fakecrd.FakeDeviceQuota
+			shakeclient.PrependReactor("patch", "fakedevicequotas", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				applied = &fakecrd.FakeDeviceQuota{}
+				if err := json.Unmarshal(action.(k8stesting.PatchAction).GetPatch(), applied); err != nil {
+					return true, nil, err
+				}
+				return true, applied, nil
+			})
+
+			c, err := NewQuotaController(k8sfake.NewSimpleClientset(), shakeclient, Options{})
+			if err != nil {
+				t.Fatalf("error creating controller: %v", err)
+			}
+			quota := &fakecrd.FakeDeviceQuota{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "quota", Generation: 1},
+				Spec:       fakecrd.FakeDeviceQuotaSpec{Devices: ptr.To(4)},
+			}
+			if err := c.fakeDeviceQuotaInformer.GetIndexer().Add(quota); err != nil {
+				t.Fatal(err)
+			}
+			for _, claim := range tc.claims {
+				if err := c.resourceClaimInformer.GetIndexer().Add(claim); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := c.resourceSliceInformer.GetIndexer().Add(resourceSlice(tc.models)); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := c.sync(ctx, testNamespace); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if applied == nil {
+				t.Fatal("expected the status of the quota to be applied")
+			}
+			if !apiequality.Semantic.DeepEqual(&applied.Status, tc.wantStatus) {
+				t.Errorf("expected status %+v, got %+v", *tc.wantStatus, applied.Status)
+			}
+		})
+	}
+}
+
+// allocatedClaim returns a claim of the namespace allocated the devices on
+// the test node for the requests of spec
+func allocatedClaim(t *testing.T, name, driverName string, spec *fakecrd.FakeClaimParametersSpec, devices ...testDevice) *resourceapi.ResourceClaim {
+	t.Helper()
+	fakecrd.SetDefaultsFakeClaimParametersSpec(spec)
+	vendorClaimParameters, err := vendorparameters.EncodeClaimParameters(spec)
+	if err != nil {
+		t.Fatalf("error encoding vendor claim parameters: %v", err)
+	}
+
+	handle := &resourceapi.StructuredResourceHandle{
+		VendorClaimParameters: runtime.RawExtension{Raw: vendorClaimParameters},
+		NodeName:              testNodeName,
+	}
+	for _, device := range devices {
+		vendorRequestParameters, err := vendorparameters.EncodeRequestParameters(device.request)
+		if err != nil {
+			t.Fatalf("error encoding vendor request parameters: %v", err)
+		}
+		handle.Results = append(handle.Results, resourceapi.DriverAllocationResult{
+			VendorRequestParameters: runtime.RawExtension{Raw: vendorRequestParameters},
+			AllocationResultModel: resourceapi.AllocationResultModel{
+				NamedResources: &resourceapi.NamedResourcesAllocationResult{Name: device.name},
+			},
+		})
+	}
+
+	return &resourceapi.ResourceClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Status: resourceapi.ResourceClaimStatus{
+			Allocation: &resourceapi.AllocationResult{
+				ResourceHandles: []resourceapi.ResourceHandle{{
+					DriverName:     driverName,
+					StructuredData: handle,
+				}},
+			},
+		},
+	}
+}
+
+// resourceSlice publishes devices of the given models on the test node
+func resourceSlice(models map[string]string) *resourceapi.ResourceSlice {
+	slice := &resourceapi.ResourceSlice{
+		ObjectMeta: metav1.ObjectMeta{Name: testNodeName},
+		NodeName:   testNodeName,
+		DriverName: DefaultDriverName,
+		ResourceModel: resourceapi.ResourceModel{
+			NamedResources: &resourceapi.NamedResourcesResources{},
+		},
+	}
+	for name, model := range models {
+		slice.NamedResources.Instances = append(slice.NamedResources.Instances, resourceapi.NamedResourcesInstance{
+			Name: name,
+			Attributes: []resourceapi.NamedResourcesAttribute{{
+				Name:                         "model",
+				NamedResourcesAttributeValue: resourceapi.NamedResourcesAttributeValue{StringValue: ptr.To(model)},
+			}},
+		})
+	}
+	return slice
+}
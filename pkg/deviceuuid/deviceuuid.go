@@ -0,0 +1,67 @@
+// Package deviceuuid derives the UUIDs of the emulated devices and of the
+// partitions they are split into. The UUIDs only depend on their seed, so that
+// the kubelet plugin hands the same UUIDs to containers across restarts and
+// kubectl-fake_dra can tell which partitions a claim was given.
+package deviceuuid
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/google/uuid"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+)
+
+// Prefixes of the UUIDs of each type of device
+const (
+	FakePrefix        = "FAKE-"
+	NICPrefix         = "NIC-"
+	AcceleratorPrefix = "ACCEL-"
+)
+
+// Generate returns count UUIDs with the given prefix derived from the seed
+func Generate(seed string, prefix string, count int) []string {
+	rand := rand.New(rand.NewSource(Hash(seed)))
+
+	uuids := make([]string, count)
+	for i := 0; i < count; i++ {
+		charset := make([]byte, 16)
+		rand.Read(charset)
+		uuid, _ := uuid.FromBytes(charset)
+		uuids[i] = prefix + uuid.String()
+	}
+	return uuids
+}
+
+// Partitions returns the UUIDs of the partitions a device of the given type
+// is split into. A device which is not split is handed out as is.
+func Partitions(deviceType, parent string, split int) []string {
+	if split <= 0 {
+		return []string{parent}
+	}
+
+	switch deviceType {
+	case fakecrd.NICDeviceType:
+		// The virtual functions of a NIC are numbered after their physical
+		// function
+		vfs := make([]string, split)
+		for i := range vfs {
+			vfs[i] = fmt.Sprintf("%s-VF%d", parent, i)
+		}
+		return vfs
+	case fakecrd.AcceleratorDeviceType:
+		return Generate(parent, AcceleratorPrefix, split)
+	default:
+		return Generate(parent, FakePrefix, split)
+	}
+}
+
+// Hash returns the seed of the random source of a string
+func Hash(s string) int64 {
+	h := int64(0)
+	for _, c := range s {
+		h = 31*h + int64(c)
+	}
+	return h
+}
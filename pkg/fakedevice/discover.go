@@ -0,0 +1,454 @@
+package fakedevice
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+)
+
+// deviceEnv names the environment variables describing the devices of a
+// type. The devices are numbered per type and claim, the variables holding
+// an index take it as their only argument.
+type deviceEnv struct {
+	deviceType string
+	uuid       string
+	model      string
+	parent     string
+	partition  string
+	partitions string
+	// memory and speed are only set for the types having them
+	memory string
+	speed  string
+}
+
+var deviceEnvs = []deviceEnv{
+	{
+		deviceType: fakecrd.FakeDeviceType,
+		uuid:       "FAKE_DEVICE_%d",
+		model:      "FAKE_DEVICE_MODEL",
+		parent:     "FAKE_DEVICE_%d_PARENT",
+		partition:  "FAKE_DEVICE_%d_PARTITION",
+		partitions: "FAKE_DEVICE_%d_PARTITIONS",
+	},
+	{
+		deviceType: fakecrd.NICDeviceType,
+		uuid:       "FAKE_NIC_%d",
+		model:      "FAKE_NIC_MODEL",
+		parent:     "FAKE_NIC_%d_PF",
+		partition:  "FAKE_NIC_%d_VF_INDEX",
+		partitions: "FAKE_NIC_%d_VFS",
+		speed:      "FAKE_NIC_%d_SPEED_GBPS",
+	},
+	{
+		deviceType: fakecrd.AcceleratorDeviceType,
+		uuid:       "FAKE_ACCELERATOR_%d",
+		model:      "FAKE_ACCELERATOR_MODEL",
+		parent:     "FAKE_ACCELERATOR_%d_PARENT",
+		partition:  "FAKE_ACCELERATOR_%d_PARTITION",
+		partitions: "FAKE_ACCELERATOR_%d_PARTITIONS",
+		memory:     "FAKE_ACCELERATOR_%d_MEMORY",
+	},
+}
+
+// Allocation is what the kubelet plugin assigned to the container
+type Allocation struct {
+	// NodeName is the name of the node the devices are emulated on
+	NodeName string `json:"nodeName"`
+	// DriverName is the name of the driver the devices were allocated by
+	DriverName string `json:"driverName"`
+	// Devices are the devices assigned to the container, ordered by type
+	// and by the index they were handed out with
+	Devices []Device `json:"devices"`
+	// Configs are the device configs of the claims which have one
+	Configs []ClaimConfig `json:"configs,omitempty"`
+	// AdminAccess tells whether the container uses an admin access claim,
+	// which sees the inventory of the node instead of devices
+	AdminAccess bool `json:"adminAccess,omitempty"`
+	// InventoryPath is the path of the inventory file of the admin access
+	// claim
+	InventoryPath string `json:"inventoryPath,omitempty"`
+	// UsageDirs are the directories the usage of the devices is reported
+	// in, one per claim. They are only mounted when the kubelet plugin
+	// exports telemetry.
+	UsageDirs []string `json:"usageDirs,omitempty"`
+}
+
+// Discover returns the devices assigned to the current container, read from
+// its environment and from the descriptor files mounted under
+// ClaimConfigRoot. Only the directories of the claims of the container are
+// mounted there.
+func Discover() (*Allocation, error) {
+	claimUIDs, err := listClaimUIDs(ClaimConfigRoot)
+	if err != nil {
+		return nil, err
+	}
+	return DiscoverFrom(os.Environ(), ClaimConfigRoot, claimUIDs...)
+}
+
+// DiscoverFrom returns the devices described by the given environment, in
+// the form of os.Environ, and by the descriptor files of the given claims
+// under claimConfigRoot. The files the environment points to are read too,
+// from claimConfigRoot instead of ClaimConfigRoot if they live there.
+// Tests use it to discover devices from the environment of another process
+// or from files written by a kubelet plugin running in process, whose root
+// holds the directories of every claim prepared on the node: only the claims
+// of the container must be passed.
+func DiscoverFrom(environ []string, claimConfigRoot string, claimUIDs ...string) (*Allocation, error) {
+	env := map[string]string{}
+	for _, kv := range environ {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+
+	allocation := &Allocation{
+		NodeName:      env[EnvNodeName],
+		DriverName:    env[EnvDriverName],
+		Devices:       []Device{},
+		AdminAccess:   env[EnvAdminAccess] == "true",
+		InventoryPath: env[EnvInventory],
+	}
+
+	for _, de := range deviceEnvs {
+		devices, err := de.discover(env)
+		if err != nil {
+			return nil, err
+		}
+		allocation.Devices = append(allocation.Devices, devices...)
+	}
+
+	configs, err := readClaimConfigs(claimConfigRoot, claimUIDs, env[EnvConfig])
+	if err != nil {
+		return nil, err
+	}
+	allocation.Configs = configs
+	allocation.mergeClaimConfigs()
+
+	usageDirs, err := findUsageDirs(claimConfigRoot, claimUIDs, env[EnvUsageDir])
+	if err != nil {
+		return nil, err
+	}
+	allocation.UsageDirs = usageDirs
+
+	for i := range allocation.Devices {
+		setCapacity(&allocation.Devices[i])
+	}
+	return allocation, nil
+}
+
+// discover returns the devices of the type found in the environment
+func (de *deviceEnv) discover(env map[string]string) ([]Device, error) {
+	prefix, _, _ := strings.Cut(de.uuid, "%d")
+
+	var indexes []int
+	for key := range env {
+		suffix, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		// Skips the other variables sharing the prefix, such as the model
+		index, err := strconv.Atoi(suffix)
+		if err != nil || index < 0 {
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+	slices.Sort(indexes)
+
+	devices := make([]Device, 0, len(indexes))
+	for _, index := range indexes {
+		device := Device{
+			UUID:   env[fmt.Sprintf(de.uuid, index)],
+			Type:   de.deviceType,
+			Model:  env[de.model],
+			Parent: env[fmt.Sprintf(de.parent, index)],
+		}
+		var err error
+		if device.Partition, err = intEnv(env, fmt.Sprintf(de.partition, index)); err != nil {
+			return nil, err
+		}
+		if device.Partitions, err = intEnv(env, fmt.Sprintf(de.partitions, index)); err != nil {
+			return nil, err
+		}
+		if de.memory != "" {
+			key := fmt.Sprintf(de.memory, index)
+			if value, ok := env[key]; ok {
+				memory, err := resource.ParseQuantity(value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value of %s: %w", key, err)
+				}
+				device.Memory = &memory
+			}
+		}
+		if de.speed != "" {
+			speed, err := intEnv(env, fmt.Sprintf(de.speed, index))
+			if err != nil {
+				return nil, err
+			}
+			device.SpeedGbps = int64(speed)
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// intEnv returns the integer value of an environment variable, 0 if unset
+func intEnv(env map[string]string, key string) (int, error) {
+	value, ok := env[key]
+	if !ok {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value of %s: %w", key, err)
+	}
+	return i, nil
+}
+
+// listClaimUIDs returns the UIDs of the claims which have a directory under
+// root, none if root does not exist
+func listClaimUIDs(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error listing claim directories: %w", err)
+	}
+	var claimUIDs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			claimUIDs = append(claimUIDs, entry.Name())
+		}
+	}
+	return claimUIDs, nil
+}
+
+// claimPaths returns the paths of the file or directory called name of the
+// given claims under root which exist, together with envPath, the path
+// pointed to by the environment. envPath is moved under root if it is below
+// ClaimConfigRoot.
+func claimPaths(root string, claimUIDs []string, name, envPath string) ([]string, error) {
+	var paths []string
+	if root != "" {
+		for _, claimUID := range claimUIDs {
+			path := filepath.Join(root, claimUID, name)
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			paths = append(paths, path)
+		}
+	}
+	if envPath != "" {
+		envPath = filepath.Clean(envPath)
+		if rel, ok := strings.CutPrefix(envPath, ClaimConfigRoot+string(filepath.Separator)); ok && root != "" {
+			envPath = filepath.Join(root, rel)
+		}
+		if !slices.Contains(paths, envPath) {
+			paths = append(paths, envPath)
+		}
+	}
+	return paths, nil
+}
+
+// readClaimConfigs reads the descriptor files of the given claims under root
+// together with the one pointed to by configPath. Claims without a
+// descriptor file are skipped since only claims with a device config have
+// one.
+func readClaimConfigs(root string, claimUIDs []string, configPath string) ([]ClaimConfig, error) {
+	paths, err := claimPaths(root, claimUIDs, ClaimConfigFileName, configPath)
+	if err != nil {
+		return nil, fmt.Errorf("error looking up claim config files: %w", err)
+	}
+
+	configs := make([]ClaimConfig, 0, len(paths))
+	for _, path := range paths {
+		config, err := ReadClaimConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, *config)
+	}
+	slices.SortFunc(configs, func(a, b ClaimConfig) int {
+		return cmp.Compare(a.ClaimUID, b.ClaimUID)
+	})
+	return configs, nil
+}
+
+// findUsageDirs returns the usage directories of the given claims under root
+// together with the one pointed to by usageDir
+func findUsageDirs(root string, claimUIDs []string, usageDir string) ([]string, error) {
+	dirs, err := claimPaths(root, claimUIDs, UsageDirName, usageDir)
+	if err != nil {
+		return nil, fmt.Errorf("error looking up usage directories: %w", err)
+	}
+	slices.Sort(dirs)
+	return dirs, nil
+}
+
+// ReadClaimConfig reads the descriptor file of the device config of a claim
+func ReadClaimConfig(path string) (*ClaimConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading claim config %s: %w", path, err)
+	}
+	config := &ClaimConfig{}
+	if err := json.Unmarshal(raw, config); err != nil {
+		return nil, fmt.Errorf("error decoding claim config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// ReadInventory reads the inventory file of an admin access claim
+func ReadInventory(path string) (*Inventory, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading device inventory %s: %w", path, err)
+	}
+	inventory := &Inventory{}
+	if err := json.Unmarshal(raw, inventory); err != nil {
+		return nil, fmt.Errorf("error decoding device inventory %s: %w", path, err)
+	}
+	return inventory, nil
+}
+
+// mergeClaimConfigs completes the devices found in the environment with the
+// devices listed in the descriptor files. The descriptor files list every
+// device of their claim, including the ones whose variables were overridden
+// by another claim numbering its devices from the same index.
+func (a *Allocation) mergeClaimConfigs() {
+	indexes := map[string]int{}
+	for i, device := range a.Devices {
+		indexes[device.UUID] = i
+	}
+
+	for _, config := range a.Configs {
+		for _, configDevice := range config.Devices {
+			i, ok := indexes[configDevice.UUID]
+			if !ok {
+				indexes[configDevice.UUID] = len(a.Devices)
+				a.Devices = append(a.Devices, Device{
+					UUID:       configDevice.UUID,
+					Type:       configDevice.Type,
+					Model:      configDevice.Model,
+					Parent:     configDevice.Parent,
+					Partition:  configDevice.Partition,
+					Partitions: configDevice.Partitions,
+				})
+				continue
+			}
+			device := &a.Devices[i]
+			if device.Model == "" {
+				device.Model = configDevice.Model
+			}
+			if device.Parent == "" {
+				device.Parent = configDevice.Parent
+				device.Partition = configDevice.Partition
+				device.Partitions = configDevice.Partitions
+			}
+		}
+	}
+}
+
+// setCapacity derives the memory of accelerators and the speed of NICs from
+// their model when the environment does not provide them
+func setCapacity(device *Device) {
+	partitions := int64(max(device.Partitions, 1))
+	switch device.Type {
+	case fakecrd.AcceleratorDeviceType:
+		if memory, ok := fakecrd.AcceleratorMemory(device.Model); ok && device.Memory == nil {
+			device.Memory = resource.NewQuantity(memory.Value()/partitions, resource.BinarySI)
+		}
+	case fakecrd.NICDeviceType:
+		if speed, ok := fakecrd.NICSpeedGbps(device.Model); ok && device.SpeedGbps == 0 {
+			device.SpeedGbps = speed / partitions
+		}
+	}
+}
+
+// DevicesOfType returns the devices of the given type
+func (a *Allocation) DevicesOfType(deviceType string) []Device {
+	var devices []Device
+	for _, device := range a.Devices {
+		if device.Type == deviceType {
+			devices = append(devices, device)
+		}
+	}
+	return devices
+}
+
+// Device returns the device with the given UUID
+func (a *Allocation) Device(uuid string) (*Device, bool) {
+	for i := range a.Devices {
+		if a.Devices[i].UUID == uuid {
+			return &a.Devices[i], true
+		}
+	}
+	return nil, false
+}
+
+// Inventory reads the inventory of the node seen by the admin access claim
+func (a *Allocation) Inventory() (*Inventory, error) {
+	if !a.AdminAccess || a.InventoryPath == "" {
+		return nil, fmt.Errorf("container has no admin access claim")
+	}
+	return ReadInventory(a.InventoryPath)
+}
+
+// ReportUsage reports the usage of a device to the kubelet plugin, which
+// exports it as the telemetry of the device. The plugin falls back to
+// synthetic values when the usage is not reported again within a minute.
+// The usage is written to the usage directory of every claim of the
+// container since the claim of a device is not known from its environment,
+// the plugin only reads it from the directory of the claim holding the
+// device.
+func (a *Allocation) ReportUsage(uuid string, usage Usage) error {
+	if _, ok := a.Device(uuid); !ok {
+		return fmt.Errorf("device %s is not assigned to the container", uuid)
+	}
+	if len(a.UsageDirs) == 0 {
+		return fmt.Errorf("no usage directory, the kubelet plugin does not export telemetry")
+	}
+	if usage.Utilization < 0 || usage.Utilization > 1 {
+		return fmt.Errorf("utilization %g of device %s must be between 0 and 1", usage.Utilization, uuid)
+	}
+	raw, err := json.Marshal(&usage)
+	if err != nil {
+		return fmt.Errorf("error encoding usage of device %s: %w", uuid, err)
+	}
+	for _, dir := range a.UsageDirs {
+		if err := writeFileAtomically(filepath.Join(dir, UsageFileName(uuid)), raw); err != nil {
+			return fmt.Errorf("error reporting usage of device %s: %w", uuid, err)
+		}
+	}
+	return nil
+}
+
+// writeFileAtomically replaces a file by renaming a new one into place, so
+// that readers never see a partial file
+func writeFileAtomically(path string, raw []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,228 @@
+package fakedevice
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+)
+
+func TestDiscoverFrom(t *testing.T) {
+	testCases := map[string]struct {
+		environ []string
+		// configs are written under the claim config root, keyed on the
+		// UID of their claim
+		configs map[string]*ClaimConfig
+		// usageDirs are the claims with a usage directory
+		usageDirs []string
+		claimUIDs []string
+
+		wantDevices   []string
+		wantConfigs   []string
+		wantUsageDirs []string
+		wantErr       bool
+	}{
+		"whole devices": {
+			environ: []string{
+				"FAKE_NODE_NAME=node",
+				"DRA_RESOURCE_DRIVER_NAME=fake.resource.3-shake.com",
+				"FAKE_DEVICE_1=FAKE-b",
+				"FAKE_DEVICE_0=FAKE-a",
+				"FAKE_DEVICE_MODEL=ULTRA_10",
+			},
+			wantDevices: []string{
+				"FAKE-a fake ULTRA_10",
+				"FAKE-b fake ULTRA_10",
+			},
+		},
+		"accelerator partitions": {
+			environ: []string{
+				"FAKE_ACCELERATOR_0=ACCEL-a",
+				"FAKE_ACCELERATOR_0_PARENT=ACCEL-p",
+				"FAKE_ACCELERATOR_0_PARTITION=1",
+				"FAKE_ACCELERATOR_0_PARTITIONS=2",
+				"FAKE_ACCELERATOR_MODEL=ACCEL_32G",
+			},
+			wantDevices: []string{
+				"ACCEL-a accelerator ACCEL_32G parent=ACCEL-p 1/2 memory=16Gi",
+			},
+		},
+		"nic virtual functions": {
+			environ: []string{
+				"FAKE_NIC_0=NIC-p-VF0",
+				"FAKE_NIC_0_PF=NIC-p",
+				"FAKE_NIC_0_VF_INDEX=0",
+				"FAKE_NIC_0_VFS=4",
+				"FAKE_NIC_0_SPEED_GBPS=6",
+				"FAKE_NIC_MODEL=NIC_25G",
+			},
+			wantDevices: []string{
+				"NIC-p-VF0 nic NIC_25G parent=NIC-p 0/4 speed=6",
+			},
+		},
+		"invalid number of partitions": {
+			environ: []string{
+				"FAKE_DEVICE_0=FAKE-a",
+				"FAKE_DEVICE_0_PARTITIONS=two",
+			},
+			wantErr: true,
+		},
+		"devices of the claims completed from their config": {
+			// The second claim numbered its devices from the same index
+			// and overrode the variables of the first one
+			environ: []string{
+				"FAKE_DEVICE_0=FAKE-c",
+				"FAKE_DEVICE_MODEL=ULTRA_10",
+				"FAKE_DEVICE_CONFIG=/var/run/fake-dra/claims/claim-b/config.json",
+			},
+			configs: map[string]*ClaimConfig{
+				"claim-a": claimConfig("claim-a", "FAKE-a", "FAKE-b"),
+				"claim-b": claimConfig("claim-b", "FAKE-c"),
+			},
+			claimUIDs: []string{"claim-a", "claim-b"},
+			wantDevices: []string{
+				"FAKE-c fake ULTRA_10",
+				"FAKE-a fake ULTRA_10",
+				"FAKE-b fake ULTRA_10",
+			},
+			wantConfigs: []string{"claim-a", "claim-b"},
+		},
+		"claims of other containers ignored": {
+			environ: []string{
+				"FAKE_DEVICE_0=FAKE-a",
+				"FAKE_DEVICE_MODEL=ULTRA_10",
+				"FAKE_DEVICE_USAGE_DIR=/var/run/fake-dra/claims/claim-a/usage",
+			},
+			configs: map[string]*ClaimConfig{
+				"claim-a": claimConfig("claim-a", "FAKE-a"),
+				"claim-b": claimConfig("claim-b", "FAKE-b"),
+			},
+			usageDirs:     []string{"claim-a", "claim-b"},
+			claimUIDs:     []string{"claim-a"},
+			wantDevices:   []string{"FAKE-a fake ULTRA_10"},
+			wantConfigs:   []string{"claim-a"},
+			wantUsageDirs: []string{"claim-a"},
+		},
+		"only the files the environment points to without claims": {
+			environ: []string{
+				"FAKE_DEVICE_0=FAKE-b",
+				"FAKE_DEVICE_MODEL=ULTRA_10",
+				"FAKE_DEVICE_CONFIG=/var/run/fake-dra/claims/claim-b/config.json",
+				"FAKE_DEVICE_USAGE_DIR=/var/run/fake-dra/claims/claim-b/usage",
+			},
+			configs: map[string]*ClaimConfig{
+				"claim-a": claimConfig("claim-a", "FAKE-a"),
+				"claim-b": claimConfig("claim-b", "FAKE-b"),
+			},
+			usageDirs:     []string{"claim-a", "claim-b"},
+			wantDevices:   []string{"FAKE-b fake ULTRA_10"},
+			wantConfigs:   []string{"claim-b"},
+			wantUsageDirs: []string{"claim-b"},
+		},
+		"missing config the environment points to": {
+			environ: []string{
+				"FAKE_DEVICE_0=FAKE-a",
+				"FAKE_DEVICE_CONFIG=/var/run/fake-dra/claims/claim-a/config.json",
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			for claimUID, config := range tc.configs {
+				writeClaimConfig(t, root, claimUID, config)
+			}
+			for _, claimUID := range tc.usageDirs {
+				if err := os.MkdirAll(filepath.Join(root, claimUID, UsageDirName), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			allocation, err := DiscoverFrom(tc.environ, root, tc.claimUIDs...)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", allocation)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var devices []string
+			for _, device := range allocation.Devices {
+				devices = append(devices, describeDevice(device))
+			}
+			if !slices.Equal(devices, tc.wantDevices) {
+				t.Errorf("expected devices %q, got %q", tc.wantDevices, devices)
+			}
+
+			var configs []string
+			for _, config := range allocation.Configs {
+				configs = append(configs, config.ClaimUID)
+			}
+			if !slices.Equal(configs, tc.wantConfigs) {
+				t.Errorf("expected configs of claims %q, got %q", tc.wantConfigs, configs)
+			}
+
+			var usageDirs []string
+			for _, claimUID := range tc.wantUsageDirs {
+				usageDirs = append(usageDirs, filepath.Join(root, claimUID, UsageDirName))
+			}
+			if !slices.Equal(allocation.UsageDirs, usageDirs) {
+				t.Errorf("expected usage directories %q, got %q", usageDirs, allocation.UsageDirs)
+			}
+		})
+	}
+}
+
+func claimConfig(claimUID string, uuids ...string) *ClaimConfig {
+	config := &ClaimConfig{
+		ClaimUID: claimUID,
+		Mode:     fakecrd.FakeDeviceModeCompute,
+	}
+	for _, uuid := range uuids {
+		config.Devices = append(config.Devices, ClaimConfigDevice{
+			UUID:  uuid,
+			Type:  fakecrd.FakeDeviceType,
+			Model: fakecrd.FakeModelUltra10,
+		})
+	}
+	return config
+}
+
+func writeClaimConfig(t *testing.T, root, claimUID string, config *ClaimConfig) {
+	t.Helper()
+	raw, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(root, claimUID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ClaimConfigFileName), raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// describeDevice summarizes the fields of a device which are set
+func describeDevice(device Device) string {
+	s := fmt.Sprintf("%s %s %s", device.UUID, device.Type, device.Model)
+	if device.IsPartition() {
+		s += fmt.Sprintf(" parent=%s %d/%d", device.Parent, device.Partition, device.Partitions)
+	}
+	if device.Memory != nil {
+		s += " memory=" + device.Memory.String()
+	}
+	if device.SpeedGbps != 0 {
+		s += fmt.Sprintf(" speed=%d", device.SpeedGbps)
+	}
+	return s
+}
@@ -0,0 +1,156 @@
+// Package fakedevice lets workloads discover the fake devices assigned to
+// their container, the way they would use the SDK of a real device. The
+// kubelet plugin hands the devices to the containers through CDI, as
+// environment variables and as descriptor files mounted read-only. Discover
+// reads both and returns the devices with their UUID, model, parent and
+// share of the parent, so that workloads and their tests do not have to
+// parse the environment by hand.
+//
+// The driver does not serve a device socket to containers, everything the
+// library reports comes from the environment and the mounted files.
+package fakedevice
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+)
+
+// Environment variables set in the containers by the kubelet plugin
+const (
+	// EnvNodeName is the name of the node the devices are emulated on
+	EnvNodeName = "FAKE_NODE_NAME"
+	// EnvDriverName is the name of the driver the devices were allocated by
+	EnvDriverName = "DRA_RESOURCE_DRIVER_NAME"
+	// EnvConfig is the path of the descriptor file of the device config of
+	// a claim
+	EnvConfig = "FAKE_DEVICE_CONFIG"
+	// EnvMode is the mode the devices of a claim are operated in
+	EnvMode = "FAKE_DEVICE_MODE"
+	// EnvClockProfile is the clock profile the devices of a claim run with
+	EnvClockProfile = "FAKE_DEVICE_CLOCK_PROFILE"
+	// EnvPowerLimitWatts caps the power draw of each device of a claim
+	EnvPowerLimitWatts = "FAKE_DEVICE_POWER_LIMIT_WATTS"
+	// EnvAdminAccess is set to true for admin access claims
+	EnvAdminAccess = "FAKE_ADMIN_ACCESS"
+	// EnvInventory is the path of the inventory file of an admin access
+	// claim
+	EnvInventory = "FAKE_DEVICE_INVENTORY"
+	// EnvUsageDir is the path of the writable directory the usage of the
+	// devices of a claim is reported in, set when the kubelet plugin
+	// exports telemetry
+	EnvUsageDir = "FAKE_DEVICE_USAGE_DIR"
+)
+
+const (
+	// ClaimConfigRoot is where the descriptor files of claims are mounted in
+	// containers, in a directory named after the UID of each claim
+	ClaimConfigRoot = "/var/run/fake-dra/claims"
+	// ClaimConfigFileName is the name of the descriptor file of a claim
+	ClaimConfigFileName = "config.json"
+	// InventoryFileName is the name of the inventory file of an admin access
+	// claim
+	InventoryFileName = "inventory.json"
+	// UsageDirName is the name of the directory next to the descriptor file
+	// of a claim the usage of its devices is reported in
+	UsageDirName = "usage"
+)
+
+// Device is a device assigned to the container, or a partition of one
+type Device struct {
+	UUID  string `json:"uuid"`
+	Type  string `json:"type"`
+	Model string `json:"model"`
+	// Parent is the UUID of the device a partition was split from, empty
+	// for whole devices
+	Parent string `json:"parent,omitempty"`
+	// Partition is the position of a partition within its parent
+	Partition int `json:"partition,omitempty"`
+	// Partitions is the number of partitions the parent was split into, 0
+	// for whole devices. A parent split into a single partition still
+	// hands it out with a UUID of its own.
+	Partitions int `json:"partitions,omitempty"`
+	// Memory is the memory of an accelerator, or of its partition
+	Memory *resource.Quantity `json:"memory,omitempty"`
+	// SpeedGbps is the link speed of a NIC, or of its virtual function
+	SpeedGbps int64 `json:"speedGbps,omitempty"`
+}
+
+// IsPartition tells whether the device was split from a parent device
+func (d *Device) IsPartition() bool {
+	return d.Parent != ""
+}
+
+// Fraction returns the share of its parent the device represents, 1 for
+// whole devices
+func (d *Device) Fraction() float64 {
+	if d.Partitions <= 1 {
+		return 1
+	}
+	return 1 / float64(d.Partitions)
+}
+
+// String returns the UUID of the device
+func (d *Device) String() string {
+	if d.IsPartition() {
+		return fmt.Sprintf("%s (partition %d/%d of %s)", d.UUID, d.Partition+1, d.Partitions, d.Parent)
+	}
+	return d.UUID
+}
+
+// ClaimConfig is the content of the descriptor file of the device config of
+// a claim, which lists the devices prepared for the claim
+type ClaimConfig struct {
+	ClaimUID        string               `json:"claimUID"`
+	Mode            string               `json:"mode"`
+	ClockProfile    string               `json:"clockProfile"`
+	PowerLimitWatts *int                 `json:"powerLimitWatts,omitempty"`
+	Env             []fakecrd.FakeEnvVar `json:"env,omitempty"`
+	Devices         []ClaimConfigDevice  `json:"devices"`
+}
+
+// ClaimConfigDevice is a device listed in the descriptor file of a claim
+type ClaimConfigDevice struct {
+	UUID       string `json:"uuid"`
+	Type       string `json:"type"`
+	Model      string `json:"model"`
+	Parent     string `json:"parent,omitempty"`
+	Partition  int    `json:"partition,omitempty"`
+	Partitions int    `json:"partitions,omitempty"`
+}
+
+// Inventory is the content of the inventory file of an admin access claim.
+// The kubelet plugin rewrites it whenever a claim is prepared or unprepared
+// on the node.
+type Inventory struct {
+	Devices []InventoryDevice `json:"devices"`
+}
+
+// InventoryDevice is a device of the node listed in an inventory
+type InventoryDevice struct {
+	UUID    string `json:"uuid"`
+	Type    string `json:"type"`
+	Model   string `json:"model"`
+	Healthy bool   `json:"healthy"`
+	// Claims are the UIDs of the claims the device, or a partition of it,
+	// is prepared for
+	Claims []string `json:"claims,omitempty"`
+}
+
+// Usage is the usage of a device reported by the workload, in a file named
+// after the UUID of the device in the usage directory of its claim. The
+// kubelet plugin exports it as the telemetry of the device instead of
+// synthetic values while the file is kept up to date.
+type Usage struct {
+	// Utilization is the share of the device in use, between 0 and 1
+	Utilization float64 `json:"utilization"`
+	// MemoryUsedBytes is the memory in use, for devices having memory
+	MemoryUsedBytes *int64 `json:"memoryUsedBytes,omitempty"`
+}
+
+// UsageFileName returns the name of the usage file of a device
+func UsageFileName(uuid string) string {
+	return uuid + ".json"
+}
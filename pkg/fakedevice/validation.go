@@ -0,0 +1,128 @@
+package fakedevice
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	"github.com/toVersus/fake-dra-driver/pkg/deviceuuid"
+)
+
+// uuidPrefixes are the prefixes of the UUIDs of each type of device
+var uuidPrefixes = map[string]string{
+	fakecrd.FakeDeviceType:        deviceuuid.FakePrefix,
+	fakecrd.NICDeviceType:         deviceuuid.NICPrefix,
+	fakecrd.AcceleratorDeviceType: deviceuuid.AcceleratorPrefix,
+}
+
+// ValidateDevice checks that a device is one the kubelet plugin can hand
+// out: its UUID and model match its type, and a partition has the UUID the
+// plugin derives from its parent for its position.
+func ValidateDevice(device *Device) error {
+	var errs []error
+	prefix, ok := uuidPrefixes[device.Type]
+	if !ok {
+		return fmt.Errorf("device %s: unknown device type %q, must be one of %v", device.UUID, device.Type, fakecrd.DeviceTypes())
+	}
+	if !strings.HasPrefix(device.UUID, prefix) {
+		errs = append(errs, fmt.Errorf("device %s: UUID of %s device must start with %s", device.UUID, device.Type, prefix))
+	}
+	if !slices.Contains(fakecrd.DeviceTypeModels(device.Type), device.Model) {
+		errs = append(errs, fmt.Errorf("device %s: unknown %s model %q, must be one of %v", device.UUID, device.Type, device.Model, fakecrd.DeviceTypeModels(device.Type)))
+	}
+
+	switch {
+	case device.IsPartition() && device.Partitions <= 0:
+		errs = append(errs, fmt.Errorf("device %s: partition of %s has no number of partitions", device.UUID, device.Parent))
+	case !device.IsPartition() && device.Partitions > 0:
+		errs = append(errs, fmt.Errorf("device %s: split into %d partitions without a parent", device.UUID, device.Partitions))
+	case device.IsPartition():
+		if maxSplit, ok := fakecrd.MaxSplit(device.Model); ok && device.Partitions > maxSplit {
+			errs = append(errs, fmt.Errorf("device %s: %s device supports at most %d partitions, split into %d", device.UUID, device.Model, maxSplit, device.Partitions))
+		}
+		if device.Partition < 0 || device.Partition >= device.Partitions {
+			errs = append(errs, fmt.Errorf("device %s: partition %d out of %d partitions", device.UUID, device.Partition, device.Partitions))
+		} else if want := deviceuuid.Partitions(device.Type, device.Parent, device.Partitions)[device.Partition]; device.UUID != want {
+			errs = append(errs, fmt.Errorf("device %s: partition %d of %s must have UUID %s", device.UUID, device.Partition, device.Parent, want))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks that the allocation is consistent: every device is valid
+// and assigned once, the partitions of a parent agree on their number and
+// the devices of a type share their model like on a real node.
+func (a *Allocation) Validate() error {
+	var errs []error
+	if len(a.Devices) > 0 {
+		if a.NodeName == "" {
+			errs = append(errs, fmt.Errorf("%s is not set", EnvNodeName))
+		}
+		if a.DriverName == "" {
+			errs = append(errs, fmt.Errorf("%s is not set", EnvDriverName))
+		}
+	}
+
+	uuids := map[string]bool{}
+	partitions := map[string]int{}
+	models := map[string]string{}
+	for i := range a.Devices {
+		device := &a.Devices[i]
+		if err := ValidateDevice(device); err != nil {
+			errs = append(errs, err)
+		}
+		if uuids[device.UUID] {
+			errs = append(errs, fmt.Errorf("device %s: assigned more than once", device.UUID))
+		}
+		uuids[device.UUID] = true
+
+		if device.IsPartition() {
+			if n, ok := partitions[device.Parent]; ok && n != device.Partitions {
+				errs = append(errs, fmt.Errorf("device %s: %s split into both %d and %d partitions", device.UUID, device.Parent, n, device.Partitions))
+			}
+			partitions[device.Parent] = device.Partitions
+		}
+		if model, ok := models[device.Type]; ok && model != device.Model {
+			errs = append(errs, fmt.Errorf("device %s: %s devices of both models %s and %s", device.UUID, device.Type, model, device.Model))
+		}
+		models[device.Type] = device.Model
+	}
+	return errors.Join(errs...)
+}
+
+// ExpectDevices checks that count devices or partitions of the given type
+// are assigned to the container
+func (a *Allocation) ExpectDevices(deviceType string, count int) error {
+	if n := len(a.DevicesOfType(deviceType)); n != count {
+		return fmt.Errorf("expected %d %s devices, got %d", count, deviceType, n)
+	}
+	return nil
+}
+
+// ExpectCapacity checks that the devices of the given type add up to the
+// given number of whole devices, such as 0.5 for half of a split device
+func (a *Allocation) ExpectCapacity(deviceType string, capacity float64) error {
+	total := 0.0
+	for _, device := range a.DevicesOfType(deviceType) {
+		total += device.Fraction()
+	}
+	if math.Abs(total-capacity) > 1e-9 {
+		return fmt.Errorf("expected %g %s devices worth of capacity, got %g", capacity, deviceType, total)
+	}
+	return nil
+}
+
+// ExpectModel checks that the devices of the given type are of the given
+// model
+func (a *Allocation) ExpectModel(deviceType, model string) error {
+	var errs []error
+	for _, device := range a.DevicesOfType(deviceType) {
+		if device.Model != model {
+			errs = append(errs, fmt.Errorf("device %s: expected model %s, got %s", device.UUID, model, device.Model))
+		}
+	}
+	return errors.Join(errs...)
+}
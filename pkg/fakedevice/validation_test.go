@@ -0,0 +1,72 @@
+package fakedevice
+
+import (
+	"testing"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	"github.com/toVersus/fake-dra-driver/pkg/deviceuuid"
+)
+
+func TestValidateDevice(t *testing.T) {
+	parent := deviceuuid.AcceleratorPrefix + "parent"
+	partitions := deviceuuid.Partitions(fakecrd.AcceleratorDeviceType, parent, 2)
+
+	testCases := map[string]struct {
+		device  Device
+		wantErr bool
+	}{
+		"whole device": {
+			device: Device{UUID: "FAKE-a", Type: fakecrd.FakeDeviceType, Model: fakecrd.FakeModelUltra10},
+		},
+		"partition": {
+			device: Device{UUID: partitions[1], Type: fakecrd.AcceleratorDeviceType, Model: fakecrd.AcceleratorModel32G, Parent: parent, Partition: 1, Partitions: 2},
+		},
+		"single partition": {
+			device: Device{UUID: deviceuuid.Partitions(fakecrd.NICDeviceType, "NIC-p", 1)[0], Type: fakecrd.NICDeviceType, Model: fakecrd.NICModel25G, Parent: "NIC-p", Partitions: 1},
+		},
+		"unknown type": {
+			device:  Device{UUID: "GPU-a", Type: "gpu", Model: fakecrd.FakeModelUltra10},
+			wantErr: true,
+		},
+		"prefix of another type": {
+			device:  Device{UUID: "NIC-a", Type: fakecrd.FakeDeviceType, Model: fakecrd.FakeModelUltra10},
+			wantErr: true,
+		},
+		"model of another type": {
+			device:  Device{UUID: "FAKE-a", Type: fakecrd.FakeDeviceType, Model: fakecrd.NICModel25G},
+			wantErr: true,
+		},
+		"partition without number of partitions": {
+			device:  Device{UUID: partitions[0], Type: fakecrd.AcceleratorDeviceType, Model: fakecrd.AcceleratorModel32G, Parent: parent},
+			wantErr: true,
+		},
+		"partitions without parent": {
+			device:  Device{UUID: "ACCEL-a", Type: fakecrd.AcceleratorDeviceType, Model: fakecrd.AcceleratorModel32G, Partitions: 2},
+			wantErr: true,
+		},
+		"partition out of range": {
+			device:  Device{UUID: partitions[1], Type: fakecrd.AcceleratorDeviceType, Model: fakecrd.AcceleratorModel32G, Parent: parent, Partition: 2, Partitions: 2},
+			wantErr: true,
+		},
+		"partition with UUID of another position": {
+			device:  Device{UUID: partitions[0], Type: fakecrd.AcceleratorDeviceType, Model: fakecrd.AcceleratorModel32G, Parent: parent, Partition: 1, Partitions: 2},
+			wantErr: true,
+		},
+		"more partitions than the model supports": {
+			device:  Device{UUID: "ACCEL-a", Type: fakecrd.AcceleratorDeviceType, Model: fakecrd.AcceleratorModel32G, Parent: parent, Partitions: 16},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateDevice(&tc.device)
+			if tc.wantErr && err == nil {
+				t.Error("expected an error")
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
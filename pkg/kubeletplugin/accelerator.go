@@ -0,0 +1,85 @@
+package kubeletplugin
+
+import (
+	"context"
+	"fmt"
+
+	cdispec "github.com/container-orchestrated-devices/container-device-interface/specs-go"
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	"github.com/toVersus/fake-dra-driver/pkg/deviceuuid"
+)
+
+const (
+	perNodeAcceleratorDevices = 4
+	acceleratorDevicePrefix   = deviceuuid.AcceleratorPrefix
+)
+
+func init() {
+	registerDeviceType(&acceleratorDeviceType{})
+}
+
+// acceleratorDeviceType emulates accelerators with on-board memory. A split
+// accelerator is handed out as partitions with an equal share of the
+// memory.
+type acceleratorDeviceType struct{}
+
+func (t *acceleratorDeviceType) Name() string {
+	return fakecrd.AcceleratorDeviceType
+}
+
+func (t *acceleratorDeviceType) Enumerate(ctx context.Context, seed string) ([]*DeviceInfo, error) {
+	return enumerateDevices(ctx, t.Name(), t.Name()+"/"+seed, acceleratorDevicePrefix, perNodeAcceleratorDevices, fakecrd.DeviceTypeModels(t.Name())), nil
+}
+
+func (t *acceleratorDeviceType) Attributes(device *DeviceInfo) []resourceapi.NamedResourcesAttribute {
+	var attributes []resourceapi.NamedResourcesAttribute
+	if memory, ok := fakecrd.AcceleratorMemory(device.model); ok {
+		attributes = append(attributes, resourceapi.NamedResourcesAttribute{
+			Name: "memory",
+			NamedResourcesAttributeValue: resourceapi.NamedResourcesAttributeValue{
+				QuantityValue: &memory,
+			},
+		})
+	}
+	if maxPartitions, ok := fakecrd.MaxSplit(device.model); ok {
+		attributes = append(attributes, intAttribute("maxPartitions", int64(maxPartitions)))
+	}
+	return attributes
+}
+
+func (t *acceleratorDeviceType) Prepare(ctx context.Context, device *DeviceInfo, split int) ([]*DeviceInfo, error) {
+	if split > 0 {
+		klog.FromContext(ctx).Info("Partitioning accelerator memory", "parentUID", device.uuid, "partitions", split)
+		return enumerateSplittedDevices(ctx, device, split), nil
+	}
+	return []*DeviceInfo{device}, nil
+}
+
+func (t *acceleratorDeviceType) Unprepare(ctx context.Context, devices []*DeviceInfo) error {
+	return nil
+}
+
+func (t *acceleratorDeviceType) ContainerEdits(device *DeviceInfo, index int) cdispec.ContainerEdits {
+	env := []string{
+		fmt.Sprintf("FAKE_ACCELERATOR_%d=%s", index, device.uuid),
+		fmt.Sprintf("FAKE_ACCELERATOR_MODEL=%s", device.model),
+	}
+	if device.parent != "" {
+		env = append(env,
+			fmt.Sprintf("FAKE_ACCELERATOR_%d_PARENT=%s", index, device.parent),
+			fmt.Sprintf("FAKE_ACCELERATOR_%d_PARTITION=%d", index, device.index),
+			fmt.Sprintf("FAKE_ACCELERATOR_%d_PARTITIONS=%d", index, device.partitions),
+		)
+	}
+	if memory, ok := fakecrd.AcceleratorMemory(device.model); ok {
+		if device.partitions > 1 {
+			memory = *resource.NewQuantity(memory.Value()/int64(device.partitions), resource.BinarySI)
+		}
+		env = append(env, fmt.Sprintf("FAKE_ACCELERATOR_%d_MEMORY=%s", index, memory.String()))
+	}
+	return cdispec.ContainerEdits{Env: env}
+}
@@ -0,0 +1,103 @@
+package kubeletplugin
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/klog/v2"
+
+	"github.com/toVersus/fake-dra-driver/pkg/fakedevice"
+)
+
+// PreparedAdmin is an admin access claim, which sees the devices of the node
+// matching Models, or all of them if empty, without allocating any
+type PreparedAdmin struct {
+	Models []string
+}
+
+// PrepareAdmin prepares an admin access claim, which reserves nothing and
+// exposes the inventory of the devices to the containers read-only.
+func (s *DeviceState) PrepareAdmin(ctx context.Context, claimUID string, models []string) ([]string, error) {
+	logger := klog.FromContext(ctx).WithValues(
+		"resourceClaimUID", claimUID,
+	)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(4).Info("Prepare admin access CDI spec file for claim")
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.prepared[claimUID] != nil {
+		logger.V(2).Info("Returning already prepared devices for claim")
+		return s.cdi.GetClaimDevices(claimUID, s.prepared[claimUID]), nil
+	}
+
+	prepared := &PreparedDevices{
+		Admin: &PreparedAdmin{Models: models},
+	}
+
+	logger.V(4).Info("Writing device inventory for claim")
+	if err := s.cdi.WriteAdminInventory(claimUID, s.adminInventory(prepared.Admin)); err != nil {
+		return nil, fmt.Errorf("unable to write device inventory for claim: %w", err)
+	}
+
+	logger.V(4).Info("Creating CDI spec file for claim")
+	if err := s.cdi.CreateClaimSpecFile(ctx, claimUID, prepared); err != nil {
+		return nil, fmt.Errorf("unable to create CDI spec file for claim: %w", err)
+	}
+	s.prepared[claimUID] = prepared
+	return s.cdi.GetClaimDevices(claimUID, s.prepared[claimUID]), nil
+}
+
+// refreshAdminInventories rewrites the inventory of every admin access claim
+// after the prepared claims changed. Errors are only logged since they must
+// not fail the claim being prepared or unprepared.
+func (s *DeviceState) refreshAdminInventories(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	for claimUID, prepared := range s.prepared {
+		if prepared.Admin == nil {
+			continue
+		}
+		if err := s.cdi.WriteAdminInventory(claimUID, s.adminInventory(prepared.Admin)); err != nil {
+			logger.Error(err, "Error refreshing device inventory of admin access claim", "resourceClaimUID", claimUID)
+		}
+	}
+}
+
+// adminInventory lists the devices visible to an admin access claim together
+// with the claims they are prepared for.
+func (s *DeviceState) adminInventory(admin *PreparedAdmin) *fakedevice.Inventory {
+	claims := map[string][]string{}
+	for claimUID, prepared := range s.prepared {
+		for _, device := range prepared.Devices {
+			uuid := device.uuid
+			if device.parent != "" {
+				uuid = device.parent
+			}
+			if !slices.Contains(claims[uuid], claimUID) {
+				claims[uuid] = append(claims[uuid], claimUID)
+			}
+		}
+	}
+
+	inventory := &fakedevice.Inventory{Devices: []fakedevice.InventoryDevice{}}
+	for uuid, device := range s.allocatable {
+		if len(admin.Models) > 0 && !slices.Contains(admin.Models, device.model) {
+			continue
+		}
+		slices.Sort(claims[uuid])
+		inventory.Devices = append(inventory.Devices, fakedevice.InventoryDevice{
+			UUID:    uuid,
+			Type:    device.deviceType,
+			Model:   device.model,
+			Healthy: !device.unhealthy,
+			Claims:  claims[uuid],
+		})
+	}
+	slices.SortFunc(inventory.Devices, func(a, b fakedevice.InventoryDevice) int {
+		return cmp.Compare(a.UUID, b.UUID)
+	})
+	return inventory
+}
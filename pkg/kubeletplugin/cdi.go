@@ -0,0 +1,394 @@
+package kubeletplugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cdiapi "github.com/container-orchestrated-devices/container-device-interface/pkg/cdi"
+	cdispec "github.com/container-orchestrated-devices/container-device-interface/specs-go"
+	"k8s.io/klog/v2"
+
+	"github.com/toVersus/fake-dra-driver/pkg/fakedevice"
+)
+
+const (
+	cdiCommonDeviceName = "common"
+
+	// claimConfigFileName is the name of the descriptor file of a claim
+	claimConfigFileName = fakedevice.ClaimConfigFileName
+	// claimConfigContainerRoot is where the descriptor files of claims are
+	// mounted in containers
+	claimConfigContainerRoot = fakedevice.ClaimConfigRoot
+
+	// adminInventoryFileName is the name of the inventory file of an admin
+	// access claim. The directory holding it is mounted rather than the file
+	// so that containers see it being replaced.
+	adminInventoryFileName = fakedevice.InventoryFileName
+)
+
+type CDIHandler struct {
+	cache      *cdiapi.Cache
+	configRoot string
+	driverName string
+	nodeName   string
+	vendor     string
+	class      string
+	// usageDirs mounts a writable directory in the containers of claims for
+	// their workloads to report the usage of the devices in
+	usageDirs bool
+}
+
+// NewCDIHandler creates the handler of the CDI spec files of the driver
+func NewCDIHandler(ctx context.Context, opts Options) (*CDIHandler, error) {
+	logger := klog.FromContext(ctx)
+	if err := opts.complete(); err != nil {
+		return nil, err
+	}
+	// Each handler has its own cache rather than the global CDI registry, so
+	// that several plugins running in the same process keep their spec
+	// files apart
+	logger.V(4).Info("Creating CDI cache", "dir", opts.CDIRoot)
+	cache, err := cdiapi.NewCache(
+		cdiapi.WithSpecDirs(opts.CDIRoot),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the CDI cache: %w", err)
+	}
+
+	handler := &CDIHandler{
+		cache:      cache,
+		configRoot: opts.ClaimConfigRoot,
+		driverName: opts.DriverName,
+		nodeName:   opts.NodeName,
+		vendor:     opts.CDIVendor,
+		class:      opts.CDIClass,
+		usageDirs:  opts.telemetryEnabled(),
+	}
+
+	logger.V(4).Info("Created new CDI handler")
+	return handler, nil
+}
+
+// kind returns the kind of the CDI devices of the driver
+func (cdi *CDIHandler) kind() string {
+	return cdi.vendor + "/" + cdi.class
+}
+
+func (cdi *CDIHandler) GetDevice(ctx context.Context, device string) *cdiapi.Device {
+	logger := klog.FromContext(ctx).WithValues("device", device)
+	logger.V(4).Info("Getting CDI device")
+	return cdi.cache.GetDevice(device)
+}
+
+func (cdi *CDIHandler) CreateCommonSpecFile(ctx context.Context) error {
+	logger := klog.FromContext(ctx).WithValues(
+		"cdiDevice", cdiCommonDeviceName,
+	)
+	spec := &cdispec.Spec{
+		Kind: cdi.kind(),
+		Devices: []cdispec.Device{
+			{
+				Name: cdiCommonDeviceName,
+				ContainerEdits: cdispec.ContainerEdits{
+					Env: []string{
+						fmt.Sprintf("%s=%s", fakedevice.EnvNodeName, cdi.nodeName),
+						fmt.Sprintf("%s=%s", fakedevice.EnvDriverName, cdi.driverName),
+					},
+				},
+			},
+		},
+	}
+	logger.V(4).Info("Creating common CDI spec file",
+		"env", klog.KObjSlice(spec.Devices[0].ContainerEdits.Env))
+
+	minVersion, err := cdiapi.MinimumRequiredVersion(spec)
+	if err != nil {
+		return fmt.Errorf("failed to get minimum required common CDI spec version: %w", err)
+	}
+	logger.V(4).Info("Minimum required common CDI spec version", "cdiSpecVersion", minVersion)
+	spec.Version = minVersion
+
+	specName, err := cdiapi.GenerateNameForTransientSpec(spec, cdiCommonDeviceName)
+	if err != nil {
+		return fmt.Errorf("failed to generate Spec name for common CDI: %w", err)
+	}
+	logger.V(4).Info("Writing common CDI spec file", "cdiSpecName", specName)
+	return cdi.cache.WriteSpec(spec, specName)
+}
+
+func (cdi *CDIHandler) CreateClaimSpecFile(ctx context.Context, claimUID string, devices *PreparedDevices) error {
+	specName := cdiapi.GenerateTransientSpecName(cdi.vendor, cdi.class, claimUID)
+	logger := klog.FromContext(ctx).WithValues(
+		"cdiSpecName", specName,
+	)
+	spec := &cdispec.Spec{
+		Kind:    cdi.kind(),
+		Devices: []cdispec.Device{},
+	}
+
+	// Devices are numbered per type in the environment of the containers
+	indexes := map[string]int{}
+	for _, device := range devices.Devices {
+		deviceType, err := lookupDeviceType(device.deviceType)
+		if err != nil {
+			return err
+		}
+		cdiDevice := cdispec.Device{
+			Name:           device.uuid,
+			ContainerEdits: deviceType.ContainerEdits(device, indexes[device.deviceType]),
+		}
+		logger.V(4).Info("Creating claimed CDI spec file",
+			"cdiDeviceName", cdiDevice.Name, "deviceType", device.deviceType, "env", strings.Join(cdiDevice.ContainerEdits.Env, ","))
+		spec.Devices = append(spec.Devices, cdiDevice)
+		indexes[device.deviceType]++
+	}
+
+	if devices.Config != nil {
+		cdiDevice, err := cdi.createClaimConfig(ctx, claimUID, devices)
+		if err != nil {
+			return fmt.Errorf("failed to create device config for claim: %w", err)
+		}
+		spec.Devices = append(spec.Devices, *cdiDevice)
+	}
+	if devices.Admin != nil {
+		spec.Devices = append(spec.Devices, cdi.adminDevice(ctx, claimUID))
+	}
+	if cdi.hasUsageDir(devices) {
+		edits, err := cdi.createUsageDir(ctx, claimUID)
+		if err != nil {
+			return fmt.Errorf("failed to create usage directory for claim: %w", err)
+		}
+		// The config and the usage directory both apply to all devices of
+		// the claim, so they share the CDI device named after the claim
+		claimDevice := claimCDIDevice(spec, claimUID)
+		claimDevice.ContainerEdits.Env = append(claimDevice.ContainerEdits.Env, edits.Env...)
+		claimDevice.ContainerEdits.Mounts = append(claimDevice.ContainerEdits.Mounts, edits.Mounts...)
+	}
+
+	minVersion, err := cdiapi.MinimumRequiredVersion(spec)
+	if err != nil {
+		return fmt.Errorf("failed to get minimum required CDI spec version: %w", err)
+	}
+	logger.V(4).Info("Minimum required claimed CDI spec version", "cdiSpecVersion", minVersion)
+	spec.Version = minVersion
+
+	logger.V(4).Info("Writing claimed CDI spec file")
+	return cdi.cache.WriteSpec(spec, specName)
+}
+
+// createClaimConfig writes the descriptor file of the device config of a
+// claim and returns the CDI device passing the config to the containers. The
+// device is named after the claim since the config applies to all of its
+// devices.
+func (cdi *CDIHandler) createClaimConfig(ctx context.Context, claimUID string, devices *PreparedDevices) (*cdispec.Device, error) {
+	logger := klog.FromContext(ctx)
+	config := devices.Config
+
+	descriptor := fakedevice.ClaimConfig{
+		ClaimUID:        claimUID,
+		Mode:            config.Mode,
+		ClockProfile:    config.ClockProfile,
+		PowerLimitWatts: config.PowerLimitWatts,
+		Env:             config.Env,
+	}
+	for _, device := range devices.Devices {
+		descriptor.Devices = append(descriptor.Devices, fakedevice.ClaimConfigDevice{
+			UUID:       device.uuid,
+			Type:       device.deviceType,
+			Model:      device.model,
+			Parent:     device.parent,
+			Partition:  device.index,
+			Partitions: device.partitions,
+		})
+	}
+	raw, err := json.MarshalIndent(&descriptor, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config descriptor: %w", err)
+	}
+
+	dir := filepath.Join(cdi.configRoot, claimUID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+	hostPath := filepath.Join(dir, claimConfigFileName)
+	if err := os.WriteFile(hostPath, raw, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write config descriptor: %w", err)
+	}
+	containerPath := filepath.Join(claimConfigContainerRoot, claimUID, claimConfigFileName)
+
+	env := []string{
+		fmt.Sprintf("%s=%s", fakedevice.EnvMode, config.Mode),
+		fmt.Sprintf("%s=%s", fakedevice.EnvClockProfile, config.ClockProfile),
+		fmt.Sprintf("%s=%s", fakedevice.EnvConfig, containerPath),
+	}
+	if config.PowerLimitWatts != nil {
+		env = append(env, fmt.Sprintf("%s=%d", fakedevice.EnvPowerLimitWatts, *config.PowerLimitWatts))
+	}
+	for _, e := range config.Env {
+		env = append(env, fmt.Sprintf("%s=%s", e.Name, e.Value))
+	}
+
+	cdiDevice := &cdispec.Device{
+		Name: claimUID,
+		ContainerEdits: cdispec.ContainerEdits{
+			Env: env,
+			Mounts: []*cdispec.Mount{
+				{
+					HostPath:      hostPath,
+					ContainerPath: containerPath,
+					Options:       []string{"ro", "nosuid", "nodev", "bind"},
+				},
+			},
+		},
+	}
+	logger.V(4).Info("Creating claim config CDI device",
+		"cdiDeviceName", cdiDevice.Name, "descriptor", hostPath, "env", strings.Join(env, ","))
+	return cdiDevice, nil
+}
+
+// hasUsageDir tells whether the containers of a claim get a usage directory,
+// which is only the case for claims with devices when telemetry is exported
+func (cdi *CDIHandler) hasUsageDir(devices *PreparedDevices) bool {
+	return cdi.usageDirs && len(devices.Devices) > 0
+}
+
+// usageDir returns the path of the usage directory of a claim on the host
+func (cdi *CDIHandler) usageDir(claimUID string) string {
+	return filepath.Join(cdi.configRoot, claimUID, fakedevice.UsageDirName)
+}
+
+// createUsageDir creates the directory the workloads of a claim report the
+// usage of its devices in and returns the container edits mounting it. The
+// directory is writable by any user since the containers may not run as
+// root.
+func (cdi *CDIHandler) createUsageDir(ctx context.Context, claimUID string) (*cdispec.ContainerEdits, error) {
+	logger := klog.FromContext(ctx)
+
+	hostPath := cdi.usageDir(claimUID)
+	if err := os.MkdirAll(hostPath, 0755); err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(hostPath, 0777); err != nil {
+		return nil, err
+	}
+	containerPath := filepath.Join(claimConfigContainerRoot, claimUID, fakedevice.UsageDirName)
+
+	edits := &cdispec.ContainerEdits{
+		Env: []string{
+			fmt.Sprintf("%s=%s", fakedevice.EnvUsageDir, containerPath),
+		},
+		Mounts: []*cdispec.Mount{
+			{
+				HostPath:      hostPath,
+				ContainerPath: containerPath,
+				Options:       []string{"rw", "nosuid", "nodev", "bind"},
+			},
+		},
+	}
+	logger.V(4).Info("Creating usage directory", "dir", hostPath)
+	return edits, nil
+}
+
+// claimCDIDevice returns the CDI device named after the claim in the spec,
+// which is added if missing
+func claimCDIDevice(spec *cdispec.Spec, claimUID string) *cdispec.Device {
+	for i := range spec.Devices {
+		if spec.Devices[i].Name == claimUID {
+			return &spec.Devices[i]
+		}
+	}
+	spec.Devices = append(spec.Devices, cdispec.Device{Name: claimUID})
+	return &spec.Devices[len(spec.Devices)-1]
+}
+
+// adminDevice returns the CDI device of an admin access claim, which exposes
+// the inventory of the node instead of device nodes.
+func (cdi *CDIHandler) adminDevice(ctx context.Context, claimUID string) cdispec.Device {
+	logger := klog.FromContext(ctx)
+
+	containerDir := filepath.Join(claimConfigContainerRoot, claimUID)
+	cdiDevice := cdispec.Device{
+		Name: claimUID,
+		ContainerEdits: cdispec.ContainerEdits{
+			Env: []string{
+				fmt.Sprintf("%s=true", fakedevice.EnvAdminAccess),
+				fmt.Sprintf("%s=%s", fakedevice.EnvInventory, filepath.Join(containerDir, adminInventoryFileName)),
+			},
+			Mounts: []*cdispec.Mount{
+				{
+					HostPath:      filepath.Join(cdi.configRoot, claimUID),
+					ContainerPath: containerDir,
+					Options:       []string{"ro", "nosuid", "nodev", "bind"},
+				},
+			},
+		},
+	}
+	logger.V(4).Info("Creating admin access CDI device",
+		"cdiDeviceName", cdiDevice.Name, "env", strings.Join(cdiDevice.ContainerEdits.Env, ","))
+	return cdiDevice
+}
+
+// WriteAdminInventory replaces the inventory file of an admin access claim.
+// The file is renamed into place so that readers never see a partial file.
+func (cdi *CDIHandler) WriteAdminInventory(claimUID string, inventory *fakedevice.Inventory) error {
+	raw, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal device inventory: %w", err)
+	}
+
+	dir := filepath.Join(cdi.configRoot, claimUID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create inventory directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, adminInventoryFileName+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create device inventory: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write device inventory: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write device inventory: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write device inventory: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, adminInventoryFileName)); err != nil {
+		return fmt.Errorf("failed to replace device inventory: %w", err)
+	}
+	return nil
+}
+
+func (cdi *CDIHandler) DeleteClaimSpecFile(claimUID string) error {
+	specName := cdiapi.GenerateTransientSpecName(cdi.vendor, cdi.class, claimUID)
+	if err := cdi.cache.RemoveSpec(specName); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(filepath.Join(cdi.configRoot, claimUID)); err != nil {
+		return fmt.Errorf("failed to remove config directory: %w", err)
+	}
+	return nil
+}
+
+func (cdi *CDIHandler) GetClaimDevices(claimUID string, devices *PreparedDevices) []string {
+	cdiDevices := []string{
+		cdiapi.QualifiedName(cdi.vendor, cdi.class, cdiCommonDeviceName),
+	}
+
+	for _, device := range devices.Devices {
+		cdiDevice := cdiapi.QualifiedName(cdi.vendor, cdi.class, device.uuid)
+		cdiDevices = append(cdiDevices, cdiDevice)
+	}
+	if devices.Config != nil || devices.Admin != nil || cdi.hasUsageDir(devices) {
+		cdiDevices = append(cdiDevices, cdiapi.QualifiedName(cdi.vendor, cdi.class, claimUID))
+	}
+
+	return cdiDevices
+}
@@ -0,0 +1,101 @@
+package kubeletplugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	cdispec "github.com/container-orchestrated-devices/container-device-interface/specs-go"
+	resourceapi "k8s.io/api/resource/v1alpha2"
+)
+
+// DeviceInfo describes a device emulated on the node, or a partition of one
+type DeviceInfo struct {
+	uuid       string
+	deviceType string
+	model      string
+	// parent is the UUID of the device a partition was split from
+	parent string
+	// index is the position of a partition within its parent
+	index int
+	// partitions is the number of partitions the parent was split into
+	partitions int
+}
+
+// DeviceType emulates one kind of device. Each type is responsible for its
+// devices from enumeration to the CDI edits of the containers using them,
+// the DeviceState only keeps track of which devices are prepared for which
+// claim.
+type DeviceType interface {
+	// Name is the type of the devices, as selected by the Type of a
+	// DeviceSelector in DeviceClassParameters
+	Name() string
+	// Enumerate returns the devices of the type emulated on the node. The
+	// seed identifies the inventory of the node, the same devices are
+	// enumerated for the same seed.
+	Enumerate(ctx context.Context, seed string) ([]*DeviceInfo, error)
+	// Attributes returns the attributes of a device published in the
+	// resource model on top of its uuid, type and model
+	Attributes(device *DeviceInfo) []resourceapi.NamedResourcesAttribute
+	// Prepare returns the devices handed to the containers for an allocated
+	// device split into the given number of partitions, 0 for whole devices
+	Prepare(ctx context.Context, device *DeviceInfo, split int) ([]*DeviceInfo, error)
+	// Unprepare releases the devices returned by Prepare
+	Unprepare(ctx context.Context, devices []*DeviceInfo) error
+	// ContainerEdits returns the CDI edits giving containers access to a
+	// prepared device, index being its position among the devices of the
+	// type prepared for the claim
+	ContainerEdits(device *DeviceInfo, index int) cdispec.ContainerEdits
+}
+
+// deviceTypes holds the built-in device types by name
+var deviceTypes = map[string]DeviceType{}
+
+// registerDeviceType adds a built-in device type, it must be called from
+// init functions only
+func registerDeviceType(deviceType DeviceType) {
+	if _, exists := deviceTypes[deviceType.Name()]; exists {
+		panic(fmt.Sprintf("device type %s registered twice", deviceType.Name()))
+	}
+	deviceTypes[deviceType.Name()] = deviceType
+}
+
+// lookupDeviceType returns the named device type
+func lookupDeviceType(name string) (DeviceType, error) {
+	deviceType, ok := deviceTypes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown device type %q, must be one of %v", name, RegisteredDeviceTypes())
+	}
+	return deviceType, nil
+}
+
+// RegisteredDeviceTypes returns the names of the built-in device types, which
+// may be passed in the DeviceTypes of Options
+func RegisteredDeviceTypes() []string {
+	names := make([]string, 0, len(deviceTypes))
+	for name := range deviceTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// stringAttribute returns a string attribute of the resource model
+func stringAttribute(name, value string) resourceapi.NamedResourcesAttribute {
+	return resourceapi.NamedResourcesAttribute{
+		Name: name,
+		NamedResourcesAttributeValue: resourceapi.NamedResourcesAttributeValue{
+			StringValue: &value,
+		},
+	}
+}
+
+// intAttribute returns an int attribute of the resource model
+func intAttribute(name string, value int64) resourceapi.NamedResourcesAttribute {
+	return resourceapi.NamedResourcesAttribute{
+		Name: name,
+		NamedResourcesAttributeValue: resourceapi.NamedResourcesAttributeValue{
+			IntValue: &value,
+		},
+	}
+}
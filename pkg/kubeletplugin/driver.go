@@ -0,0 +1,372 @@
+package kubeletplugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	coreclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	drapbv1 "k8s.io/kubelet/pkg/apis/dra/v1alpha3"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	"github.com/toVersus/fake-dra-driver/pkg/vendorparameters"
+)
+
+// Reasons of the Events recorded by the driver
+const (
+	reasonPrepareFailed         = "PrepareFailed"
+	reasonDeviceUnhealthy       = "DeviceUnhealthy"
+	reasonSplitCapacityExceeded = "SplitCapacityExceeded"
+	reasonUnsupportedConfig     = "UnsupportedConfig"
+)
+
+// reservedPodsTimeout bounds the lookup of the Pods a claim is reserved for
+const reservedPodsTimeout = 30 * time.Second
+
+var _ drapbv1.NodeServer = &Driver{}
+
+// Driver implements the gRPC API kubelet calls to prepare the devices
+// allocated to claims on the node
+type Driver struct {
+	sync.Mutex
+	doneCh chan struct{}
+
+	state      *DeviceState
+	coreclient coreclientset.Interface
+	recorder   record.EventRecorder
+	// adminAccessNamespaces are the namespaces admin access claims may be
+	// prepared in
+	adminAccessNamespaces sets.Set[string]
+	// telemetry is nil unless the telemetry of the devices is exported
+	telemetry *telemetry
+}
+
+// NewDriver emulates the devices of the node and creates the CDI spec file
+// common to all claims. Serving the driver is left to the caller, see Start.
+func NewDriver(ctx context.Context, opts Options) (*Driver, error) {
+	logger := klog.FromContext(ctx)
+	if err := opts.complete(); err != nil {
+		return nil, err
+	}
+
+	logger.V(4).Info("Generating mock Fake devices")
+	state, err := NewDeviceState(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	driver := &Driver{
+		doneCh:                make(chan struct{}),
+		state:                 state,
+		coreclient:            opts.CoreClient,
+		recorder:              opts.Recorder,
+		adminAccessNamespaces: sets.New(opts.AdminAccessNamespaces...),
+	}
+	if opts.telemetryEnabled() {
+		driver.telemetry = newTelemetry(state, opts)
+	}
+	return driver, nil
+}
+
+func (d *Driver) Shutdown(ctx context.Context) error {
+	logger := klog.FromContext(ctx)
+	logger.V(2).Info("Updating status of NodeAllocationState to NotReady before shutting down fake-dra-driver")
+	defer close(d.doneCh)
+
+	return nil
+}
+
+func (d *Driver) NodeListAndWatchResources(req *drapbv1.NodeListAndWatchResourcesRequest, stream drapbv1.Node_NodeListAndWatchResourcesServer) error {
+	resourceModel := d.state.getResourceModelFromAllocatableDevices()
+	resp := &drapbv1.NodeListAndWatchResourcesResponse{
+		Resources: []*resourceapi.ResourceModel{&resourceModel},
+	}
+
+	if err := stream.Send(resp); err != nil {
+		return err
+	}
+
+	// Keep the stream open until the driver is shutdown or the client
+	// disconnects
+	select {
+	case <-d.doneCh:
+	case <-stream.Context().Done():
+	}
+
+	return nil
+}
+
+func (d *Driver) NodePrepareResources(ctx context.Context, req *drapbv1.NodePrepareResourcesRequest) (*drapbv1.NodePrepareResourcesResponse, error) {
+	logger := klog.FromContext(ctx)
+	logger.V(4).Info("NodePrepareResource is called", "numClaims", len(req.Claims))
+
+	preparedResources := &drapbv1.NodePrepareResourcesResponse{Claims: map[string]*drapbv1.NodePrepareResourceResponse{}}
+
+	// In production version some common operations of d.nodeUnprepareResources
+	// should be done outside of the loop, for instance updating the CR could
+	// be done once after all HW was prepared.
+	for _, claim := range req.Claims {
+		prepared := d.nodePrepareResource(ctx, claim)
+		klog.V(4).Info("Prepared devices for allocated claims", "devices", klog.Format(prepared))
+		preparedResources.Claims[claim.Uid] = prepared
+	}
+
+	return preparedResources, nil
+}
+
+func (d *Driver) nodePrepareResource(ctx context.Context, claim *drapbv1.Claim) *drapbv1.NodePrepareResourceResponse {
+	logger := klog.FromContext(ctx)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(4).Info("NodePrepareResource is called")
+	d.Lock()
+	defer d.Unlock()
+
+	logger.V(4).Info("Getting NodeAllocationState and hold it onto driver struct for preparing resource")
+	isPrepared, prepared, err := d.isPrepared(ctx, claim.Uid)
+	if err != nil {
+		return &drapbv1.NodePrepareResourceResponse{
+			Error: fmt.Sprintf("error checking if claim is already prepared: %v", err),
+		}
+	}
+	if isPrepared {
+		logger.Info("Returning cached devices for claim", "claimUID", claim.Uid, "prepared", prepared)
+		// The claim may be reserved for another Pod since it was prepared
+		d.labelTelemetry(ctx, claim)
+		return &drapbv1.NodePrepareResourceResponse{CDIDevices: prepared}
+	}
+
+	if len(claim.StructuredResourceHandle) == 0 {
+		return &drapbv1.NodePrepareResourceResponse{
+			Error: "No StructuredResourceHandle found in claim, please enable StructuredParameters in ResourceClass",
+		}
+	}
+
+	logger.V(4).Info("[Structured Parameters] Preparing devices for claim")
+	devices, params, err := d.prepareDevices(ctx, claim)
+	if err != nil {
+		d.recordPrepareFailure(ctx, claim, err)
+		return &drapbv1.NodePrepareResourceResponse{
+			Error: fmt.Sprintf("error allocating devices for claim %v: %s", claim.Uid, err),
+		}
+	}
+
+	switch {
+	case params.AdminAccess && !d.adminAccessNamespaces.Has(claim.Namespace):
+		// The controller only generates admin access parameters in these
+		// namespaces, but they may also be written by hand
+		err = fmt.Errorf("admin access is not allowed in namespace %s", claim.Namespace)
+	case params.AdminAccess:
+		logger.V(4).Info("Preparing admin access to all devices for claim")
+		prepared, err = d.state.PrepareAdmin(ctx, claim.Uid, params.Selector.Models)
+	default:
+		logger.V(4).Info("Preparing devices for claim")
+		prepared, err = d.state.Prepare(ctx, claim.Uid, devices, params.Config)
+	}
+	if err != nil {
+		d.recordPrepareFailure(ctx, claim, err)
+		return &drapbv1.NodePrepareResourceResponse{
+			Error: fmt.Sprintf("error preparing devices for claim %v: %s", claim.Uid, err),
+		}
+	}
+
+	d.labelTelemetry(ctx, claim)
+	logger.V(4).Info("Prepared devices for allocated claims", "devices", klog.Format(prepared))
+	return &drapbv1.NodePrepareResourceResponse{CDIDevices: prepared}
+}
+
+// labelTelemetry labels the telemetry of the devices of a prepared claim
+// with the claim and the Pods it is reserved for. The Pods are looked up in
+// the background, so that neither kubelet nor other claims wait on the API
+// server, and the previous ones are kept if that fails.
+func (d *Driver) labelTelemetry(ctx context.Context, claim *drapbv1.Claim) {
+	if d.telemetry == nil {
+		return
+	}
+	d.telemetry.setClaim(claim.Uid, claim.Namespace, claim.Name)
+	if d.coreclient == nil {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, reservedPodsTimeout)
+		defer cancel()
+		consumers, err := d.reservedPods(ctx, claim)
+		if err != nil {
+			klog.FromContext(ctx).Error(err, "Error getting ResourceClaim to label the telemetry of its devices", "resourceClaim", klog.KRef(claim.Namespace, claim.Name))
+			return
+		}
+		var pods []string
+		for _, consumer := range consumers {
+			pods = append(pods, consumer.Name)
+		}
+		d.telemetry.setPods(claim.Uid, pods)
+	}()
+}
+
+// reservedPods returns the Pods a claim is reserved for. Without a client,
+// as in standalone mode, the Pods cannot be looked up and none is returned.
+func (d *Driver) reservedPods(ctx context.Context, claim *drapbv1.Claim) ([]resourceapi.ResourceClaimConsumerReference, error) {
+	if d.coreclient == nil {
+		return nil, nil
+	}
+	resourceClaim, err := d.coreclient.ResourceV1alpha2().ResourceClaims(claim.Namespace).Get(ctx, claim.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var pods []resourceapi.ResourceClaimConsumerReference
+	for _, consumer := range resourceClaim.Status.ReservedFor {
+		if consumer.APIGroup != "" || consumer.Resource != "pods" {
+			continue
+		}
+		pods = append(pods, consumer)
+	}
+	return pods, nil
+}
+
+// recordPrepareFailure records a warning Event on the claim and on the Pods
+// it is reserved for, which is where users look when their Pod does not start.
+func (d *Driver) recordPrepareFailure(ctx context.Context, claim *drapbv1.Claim, err error) {
+	logger := klog.FromContext(ctx)
+
+	reason := reasonPrepareFailed
+	switch {
+	case errors.Is(err, errDeviceUnhealthy):
+		reason = reasonDeviceUnhealthy
+	case errors.Is(err, errSplitCapacityExceeded):
+		reason = reasonSplitCapacityExceeded
+	case errors.Is(err, errUnsupportedConfig):
+		reason = reasonUnsupportedConfig
+	}
+
+	claimRef := &corev1.ObjectReference{
+		APIVersion: resourceapi.SchemeGroupVersion.String(),
+		Kind:       "ResourceClaim",
+		Namespace:  claim.Namespace,
+		Name:       claim.Name,
+		UID:        types.UID(claim.Uid),
+	}
+	d.recorder.Eventf(claimRef, corev1.EventTypeWarning, reason, "Error preparing devices: %v", err)
+
+	consumers, getErr := d.reservedPods(ctx, claim)
+	if getErr != nil {
+		logger.Error(getErr, "Error getting ResourceClaim to record Events on its Pods", "resourceClaim", klog.KRef(claim.Namespace, claim.Name))
+		return
+	}
+	for _, consumer := range consumers {
+		podRef := &corev1.ObjectReference{
+			APIVersion: corev1.SchemeGroupVersion.String(),
+			Kind:       "Pod",
+			Namespace:  claim.Namespace,
+			Name:       consumer.Name,
+			UID:        consumer.UID,
+		}
+		d.recorder.Eventf(podRef, corev1.EventTypeWarning, reason, "Error preparing devices of ResourceClaim %s: %v", claim.Name, err)
+	}
+}
+
+func (d *Driver) isPrepared(ctx context.Context, claimUID string) (bool, []string, error) {
+	logger := klog.FromContext(ctx)
+
+	if prepared, exists := d.state.prepared[claimUID]; exists {
+		claimedDevices := d.state.cdi.GetClaimDevices(claimUID, prepared)
+		logger.V(4).Info("Claimed devices for claim", "claimUID", claimUID, "claimedDevices", claimedDevices)
+		return true, claimedDevices, nil
+	}
+	logger.Info("Claim is not prepared", "claimUID", claimUID)
+	return false, nil, nil
+}
+
+// prepareDevices returns the devices allocated to the claim together with the
+// defaulted claim parameters they were allocated for.
+func (d *Driver) prepareDevices(ctx context.Context, claim *drapbv1.Claim) ([]AllocatedDevice, *fakecrd.FakeClaimParametersSpec, error) {
+	logger := klog.FromContext(ctx)
+
+	handle := claim.StructuredResourceHandle[0]
+	logger.V(4).Info("Getting vendor claim parameters", "claim", claim.Name)
+	logger.V(2).Info("Unmarshalling vendor claim parameters", "raw", string(handle.VendorClaimParameters.Raw))
+	fakeClaimParams, allocated, err := vendorparameters.DecodeAllocation(handle)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.V(4).Info("Allocating devices for claim", "claim", claim.Name)
+	preparedDevices := make([]AllocatedDevice, len(allocated))
+	for idx, device := range allocated {
+		logger.V(4).Info("Allocate named resource", "name", device.Name, "request", device.Request.Name, "split", device.Request.Split)
+		preparedDevices[idx] = AllocatedDevice{
+			name:    device.Name,
+			request: device.Request.Name,
+			split:   device.Request.Split,
+		}
+	}
+
+	return preparedDevices, fakeClaimParams, nil
+}
+
+func (d *Driver) NodeUnprepareResources(ctx context.Context, req *drapbv1.NodeUnprepareResourcesRequest) (*drapbv1.NodeUnprepareResourcesResponse, error) {
+	logger := klog.FromContext(ctx)
+	logger.Info("NodeUnPrepareResource is called", "nclaims", len(req.Claims))
+	unpreparedResources := &drapbv1.NodeUnprepareResourcesResponse{Claims: map[string]*drapbv1.NodeUnprepareResourceResponse{}}
+
+	for _, claim := range req.Claims {
+		unpreparedResources.Claims[claim.Uid] = d.nodeUnprepareResource(ctx, claim)
+	}
+
+	return unpreparedResources, nil
+}
+
+func (d *Driver) nodeUnprepareResource(ctx context.Context, claim *drapbv1.Claim) *drapbv1.NodeUnprepareResourceResponse {
+	d.Lock()
+	defer d.Unlock()
+
+	logger := klog.FromContext(ctx)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(4).Info("NodeUnprepareResource is called")
+
+	isUnprepared, err := d.isUnprepared(ctx, claim.Uid)
+	if err != nil {
+		return &drapbv1.NodeUnprepareResourceResponse{
+			Error: fmt.Sprintf("error checking if claim is already unprepared: %v", err),
+		}
+	}
+	if isUnprepared {
+		logger.Info("Already unprepared, nothing to do for claim", "claimUID", claim.Uid)
+		return &drapbv1.NodeUnprepareResourceResponse{}
+	}
+
+	logger.V(4).Info("Unpreparing devices for claim", "claimUID", claim.Uid)
+	err = d.state.Unprepare(ctx, claim.Uid)
+	if err != nil {
+		return &drapbv1.NodeUnprepareResourceResponse{
+			Error: fmt.Sprintf("error unpreparing devices for claim: %s", err),
+		}
+
+	}
+
+	if d.telemetry != nil {
+		d.telemetry.removeClaim(claim.Uid)
+	}
+	logger.V(4).Info("Unprepared devices for unallocated resource claim")
+	return &drapbv1.NodeUnprepareResourceResponse{}
+}
+
+func (d *Driver) isUnprepared(ctx context.Context, claimUID string) (bool, error) {
+	logger := klog.FromContext(ctx)
+
+	if _, exists := d.state.prepared[claimUID]; !exists {
+		logger.V(4).Info("Claim is already unprepared", "claimUID", claimUID)
+		return true, nil
+	}
+	logger.Info("Claim is prepared", "claimUID", claimUID)
+	return false, nil
+}
@@ -0,0 +1,60 @@
+package kubeletplugin
+
+import (
+	"context"
+	"math/rand"
+
+	"k8s.io/klog/v2"
+
+	"github.com/toVersus/fake-dra-driver/pkg/deviceuuid"
+)
+
+// enumerateDevices generates the devices of a type emulated on the node. The
+// UUIDs and the model are derived from the seed so that they are stable
+// across restarts of the plugin.
+func enumerateDevices(ctx context.Context, deviceType, seed, prefix string, count int, models []string) []*DeviceInfo {
+	logger := klog.FromContext(ctx)
+	uuids := deviceuuid.Generate(seed, prefix, count)
+	model := generateModel(seed, models)
+
+	devices := make([]*DeviceInfo, 0, count)
+	for _, uuid := range uuids {
+		logger.V(4).Info("Enumerating devices", "deviceType", deviceType, "deviceUID", uuid)
+		devices = append(devices, &DeviceInfo{
+			uuid:       uuid,
+			deviceType: deviceType,
+			model:      model,
+		})
+	}
+	return devices
+}
+
+// enumerateSplittedDevices generates the partitions of a device, the UUIDs
+// being derived from the UUID of the device
+func enumerateSplittedDevices(ctx context.Context, parent *DeviceInfo, split int) []*DeviceInfo {
+	logger := klog.FromContext(ctx).WithValues("parentUID", parent.uuid)
+	uuids := deviceuuid.Partitions(parent.deviceType, parent.uuid, split)
+
+	splittedDevices := []*DeviceInfo{}
+	for i, uuid := range uuids {
+		deviceInfo := &DeviceInfo{
+			uuid:       uuid,
+			deviceType: parent.deviceType,
+			model:      parent.model,
+			parent:     parent.uuid,
+			index:      i,
+			partitions: split,
+		}
+		logger.V(4).Info("Enumerating split devices", "deviceUID", uuid)
+		splittedDevices = append(splittedDevices, deviceInfo)
+	}
+
+	return splittedDevices
+}
+
+// generateModel randomly selects one of the models, all devices of a type on
+// a node have the same model
+func generateModel(seed string, models []string) string {
+	rand := rand.New(rand.NewSource(deviceuuid.Hash(seed)))
+	return models[rand.Intn(len(models))]
+}
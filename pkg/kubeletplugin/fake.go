@@ -0,0 +1,68 @@
+package kubeletplugin
+
+import (
+	"context"
+	"fmt"
+
+	cdispec "github.com/container-orchestrated-devices/container-device-interface/specs-go"
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	"k8s.io/klog/v2"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	"github.com/toVersus/fake-dra-driver/pkg/deviceuuid"
+)
+
+const (
+	perNodeFakeDevices = 8
+	fakeDevicePrefix   = deviceuuid.FakePrefix
+)
+
+func init() {
+	registerDeviceType(&fakeDeviceType{})
+}
+
+// fakeDeviceType emulates the Fake devices, which are split into partitions
+// with their own UUIDs
+type fakeDeviceType struct{}
+
+func (t *fakeDeviceType) Name() string {
+	return fakecrd.FakeDeviceType
+}
+
+func (t *fakeDeviceType) Enumerate(ctx context.Context, seed string) ([]*DeviceInfo, error) {
+	return enumerateDevices(ctx, t.Name(), seed, fakeDevicePrefix, perNodeFakeDevices, fakecrd.DeviceTypeModels(t.Name())), nil
+}
+
+func (t *fakeDeviceType) Attributes(device *DeviceInfo) []resourceapi.NamedResourcesAttribute {
+	return nil
+}
+
+func (t *fakeDeviceType) Prepare(ctx context.Context, device *DeviceInfo, split int) ([]*DeviceInfo, error) {
+	if split > 0 {
+		klog.FromContext(ctx).Info("Detected split device. Preparing new device", "parentUID", device.uuid, "split", split)
+		return enumerateSplittedDevices(ctx, device, split), nil
+	}
+	return []*DeviceInfo{device}, nil
+}
+
+func (t *fakeDeviceType) Unprepare(ctx context.Context, devices []*DeviceInfo) error {
+	return nil
+}
+
+func (t *fakeDeviceType) ContainerEdits(device *DeviceInfo, index int) cdispec.ContainerEdits {
+	env := []string{
+		fmt.Sprintf("FAKE_DEVICE_%d=%s", index, device.uuid),
+		// Node 内でモデルは同じため、分割された Fake デバイスが複数あったとしても同じモデルを使うことになる
+		// モデル名の環境変数のキーも値も同じため環境変数は 1 つ設定すれば十分だが、
+		// モデル名の環境変数をデバイスの数だけ設定して重複排除してもらう
+		fmt.Sprintf("FAKE_DEVICE_MODEL=%s", device.model),
+	}
+	if device.parent != "" {
+		env = append(env,
+			fmt.Sprintf("FAKE_DEVICE_%d_PARENT=%s", index, device.parent),
+			fmt.Sprintf("FAKE_DEVICE_%d_PARTITION=%d", index, device.index),
+			fmt.Sprintf("FAKE_DEVICE_%d_PARTITIONS=%d", index, device.partitions),
+		)
+	}
+	return cdispec.ContainerEdits{Env: env}
+}
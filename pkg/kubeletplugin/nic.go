@@ -0,0 +1,93 @@
+package kubeletplugin
+
+import (
+	"context"
+	"fmt"
+
+	cdispec "github.com/container-orchestrated-devices/container-device-interface/specs-go"
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	"k8s.io/klog/v2"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	"github.com/toVersus/fake-dra-driver/pkg/deviceuuid"
+)
+
+const (
+	perNodeNICDevices = 2
+	nicDevicePrefix   = deviceuuid.NICPrefix
+)
+
+func init() {
+	registerDeviceType(&nicDeviceType{})
+}
+
+// nicDeviceType emulates network interfaces. Like SR-IOV capable NICs, a
+// split NIC is handed out as virtual functions sharing its physical
+// function, each with an index and a share of the link speed.
+type nicDeviceType struct{}
+
+func (t *nicDeviceType) Name() string {
+	return fakecrd.NICDeviceType
+}
+
+func (t *nicDeviceType) Enumerate(ctx context.Context, seed string) ([]*DeviceInfo, error) {
+	return enumerateDevices(ctx, t.Name(), t.Name()+"/"+seed, nicDevicePrefix, perNodeNICDevices, fakecrd.DeviceTypeModels(t.Name())), nil
+}
+
+func (t *nicDeviceType) Attributes(device *DeviceInfo) []resourceapi.NamedResourcesAttribute {
+	var attributes []resourceapi.NamedResourcesAttribute
+	if speed, ok := fakecrd.NICSpeedGbps(device.model); ok {
+		attributes = append(attributes, intAttribute("speedGbps", speed))
+	}
+	if maxVFs, ok := fakecrd.MaxSplit(device.model); ok {
+		attributes = append(attributes, intAttribute("maxVFs", int64(maxVFs)))
+	}
+	return attributes
+}
+
+func (t *nicDeviceType) Prepare(ctx context.Context, device *DeviceInfo, split int) ([]*DeviceInfo, error) {
+	if split <= 0 {
+		return []*DeviceInfo{device}, nil
+	}
+
+	klog.FromContext(ctx).Info("Creating virtual functions", "parentUID", device.uuid, "vfs", split)
+	vfs := make([]*DeviceInfo, split)
+	for i, uuid := range deviceuuid.Partitions(device.deviceType, device.uuid, split) {
+		vfs[i] = &DeviceInfo{
+			uuid:       uuid,
+			deviceType: device.deviceType,
+			model:      device.model,
+			parent:     device.uuid,
+			index:      i,
+			partitions: split,
+		}
+	}
+	return vfs, nil
+}
+
+func (t *nicDeviceType) Unprepare(ctx context.Context, devices []*DeviceInfo) error {
+	for _, device := range devices {
+		if device.parent != "" {
+			klog.FromContext(ctx).V(4).Info("Releasing virtual function", "deviceUID", device.uuid, "parentUID", device.parent)
+		}
+	}
+	return nil
+}
+
+func (t *nicDeviceType) ContainerEdits(device *DeviceInfo, index int) cdispec.ContainerEdits {
+	env := []string{
+		fmt.Sprintf("FAKE_NIC_%d=%s", index, device.uuid),
+		fmt.Sprintf("FAKE_NIC_MODEL=%s", device.model),
+	}
+	speed, _ := fakecrd.NICSpeedGbps(device.model)
+	if device.parent != "" {
+		env = append(env,
+			fmt.Sprintf("FAKE_NIC_%d_PF=%s", index, device.parent),
+			fmt.Sprintf("FAKE_NIC_%d_VF_INDEX=%d", index, device.index),
+			fmt.Sprintf("FAKE_NIC_%d_VFS=%d", index, device.partitions),
+		)
+		speed /= int64(device.partitions)
+	}
+	env = append(env, fmt.Sprintf("FAKE_NIC_%d_SPEED_GBPS=%d", index, speed))
+	return cdispec.ContainerEdits{Env: env}
+}
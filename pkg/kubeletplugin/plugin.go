@@ -0,0 +1,259 @@
+// Package kubeletplugin implements the kubelet plugin of the fake DRA driver.
+// It emulates the devices of a node, publishes them through kubelet and
+// prepares the devices allocated to claims as CDI devices. Start runs the
+// plugin in process, so that test suites can run the driver next to envtest
+// or a kind cluster without deploying the fake-dra-kubeletplugin binary.
+package kubeletplugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	coreclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	draplugin "k8s.io/dynamic-resource-allocation/kubeletplugin"
+	"k8s.io/klog/v2"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+)
+
+const (
+	DefaultDriverName = fakecrd.GroupName
+	DriverAPIGroup    = fakecrd.GroupName
+
+	// PluginsRegistryPath is where kubelet watches for registration sockets
+	PluginsRegistryPath = "/var/lib/kubelet/plugins_registry"
+	// PluginsPath is where the directories of the plugins are created
+	PluginsPath = "/var/lib/kubelet/plugins"
+	// DefaultCDIRoot is where CDI files are generated by default
+	DefaultCDIRoot = "/etc/cdi"
+	// DefaultCDIClass is the class of the CDI devices by default
+	DefaultCDIClass = "fake"
+	// DefaultTelemetryInterval is the interval the telemetry of the devices
+	// is sampled at by default
+	DefaultTelemetryInterval = 10 * time.Second
+)
+
+// Options configures the kubelet plugin. Every field is optional, unset
+// fields are defaulted the way the flags of fake-dra-kubeletplugin are.
+type Options struct {
+	// DriverName is the name of the driver the plugin registers with kubelet
+	// and publishes resources for, DefaultDriverName if empty
+	DriverName string
+	// NodeName is the name of the node the plugin runs on, which determines
+	// the devices emulated on it. Defaults to the hostname.
+	NodeName string
+
+	// RootDir holds the registration socket, the plugin directory and the
+	// CDI files which are not set, instead of the directories of kubelet.
+	// It lets the plugin run without root privileges.
+	RootDir string
+	// PluginRegistrationPath is the path of the registration socket,
+	// <PluginsRegistryPath>/<DriverName>.sock if empty
+	PluginRegistrationPath string
+	// PluginPath is the directory the socket of the plugin is created in,
+	// <PluginsPath>/<DriverName> if empty
+	PluginPath string
+
+	// CDIRoot is the directory CDI files are generated in, DefaultCDIRoot if
+	// empty
+	CDIRoot string
+	// CDIVendor is the vendor of the CDI devices, k8s.<DriverName> if empty
+	CDIVendor string
+	// CDIClass is the class of the CDI devices, DefaultCDIClass if empty
+	CDIClass string
+	// ClaimConfigRoot is the directory the device config descriptor files of
+	// claims are generated in, <PluginPath>/claims if empty
+	ClaimConfigRoot string
+
+	// DeviceTypes are the types of devices emulated on the node, all
+	// registered types if empty
+	DeviceTypes []string
+	// UnhealthyDevices are the UUIDs of the devices emulated as unhealthy
+	UnhealthyDevices []string
+	// AdminAccessNamespaces are the namespaces admin access claims may be
+	// prepared in, admin access is refused in every namespace if empty. The
+	// plugin checks it on its own since ResourceClaimParameters requesting
+	// admin access can be written by hand.
+	AdminAccessNamespaces []string
+
+	// CoreClient looks up the Pods of claims failing to prepare to record
+	// Events on them. The Pods are skipped if nil.
+	CoreClient coreclientset.Interface
+	// Recorder records the Events of claims failing to prepare. The Events
+	// are dropped if nil.
+	Recorder record.EventRecorder
+
+	// Registerer registers the telemetry of the devices if set. The
+	// containers of claims then get a directory to report the usage of
+	// their devices in.
+	Registerer prometheus.Registerer
+	// TelemetryInterval is the interval the telemetry is sampled at,
+	// DefaultTelemetryInterval if zero
+	TelemetryInterval time.Duration
+}
+
+// complete validates the driver name and defaults the unset options
+func (o *Options) complete() error {
+	if o.DriverName == "" {
+		o.DriverName = DefaultDriverName
+	}
+	if msgs := validation.IsDNS1123Subdomain(o.DriverName); len(msgs) > 0 {
+		return fmt.Errorf("invalid driver name %q: %s", o.DriverName, strings.Join(msgs, ", "))
+	}
+	if o.NodeName == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("error getting hostname for node name: %w", err)
+		}
+		o.NodeName = strings.ToLower(hostname)
+	}
+
+	pluginsRegistryPath, pluginsPath, cdiRoot := PluginsRegistryPath, PluginsPath, DefaultCDIRoot
+	if o.RootDir != "" {
+		pluginsRegistryPath = filepath.Join(o.RootDir, "plugins_registry")
+		pluginsPath = filepath.Join(o.RootDir, "plugins")
+		cdiRoot = filepath.Join(o.RootDir, "cdi")
+	}
+	if o.PluginRegistrationPath == "" {
+		o.PluginRegistrationPath = filepath.Join(pluginsRegistryPath, o.DriverName+".sock")
+	}
+	if o.PluginPath == "" {
+		o.PluginPath = filepath.Join(pluginsPath, o.DriverName)
+	}
+	if o.CDIRoot == "" {
+		o.CDIRoot = cdiRoot
+	}
+	if o.CDIVendor == "" {
+		o.CDIVendor = "k8s." + o.DriverName
+	}
+	if o.CDIClass == "" {
+		o.CDIClass = DefaultCDIClass
+	}
+	if o.ClaimConfigRoot == "" {
+		o.ClaimConfigRoot = filepath.Join(o.PluginPath, "claims")
+	}
+	if len(o.DeviceTypes) == 0 {
+		o.DeviceTypes = RegisteredDeviceTypes()
+	}
+	if o.Recorder == nil {
+		o.Recorder = &record.FakeRecorder{}
+	}
+	if o.TelemetryInterval <= 0 {
+		o.TelemetryInterval = DefaultTelemetryInterval
+	}
+	return nil
+}
+
+// telemetryEnabled tells whether the telemetry of the devices is exported
+func (o *Options) telemetryEnabled() bool {
+	return o.Registerer != nil
+}
+
+// Plugin is a kubelet plugin serving the driver
+type Plugin struct {
+	driver     *Driver
+	plugin     draplugin.DRAPlugin
+	socketPath string
+
+	registerer prometheus.Registerer
+	// stopTelemetry stops sampling the telemetry, nil if not exported
+	stopTelemetry context.CancelFunc
+}
+
+// Start creates the directories of the plugin, emulates the devices of the
+// node and serves them until Stop is called. kubelet picks the plugin up
+// from its registration socket.
+func Start(ctx context.Context, opts Options) (*Plugin, error) {
+	logger := klog.FromContext(ctx)
+	if err := opts.complete(); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Creating plugin directory", "dir", opts.PluginPath)
+	if err := os.MkdirAll(opts.PluginPath, 0750); err != nil {
+		return nil, fmt.Errorf("error creating plugin directory: %w", err)
+	}
+
+	registrationDir := filepath.Dir(opts.PluginRegistrationPath)
+	logger.V(4).Info("Creating plugin registration directory", "dir", registrationDir)
+	if err := os.MkdirAll(registrationDir, 0750); err != nil {
+		return nil, fmt.Errorf("error creating plugin registration directory: %w", err)
+	}
+
+	info, err := os.Stat(opts.CDIRoot)
+	if err != nil && os.IsNotExist(err) {
+		logger.Info("Creating CDI config directory", "dir", opts.CDIRoot)
+		if err := os.MkdirAll(opts.CDIRoot, 0750); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("path to cdi file generation must be a directory: %s", opts.CDIRoot)
+	}
+
+	driver, err := NewDriver(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	socketPath := filepath.Join(opts.PluginPath, "plugin.sock")
+	dp, err := draplugin.Start(
+		driver,
+		draplugin.DriverName(opts.DriverName),
+		draplugin.RegistrarSocketPath(opts.PluginRegistrationPath),
+		draplugin.PluginSocketPath(socketPath),
+		draplugin.KubeletPluginSocketPath(socketPath),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Plugin{
+		driver:     driver,
+		plugin:     dp,
+		socketPath: socketPath,
+		registerer: opts.Registerer,
+	}
+	if driver.telemetry != nil {
+		if err := opts.Registerer.Register(driver.telemetry); err != nil {
+			dp.Stop()
+			return nil, fmt.Errorf("error registering device telemetry: %w", err)
+		}
+		telemetryCtx, cancel := context.WithCancel(ctx)
+		p.stopTelemetry = cancel
+		go driver.telemetry.run(telemetryCtx)
+	}
+
+	logger.Info("Serving kubelet plugin", "socket", socketPath, "cdiRoot", opts.CDIRoot)
+	return p, nil
+}
+
+// SocketPath returns the path of the socket serving the gRPC API of the
+// plugin, which is what fake-dra-client talks to
+func (p *Plugin) SocketPath() string {
+	return p.socketPath
+}
+
+// Stop stops serving the plugin and shuts the driver down
+func (p *Plugin) Stop(ctx context.Context) error {
+	p.plugin.Stop()
+
+	if p.stopTelemetry != nil {
+		p.stopTelemetry()
+		p.registerer.Unregister(p.driver.telemetry)
+	}
+
+	if err := p.driver.Shutdown(ctx); err != nil {
+		return fmt.Errorf("error shutting down driver: %w", err)
+	}
+	return nil
+}
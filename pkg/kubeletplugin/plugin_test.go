@@ -0,0 +1,124 @@
+package kubeletplugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2/ktesting"
+	drapbv1 "k8s.io/kubelet/pkg/apis/dra/v1alpha3"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	"github.com/toVersus/fake-dra-driver/pkg/vendorparameters"
+)
+
+// TestStandalone prepares and unprepares a claim through the socket of a
+// plugin running in standalone mode, the way fake-dra-client does
+func TestStandalone(t *testing.T) {
+	_, ctx := ktesting.NewTestContext(t)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	const nodeName = "test-node"
+	rootDir := t.TempDir()
+	plugin, err := Start(ctx, Options{
+		NodeName:    nodeName,
+		RootDir:     rootDir,
+		DeviceTypes: []string{fakecrd.FakeDeviceType},
+	})
+	if err != nil {
+		t.Fatalf("error starting plugin: %v", err)
+	}
+	defer func() {
+		if err := plugin.Stop(ctx); err != nil {
+			t.Errorf("error stopping plugin: %v", err)
+		}
+	}()
+
+	conn, err := grpc.DialContext(ctx, "unix://"+plugin.SocketPath(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("error connecting to plugin: %v", err)
+	}
+	defer conn.Close()
+	client := drapbv1.NewNodeClient(conn)
+
+	instances := plugin.driver.state.getResourceModelFromAllocatableDevices().NamedResources.Instances
+	if len(instances) == 0 {
+		t.Fatal("no devices emulated on the node")
+	}
+	claim := newClaim(t, "claim-uid", nodeName, instances[0].Name)
+
+	cdiSpecs := filepath.Join(rootDir, "cdi", "*"+claim.Uid+"*")
+	claimConfig := filepath.Join(rootDir, "plugins", DefaultDriverName, "claims", claim.Uid, claimConfigFileName)
+
+	prepared, err := client.NodePrepareResources(ctx, &drapbv1.NodePrepareResourcesRequest{Claims: []*drapbv1.Claim{claim}})
+	if err != nil {
+		t.Fatalf("error preparing claim: %v", err)
+	}
+	result := prepared.Claims[claim.Uid]
+	if result == nil || result.Error != "" {
+		t.Fatalf("claim not prepared: %v", result)
+	}
+	if len(result.CDIDevices) == 0 {
+		t.Error("no CDI devices prepared for claim")
+	}
+	if matches, _ := filepath.Glob(cdiSpecs); len(matches) != 1 {
+		t.Errorf("expected a CDI spec file of the claim, got %v", matches)
+	}
+	if _, err := os.Stat(claimConfig); err != nil {
+		t.Errorf("expected claim config file: %v", err)
+	}
+
+	unprepared, err := client.NodeUnprepareResources(ctx, &drapbv1.NodeUnprepareResourcesRequest{Claims: []*drapbv1.Claim{claim}})
+	if err != nil {
+		t.Fatalf("error unpreparing claim: %v", err)
+	}
+	if result := unprepared.Claims[claim.Uid]; result == nil || result.Error != "" {
+		t.Fatalf("claim not unprepared: %v", result)
+	}
+	if matches, _ := filepath.Glob(cdiSpecs); len(matches) != 0 {
+		t.Errorf("expected the CDI spec file of the claim to be removed, got %v", matches)
+	}
+	if _, err := os.Stat(claimConfig); !os.IsNotExist(err) {
+		t.Errorf("expected the claim config file to be removed: %v", err)
+	}
+}
+
+// newClaim builds a claim allocated a single device with a device config,
+// so that a claim config file is written for it
+func newClaim(t *testing.T, uid, nodeName, device string) *drapbv1.Claim {
+	spec := &fakecrd.FakeClaimParametersSpec{
+		Config: &fakecrd.FakeDeviceConfig{Mode: fakecrd.FakeDeviceModeCompute},
+	}
+	fakecrd.SetDefaultsFakeClaimParametersSpec(spec)
+	vendorClaimParameters, err := vendorparameters.EncodeClaimParameters(spec)
+	if err != nil {
+		t.Fatalf("error encoding vendor claim parameters: %v", err)
+	}
+	vendorRequestParameters, err := vendorparameters.EncodeRequestParameters(fakecrd.DefaultRequestName)
+	if err != nil {
+		t.Fatalf("error encoding vendor request parameters: %v", err)
+	}
+
+	return &drapbv1.Claim{
+		Namespace: "default",
+		Uid:       uid,
+		Name:      "claim",
+		StructuredResourceHandle: []*resourceapi.StructuredResourceHandle{{
+			VendorClaimParameters: runtime.RawExtension{Raw: vendorClaimParameters},
+			NodeName:              nodeName,
+			Results: []resourceapi.DriverAllocationResult{{
+				VendorRequestParameters: runtime.RawExtension{Raw: vendorRequestParameters},
+				AllocationResultModel: resourceapi.AllocationResultModel{
+					NamedResources: &resourceapi.NamedResourcesAllocationResult{Name: device},
+				},
+			}},
+		}},
+	}
+}
@@ -0,0 +1,313 @@
+package kubeletplugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+)
+
+var (
+	// errDeviceUnhealthy is returned when an allocated device cannot be used
+	errDeviceUnhealthy = errors.New("device is unhealthy")
+	// errSplitCapacityExceeded is returned when a device cannot be split into
+	// the requested number of partitions
+	errSplitCapacityExceeded = errors.New("split exceeds device capacity")
+	// errUnsupportedConfig is returned when the devices cannot be configured
+	// as requested in the claim parameters
+	errUnsupportedConfig = errors.New("unsupported device config")
+)
+
+type AllocatableDevices map[string]*AllocatableDeviceInfo
+type PreparedClaims map[string]*PreparedDevices
+
+// AllocatedDevice is a device allocated to a claim for one of the requests
+// of its FakeClaimParameters
+type AllocatedDevice struct {
+	// name is the name of the device in the resource model
+	name    string
+	request string
+	split   int
+}
+
+type PreparedDevices struct {
+	// Devices are the devices of all types handed to the containers
+	Devices []*DeviceInfo
+	// Config is passed to the containers using the devices, if set
+	Config *fakecrd.FakeDeviceConfig
+	// Admin is set for admin access claims, which prepare no devices
+	Admin *PreparedAdmin
+}
+
+type AllocatableDeviceInfo struct {
+	*DeviceInfo
+	unhealthy bool
+}
+
+type DeviceState struct {
+	sync.Mutex
+	cdi         *CDIHandler
+	allocatable AllocatableDevices
+	// names maps the names of the devices in the resource model to their
+	// UUIDs
+	names    map[string]string
+	prepared PreparedClaims
+}
+
+// NewDeviceState emulates the devices of the node and creates the CDI spec
+// file common to all claims
+func NewDeviceState(ctx context.Context, opts Options) (*DeviceState, error) {
+	logger := klog.FromContext(ctx)
+	if err := opts.complete(); err != nil {
+		return nil, err
+	}
+	logger.V(2).Info("Enumerating all available devices")
+	seed := inventorySeed(opts.DriverName, opts.NodeName)
+	allocatable, err := enumerateAllPossibleDevices(ctx, seed, opts.DeviceTypes)
+	if err != nil {
+		return nil, fmt.Errorf("error enumerating all possible devices: %w", err)
+	}
+	for _, uuid := range opts.UnhealthyDevices {
+		device, ok := allocatable[uuid]
+		if !ok {
+			logger.Info("Ignoring unknown device marked as unhealthy", "deviceUID", uuid)
+			continue
+		}
+		logger.Info("Emulating unhealthy device", "deviceUID", uuid)
+		device.unhealthy = true
+	}
+
+	cdi, err := NewCDIHandler(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create CDI handler: %w", err)
+	}
+
+	logger.V(4).Info("Creating CDI spec file for common device")
+	if err = cdi.CreateCommonSpecFile(ctx); err != nil {
+		return nil, fmt.Errorf("unable to create CDI spec file for common edits: %w", err)
+	}
+
+	state := &DeviceState{
+		cdi:         cdi,
+		allocatable: allocatable,
+		names:       make(map[string]string, len(allocatable)),
+		prepared:    make(PreparedClaims),
+	}
+	for uuid := range allocatable {
+		state.names[deviceName(uuid)] = uuid
+	}
+
+	return state, nil
+}
+
+func (s *DeviceState) Prepare(ctx context.Context, claimUID string, devices []AllocatedDevice, config *fakecrd.FakeDeviceConfig) ([]string, error) {
+	logger := klog.FromContext(ctx).WithValues(
+		"resourceClaimUID", claimUID,
+	)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(4).Info("Prepare CDI spec file for claim")
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.prepared[claimUID] != nil {
+		logger.V(2).Info("Returning already prepared devices for claim")
+		return s.cdi.GetClaimDevices(claimUID, s.prepared[claimUID]), nil
+	}
+
+	prepared := &PreparedDevices{}
+
+	logger.V(4).Info("Preparing devices")
+	preparedDevices, err := s.prepareDevices(ctx, devices)
+	if err != nil {
+		return nil, fmt.Errorf("allocation failed: %w", err)
+	}
+	prepared.Devices = preparedDevices
+
+	if config != nil {
+		logger.V(4).Info("Checking device config")
+		if err := checkConfigSupported(config, preparedDevices); err != nil {
+			return nil, fmt.Errorf("configuration failed: %w", err)
+		}
+		prepared.Config = config
+	}
+
+	logger.V(4).Info("Creating CDI spec file for claim")
+	if err := s.cdi.CreateClaimSpecFile(ctx, claimUID, prepared); err != nil {
+		return nil, fmt.Errorf("unable to create CDI spec file for claim: %w", err)
+	}
+	s.prepared[claimUID] = prepared
+	s.refreshAdminInventories(ctx)
+	logger.V(4).Info("Getting list of prepared CDI devices")
+	return s.cdi.GetClaimDevices(claimUID, s.prepared[claimUID]), nil
+}
+
+func (s *DeviceState) Unprepare(ctx context.Context, claimUID string) error {
+	logger := klog.FromContext(ctx).WithValues("resourceClaimUID", claimUID)
+	logger.V(4).Info("Unprepare CDI spec file for claim")
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.prepared[claimUID] == nil {
+		return nil
+	}
+
+	logger.V(4).Info("Unpreparing devices")
+	if err := s.unprepareDevices(ctx, s.prepared[claimUID].Devices); err != nil {
+		return fmt.Errorf("unprepare failed: %w", err)
+	}
+
+	logger.V(4).Info("Delete CDI spec file for claim")
+	if err := s.cdi.DeleteClaimSpecFile(claimUID); err != nil {
+		return fmt.Errorf("unable to delete CDI spec file for claim: %w", err)
+	}
+
+	delete(s.prepared, claimUID)
+	s.refreshAdminInventories(ctx)
+	return nil
+}
+
+// prepareDevices has the type of each allocated device prepare it, which
+// splits it into partitions if requested.
+func (s *DeviceState) prepareDevices(ctx context.Context, devices []AllocatedDevice) ([]*DeviceInfo, error) {
+	logger := klog.FromContext(ctx)
+	var prepared []*DeviceInfo
+
+	for _, allocated := range devices {
+		uuid, ok := s.names[allocated.name]
+		if !ok {
+			return nil, fmt.Errorf("requested device does not exist: %q", allocated.name)
+		}
+		device := s.allocatable[uuid]
+		if device.unhealthy {
+			return nil, fmt.Errorf("%w: %s", errDeviceUnhealthy, uuid)
+		}
+		deviceType, err := lookupDeviceType(device.deviceType)
+		if err != nil {
+			return nil, err
+		}
+
+		split := allocated.split
+		if maxSplit, ok := fakecrd.MaxSplit(device.model); ok && split > maxSplit {
+			return nil, fmt.Errorf("%w: %s device %s supports at most %d partitions, %d requested by %s", errSplitCapacityExceeded, device.model, uuid, maxSplit, split, allocated.request)
+		}
+		logger.Info("Preparing device", "deviceType", device.deviceType, "deviceUID", uuid, "request", allocated.request, "split", split)
+		devices, err := deviceType.Prepare(ctx, device.DeviceInfo, split)
+		if err != nil {
+			return nil, fmt.Errorf("error preparing %s device %s: %w", device.deviceType, uuid, err)
+		}
+		prepared = append(prepared, devices...)
+	}
+	return prepared, nil
+}
+
+// checkConfigSupported checks that the prepared devices can be configured as
+// requested. The config is validated against the models allocated on this
+// node, which are only known after allocation.
+func checkConfigSupported(config *fakecrd.FakeDeviceConfig, devices []*DeviceInfo) error {
+	models := sets.New[string]()
+	for _, device := range devices {
+		models.Insert(device.model)
+		if config.Mode == fakecrd.FakeDeviceModeDebug && device.parent != "" {
+			return fmt.Errorf("%w: %s mode requires whole devices, %s is a partition of %s", errUnsupportedConfig, config.Mode, device.uuid, device.parent)
+		}
+	}
+	if errs := fakecrd.ValidateFakeDeviceConfig(config, sets.List(models), field.NewPath("config")); len(errs) > 0 {
+		return fmt.Errorf("%w: %w", errUnsupportedConfig, errs.ToAggregate())
+	}
+	return nil
+}
+
+// unprepareDevices has each type release its prepared devices.
+func (s *DeviceState) unprepareDevices(ctx context.Context, devices []*DeviceInfo) error {
+	byType := map[string][]*DeviceInfo{}
+	for _, device := range devices {
+		byType[device.deviceType] = append(byType[device.deviceType], device)
+	}
+	for name, devices := range byType {
+		deviceType, err := lookupDeviceType(name)
+		if err != nil {
+			return err
+		}
+		if err := deviceType.Unprepare(ctx, devices); err != nil {
+			return fmt.Errorf("error unpreparing %s devices: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (s *DeviceState) getResourceModelFromAllocatableDevices() resourceapi.ResourceModel {
+	return resourceModelFromDevices(s.allocatable)
+}
+
+// resourceModelFromDevices returns the resource model publishing the devices
+func resourceModelFromDevices(allocatable AllocatableDevices) resourceapi.ResourceModel {
+	var instances []resourceapi.NamedResourcesInstance
+	for _, device := range allocatable {
+		instance := resourceapi.NamedResourcesInstance{
+			Name: deviceName(device.uuid),
+			Attributes: []resourceapi.NamedResourcesAttribute{
+				stringAttribute("uuid", device.uuid),
+				stringAttribute("type", device.deviceType),
+				stringAttribute("model", device.model),
+			},
+		}
+		if deviceType, err := lookupDeviceType(device.deviceType); err == nil {
+			instance.Attributes = append(instance.Attributes, deviceType.Attributes(device.DeviceInfo)...)
+		}
+		instances = append(instances, instance)
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].Name < instances[j].Name
+	})
+
+	return resourceapi.ResourceModel{
+		NamedResources: &resourceapi.NamedResourcesResources{Instances: instances},
+	}
+}
+
+// inventorySeed returns the seed of the devices emulated on the node. Drivers
+// other than the default one get their own inventory, so that the devices of
+// several drivers on the same node do not collide.
+func inventorySeed(driverName, nodeName string) string {
+	if driverName == DefaultDriverName {
+		return nodeName
+	}
+	return driverName + "/" + nodeName
+}
+
+// enumerateAllPossibleDevices enumerates the devices of the given types
+// emulated on the node
+func enumerateAllPossibleDevices(ctx context.Context, seed string, types []string) (AllocatableDevices, error) {
+	allDevices := make(AllocatableDevices)
+	for _, name := range types {
+		deviceType, err := lookupDeviceType(name)
+		if err != nil {
+			return nil, err
+		}
+		devices, err := deviceType.Enumerate(ctx, seed)
+		if err != nil {
+			return nil, fmt.Errorf("error enumerating %s devices: %w", name, err)
+		}
+		for _, device := range devices {
+			allDevices[device.uuid] = &AllocatableDeviceInfo{DeviceInfo: device}
+		}
+	}
+	return allDevices, nil
+}
+
+// deviceName returns the name of a device in the resource model, which must
+// be a DNS label
+func deviceName(uuid string) string {
+	return strings.ToLower(uuid)
+}
@@ -0,0 +1,379 @@
+package kubeletplugin
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+	"github.com/toVersus/fake-dra-driver/pkg/deviceuuid"
+	"github.com/toVersus/fake-dra-driver/pkg/fakedevice"
+)
+
+const (
+	telemetryNamespace = "fake_dra_device"
+
+	// usageReportTTL is how long the usage reported by a workload is
+	// exported after it was last written, synthetic values are exported
+	// once it expires
+	usageReportTTL = time.Minute
+
+	// ambientTemperature is the temperature of an idle device in celsius
+	ambientTemperature = 30.
+	// loadTemperature is how much warmer a fully utilized device runs
+	loadTemperature = 55.
+	// smoothing is the share of the distance to their target utilization
+	// and temperature move by at each sample, so that the values form time
+	// series rather than noise
+	smoothing = 0.3
+	// noise is the standard deviation of the synthetic utilization
+	noise = 0.05
+)
+
+var telemetryLabels = []string{"driver", "node", "device", "parent", "type", "model", "claim", "namespace", "pod"}
+
+var (
+	utilizationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(telemetryNamespace, "", "utilization_ratio"),
+		"Share of the device in use, between 0 and 1.",
+		telemetryLabels, nil,
+	)
+	memoryUsedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(telemetryNamespace, "", "memory_used_bytes"),
+		"Memory of the device in use, for devices having memory.",
+		telemetryLabels, nil,
+	)
+	memoryTotalDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(telemetryNamespace, "", "memory_total_bytes"),
+		"Memory of the device, or its share of the memory of the parent for partitions.",
+		telemetryLabels, nil,
+	)
+	temperatureDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(telemetryNamespace, "", "temperature_celsius"),
+		"Temperature of the device.",
+		telemetryLabels, nil,
+	)
+	powerDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(telemetryNamespace, "", "power_usage_watts"),
+		"Power drawn by the device, or its share of the power of the parent for partitions.",
+		telemetryLabels, nil,
+	)
+)
+
+// modeUtilization is the synthetic utilization of the devices of a claim
+// operated in each mode
+var modeUtilization = map[string]float64{
+	fakecrd.FakeDeviceModeCompute:  0.7,
+	fakecrd.FakeDeviceModeGraphics: 0.5,
+	fakecrd.FakeDeviceModeDebug:    0.2,
+}
+
+// clockProfileLoad scales the utilization and the heat of the devices of a
+// claim running with each clock profile
+var clockProfileLoad = map[string]float64{
+	fakecrd.FakeClockProfileBase:      1,
+	fakecrd.FakeClockProfileBoost:     1.2,
+	fakecrd.FakeClockProfilePowerSave: 0.6,
+}
+
+// powerRange is the power in watts drawn by an idle and by a fully utilized
+// device
+type powerRange struct {
+	idle, max float64
+}
+
+// deviceTypePower is the power drawn by the devices of each type whose
+// model has no power limit range
+var deviceTypePower = map[string]powerRange{
+	fakecrd.FakeDeviceType:        {idle: 50, max: 300},
+	fakecrd.NICDeviceType:         {idle: 8, max: 25},
+	fakecrd.AcceleratorDeviceType: {idle: 60, max: 400},
+}
+
+// telemetryClaim labels the telemetry of the devices of a claim
+type telemetryClaim struct {
+	namespace string
+	name      string
+	// pods are the names of the Pods the claim is reserved for, unknown
+	// without a client
+	pods []string
+}
+
+// telemetryDevice is a device emulated on the node, or a partition of one,
+// together with the claim holding it if any
+type telemetryDevice struct {
+	*DeviceInfo
+	claimUID string
+	config   *fakecrd.FakeDeviceConfig
+}
+
+// deviceSample is the telemetry of a device at the last sample
+type deviceSample struct {
+	device      telemetryDevice
+	utilization float64
+	memoryUsed  float64
+	memoryTotal float64
+	temperature float64
+	power       float64
+}
+
+// telemetry samples synthetic utilization, memory, temperature and power of
+// the devices of the node and exports them as Prometheus metrics. Devices
+// held by a claim are loaded according to its device config unless their
+// workload reports its usage, idle devices only draw their idle power.
+type telemetry struct {
+	sync.Mutex
+	state      *DeviceState
+	driverName string
+	nodeName   string
+	interval   time.Duration
+	rand       *rand.Rand
+
+	claims map[string]*telemetryClaim
+	// utilization and temperature hold the values of the last sample of
+	// each device, which the next one moves from
+	utilization map[string]float64
+	temperature map[string]float64
+	samples     []*deviceSample
+}
+
+var _ prometheus.Collector = &telemetry{}
+
+func newTelemetry(state *DeviceState, opts Options) *telemetry {
+	return &telemetry{
+		state:       state,
+		driverName:  opts.DriverName,
+		nodeName:    opts.NodeName,
+		interval:    opts.TelemetryInterval,
+		rand:        rand.New(rand.NewSource(deviceuuid.Hash(inventorySeed(opts.DriverName, opts.NodeName)))),
+		claims:      map[string]*telemetryClaim{},
+		utilization: map[string]float64{},
+		temperature: map[string]float64{},
+	}
+}
+
+// run samples the telemetry until the context is done
+func (t *telemetry) run(ctx context.Context) {
+	klog.FromContext(ctx).Info("Sampling device telemetry", "interval", t.interval)
+	wait.UntilWithContext(ctx, t.sample, t.interval)
+}
+
+// setClaim labels the telemetry of the devices of a claim, keeping the Pods
+// of a claim already known
+func (t *telemetry) setClaim(claimUID, namespace, name string) {
+	t.Lock()
+	defer t.Unlock()
+	if _, ok := t.claims[claimUID]; ok {
+		return
+	}
+	t.claims[claimUID] = &telemetryClaim{namespace: namespace, name: name}
+}
+
+// setPods labels the telemetry of the devices of a claim with the Pods it is
+// reserved for, unless the claim was unprepared in the meantime
+func (t *telemetry) setPods(claimUID string, pods []string) {
+	t.Lock()
+	defer t.Unlock()
+	if claim, ok := t.claims[claimUID]; ok {
+		claim.pods = pods
+	}
+}
+
+// removeClaim forgets an unprepared claim
+func (t *telemetry) removeClaim(claimUID string) {
+	t.Lock()
+	defer t.Unlock()
+	delete(t.claims, claimUID)
+}
+
+// sample computes the telemetry of every device from the previous sample
+func (t *telemetry) sample(ctx context.Context) {
+	logger := klog.FromContext(ctx)
+	devices := t.state.telemetryDevices()
+
+	t.Lock()
+	defer t.Unlock()
+
+	utilization := make(map[string]float64, len(devices))
+	temperature := make(map[string]float64, len(devices))
+	samples := make([]*deviceSample, 0, len(devices))
+	for _, device := range devices {
+		sample := &deviceSample{device: device}
+		partitions := float64(max(device.partitions, 1))
+
+		usage, err := t.state.cdi.readUsage(device.claimUID, device.uuid)
+		if err != nil {
+			logger.V(4).Info("Ignoring usage reported for device", "deviceUID", device.uuid, "resourceClaimUID", device.claimUID, "err", err)
+		}
+		if usage != nil {
+			sample.utilization = clamp(usage.Utilization)
+		} else {
+			sample.utilization = t.syntheticUtilization(device)
+		}
+		utilization[device.uuid] = sample.utilization
+
+		load := 1.
+		if device.config != nil {
+			load = clockProfileLoad[device.config.ClockProfile]
+		}
+		target := ambientTemperature + loadTemperature*sample.utilization*load
+		previous, ok := t.temperature[device.uuid]
+		if !ok {
+			previous = ambientTemperature
+		}
+		sample.temperature = previous + smoothing*(target-previous)
+		temperature[device.uuid] = sample.temperature
+
+		if memory, ok := fakecrd.AcceleratorMemory(device.model); ok {
+			sample.memoryTotal = float64(memory.Value()) / partitions
+			sample.memoryUsed = sample.memoryTotal * (0.05 + 0.85*sample.utilization)
+			if usage != nil && usage.MemoryUsedBytes != nil {
+				sample.memoryUsed = math.Min(float64(*usage.MemoryUsedBytes), sample.memoryTotal)
+			}
+		}
+
+		power := devicePower(device.deviceType, device.model)
+		sample.power = power.idle + (power.max-power.idle)*sample.utilization
+		if device.config != nil && device.config.PowerLimitWatts != nil {
+			sample.power = math.Min(sample.power, float64(*device.config.PowerLimitWatts))
+		}
+		sample.power /= partitions
+
+		samples = append(samples, sample)
+	}
+	t.utilization = utilization
+	t.temperature = temperature
+	t.samples = samples
+}
+
+// syntheticUtilization moves the utilization of a device towards the load
+// of the claim holding it, with some noise
+func (t *telemetry) syntheticUtilization(device telemetryDevice) float64 {
+	target := 0.
+	if device.claimUID != "" {
+		target = modeUtilization[fakecrd.FakeDeviceModeCompute]
+		if device.config != nil {
+			target = modeUtilization[device.config.Mode] * clockProfileLoad[device.config.ClockProfile]
+		}
+		target += t.rand.NormFloat64() * noise
+	}
+	previous := t.utilization[device.uuid]
+	return clamp(previous + smoothing*(target-previous))
+}
+
+// devicePower returns the power drawn by a device of the given type and
+// model. The power limit range of a model bounds it when known.
+func devicePower(deviceType, model string) powerRange {
+	power := deviceTypePower[deviceType]
+	if _, maxWatts, ok := fakecrd.PowerLimitRange(model); ok {
+		power = powerRange{idle: 0.2 * float64(maxWatts), max: float64(maxWatts)}
+	}
+	return power
+}
+
+func clamp(ratio float64) float64 {
+	return math.Max(0, math.Min(1, ratio))
+}
+
+func (t *telemetry) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{utilizationDesc, memoryUsedDesc, memoryTotalDesc, temperatureDesc, powerDesc} {
+		ch <- desc
+	}
+}
+
+// Collect exports the last sample. The telemetry of a claim shared by
+// several Pods is exported for each of them.
+func (t *telemetry) Collect(ch chan<- prometheus.Metric) {
+	t.Lock()
+	defer t.Unlock()
+
+	for _, sample := range t.samples {
+		device := sample.device
+		claim := t.claims[device.claimUID]
+		if claim == nil {
+			claim = &telemetryClaim{}
+		}
+		pods := claim.pods
+		if len(pods) == 0 {
+			pods = []string{""}
+		}
+		for _, pod := range pods {
+			labels := []string{t.driverName, t.nodeName, device.uuid, device.parent, device.deviceType, device.model, claim.name, claim.namespace, pod}
+			ch <- prometheus.MustNewConstMetric(utilizationDesc, prometheus.GaugeValue, sample.utilization, labels...)
+			ch <- prometheus.MustNewConstMetric(temperatureDesc, prometheus.GaugeValue, sample.temperature, labels...)
+			ch <- prometheus.MustNewConstMetric(powerDesc, prometheus.GaugeValue, sample.power, labels...)
+			if sample.memoryTotal > 0 {
+				ch <- prometheus.MustNewConstMetric(memoryUsedDesc, prometheus.GaugeValue, sample.memoryUsed, labels...)
+				ch <- prometheus.MustNewConstMetric(memoryTotalDesc, prometheus.GaugeValue, sample.memoryTotal, labels...)
+			}
+		}
+	}
+}
+
+// telemetryDevices returns the devices prepared for claims together with the
+// devices of the node none of them holds. A device split into partitions is
+// only represented by the partitions prepared.
+func (s *DeviceState) telemetryDevices() []telemetryDevice {
+	s.Lock()
+	defer s.Unlock()
+
+	var devices []telemetryDevice
+	held := map[string]bool{}
+	for claimUID, prepared := range s.prepared {
+		for _, device := range prepared.Devices {
+			devices = append(devices, telemetryDevice{DeviceInfo: device, claimUID: claimUID, config: prepared.Config})
+			held[device.uuid] = true
+			if device.parent != "" {
+				held[device.parent] = true
+			}
+		}
+	}
+	for uuid, device := range s.allocatable {
+		if !held[uuid] {
+			devices = append(devices, telemetryDevice{DeviceInfo: device.DeviceInfo})
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].uuid < devices[j].uuid
+	})
+	return devices
+}
+
+// readUsage returns the usage of a device reported in the usage directory of
+// the claim holding it, nil if none was reported recently
+func (cdi *CDIHandler) readUsage(claimUID, uuid string) (*fakedevice.Usage, error) {
+	if claimUID == "" || !cdi.usageDirs {
+		return nil, nil
+	}
+	path := filepath.Join(cdi.usageDir(claimUID), fakedevice.UsageFileName(uuid))
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if time.Since(info.ModTime()) > usageReportTTL {
+		return nil, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	usage := &fakedevice.Usage{}
+	if err := json.Unmarshal(raw, usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
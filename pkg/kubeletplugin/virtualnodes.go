@@ -0,0 +1,362 @@
+package kubeletplugin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	coreclientset "k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// virtualNodeLabel marks the ResourceSlices published for virtual nodes,
+	// those published by kubelet for the plugin are left alone
+	virtualNodeLabel = DriverAPIGroup + "/virtual-node"
+
+	// resourceSliceNodeIndex is the name of the index of the ResourceSlices
+	// of the driver keyed on their node
+	resourceSliceNodeIndex = "node"
+)
+
+// VirtualNodePublisher publishes the devices emulated on the nodes matching a
+// selector as ResourceSlices, in the shape the kubelet publishes them for the
+// plugin. The devices are enumerated the same way as by the plugin, so a node
+// gets the same devices whichever way they are published.
+type VirtualNodePublisher struct {
+	clientset   coreclientset.Interface
+	driverName  string
+	deviceTypes []string
+	selector    labels.Selector
+
+	nodeInformer          cache.SharedIndexInformer
+	nodeLister            corelisters.NodeLister
+	resourceSliceInformer cache.SharedIndexInformer
+	informerFactory       informers.SharedInformerFactory
+
+	// queue holds the names of nodes
+	queue workqueue.RateLimitingInterface
+}
+
+// VirtualNodePublisherOptions configures a VirtualNodePublisher
+type VirtualNodePublisherOptions struct {
+	// DriverName is the driver the ResourceSlices are published for,
+	// DefaultDriverName if empty
+	DriverName string
+	// DeviceTypes are the types of devices emulated on each node, all
+	// registered types if empty
+	DeviceTypes []string
+	// NodeSelector selects the virtual nodes, all nodes if nil
+	NodeSelector labels.Selector
+}
+
+func NewVirtualNodePublisher(clientset coreclientset.Interface, opts VirtualNodePublisherOptions) (*VirtualNodePublisher, error) {
+	driverName, deviceTypes, selector := opts.DriverName, opts.DeviceTypes, opts.NodeSelector
+	if driverName == "" {
+		driverName = DefaultDriverName
+	}
+	if len(deviceTypes) == 0 {
+		deviceTypes = RegisteredDeviceTypes()
+	}
+	if selector == nil {
+		selector = labels.Everything()
+	}
+	for _, name := range deviceTypes {
+		if _, err := lookupDeviceType(name); err != nil {
+			return nil, err
+		}
+	}
+
+	informerFactory := informers.NewSharedInformerFactory(clientset, 0)
+	nodes := informerFactory.Core().V1().Nodes()
+
+	p := &VirtualNodePublisher{
+		clientset:       clientset,
+		driverName:      driverName,
+		deviceTypes:     deviceTypes,
+		selector:        selector,
+		nodeInformer:    nodes.Informer(),
+		nodeLister:      nodes.Lister(),
+		informerFactory: informerFactory,
+		// Only the slices published by the command are watched
+		resourceSliceInformer: informers.NewSharedInformerFactoryWithOptions(clientset, 0,
+			informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+				options.LabelSelector = virtualNodeLabel
+			}),
+		).Resource().V1alpha2().ResourceSlices().Informer(),
+		queue: workqueue.NewRateLimitingQueueWithConfig(
+			workqueue.DefaultControllerRateLimiter(),
+			workqueue.RateLimitingQueueConfig{Name: "virtualnodes"},
+		),
+	}
+
+	err := p.resourceSliceInformer.AddIndexers(cache.Indexers{resourceSliceNodeIndex: p.resourceSliceNodeIndexFunc})
+	if err != nil {
+		return nil, fmt.Errorf("error adding ResourceSlice indexer: %w", err)
+	}
+
+	// Label changes may select or unselect nodes, other changes do not
+	// affect the devices
+	_, err = p.nodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: p.enqueueNode,
+		UpdateFunc: func(oldObj any, newObj any) {
+			oldNode, ok := oldObj.(*corev1.Node)
+			newNode, ok2 := newObj.(*corev1.Node)
+			if ok && ok2 && apiequality.Semantic.DeepEqual(oldNode.Labels, newNode.Labels) {
+				return
+			}
+			p.enqueueNode(newObj)
+		},
+		DeleteFunc: p.enqueueNode,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error adding Node event handler: %w", err)
+	}
+
+	// Manual edits and deletions of published slices are corrected, and
+	// slices of nodes which are gone are cleaned up
+	_, err = p.resourceSliceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: p.enqueueSliceNode,
+		UpdateFunc: func(oldObj any, newObj any) {
+			p.enqueueSliceNode(newObj)
+		},
+		DeleteFunc: p.enqueueSliceNode,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error adding ResourceSlice event handler: %w", err)
+	}
+
+	return p, nil
+}
+
+// Run starts the informers and workers and blocks until the context is done.
+func (p *VirtualNodePublisher) Run(ctx context.Context, workers int) error {
+	logger := klog.FromContext(ctx)
+	defer utilruntime.HandleCrash()
+	defer p.queue.ShutDown()
+
+	p.informerFactory.Start(ctx.Done())
+	defer p.informerFactory.Shutdown()
+	go p.resourceSliceInformer.Run(ctx.Done())
+
+	logger.V(2).Info("Waiting for informer caches to sync")
+	if !cache.WaitForNamedCacheSync("virtualnodes", ctx.Done(),
+		p.nodeInformer.HasSynced,
+		p.resourceSliceInformer.HasSynced,
+	) {
+		return fmt.Errorf("error waiting for informer caches to sync")
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, p.runWorker, time.Second)
+	}
+
+	<-ctx.Done()
+	logger.Info("Shutting down virtual node publisher")
+	return nil
+}
+
+func (p *VirtualNodePublisher) enqueueNode(obj any) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	p.queue.Add(key)
+}
+
+func (p *VirtualNodePublisher) enqueueSliceNode(obj any) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	keys, err := p.resourceSliceNodeIndexFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, key := range keys {
+		p.queue.Add(key)
+	}
+}
+
+func (p *VirtualNodePublisher) runWorker(ctx context.Context) {
+	for p.processNextWorkItem(ctx) {
+	}
+}
+
+func (p *VirtualNodePublisher) processNextWorkItem(ctx context.Context) bool {
+	key, quit := p.queue.Get()
+	if quit {
+		return false
+	}
+	defer p.queue.Done(key)
+
+	logger := klog.FromContext(ctx).WithValues("node", key)
+	ctx = klog.NewContext(ctx, logger)
+
+	if err := p.sync(ctx, key.(string)); err != nil {
+		logger.Error(err, "Error publishing devices of virtual node, requeuing", "retries", p.queue.NumRequeues(key))
+		p.queue.AddRateLimited(key)
+		return true
+	}
+
+	p.queue.Forget(key)
+	return true
+}
+
+// sync publishes the devices of the named node if it is selected and
+// deletes the slices published for it otherwise.
+func (p *VirtualNodePublisher) sync(ctx context.Context, nodeName string) error {
+	logger := klog.FromContext(ctx)
+
+	node, err := p.nodeLister.Get(nodeName)
+	if apierrors.IsNotFound(err) {
+		logger.V(4).Info("Node no longer exists")
+		return p.deleteResourceSlices(ctx, nodeName, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("error getting Node from cache: %w", err)
+	}
+	if !p.selector.Matches(labels.Set(node.Labels)) {
+		return p.deleteResourceSlices(ctx, nodeName, nil)
+	}
+
+	allocatable, err := enumerateAllPossibleDevices(ctx, inventorySeed(p.driverName, node.Name), p.deviceTypes)
+	if err != nil {
+		return fmt.Errorf("error enumerating devices of node: %w", err)
+	}
+
+	resourceSlice := &resourceapi.ResourceSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: virtualNodeResourceSliceName(node.Name, p.driverName),
+			Labels: map[string]string{
+				virtualNodeLabel: "true",
+			},
+			// Slices of deleted nodes are garbage collected even if the
+			// command is not running
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: corev1.SchemeGroupVersion.String(),
+					Kind:       "Node",
+					Name:       node.Name,
+					UID:        node.UID,
+				},
+			},
+		},
+		NodeName:      node.Name,
+		DriverName:    p.driverName,
+		ResourceModel: resourceModelFromDevices(allocatable),
+	}
+	if err := p.publishResourceSlice(ctx, resourceSlice); err != nil {
+		return err
+	}
+	return p.deleteResourceSlices(ctx, nodeName, resourceSlice)
+}
+
+// publishResourceSlice creates the slice or updates it when the cached object
+// has drifted from it.
+func (p *VirtualNodePublisher) publishResourceSlice(ctx context.Context, resourceSlice *resourceapi.ResourceSlice) error {
+	logger := klog.FromContext(ctx).WithValues("resourceSlice", klog.KObj(resourceSlice))
+
+	obj, exists, err := p.resourceSliceInformer.GetStore().GetByKey(resourceSlice.Name)
+	if err != nil {
+		return fmt.Errorf("error getting ResourceSlice from cache: %w", err)
+	}
+	if !exists {
+		_, err := p.clientset.ResourceV1alpha2().ResourceSlices().Create(ctx, resourceSlice, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			// The cache has not caught up yet, the slice is checked again
+			// once it has
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error creating ResourceSlice: %w", err)
+		}
+		logger.V(2).Info("Published devices of virtual node", "devices", len(resourceSlice.NamedResources.Instances))
+		return nil
+	}
+
+	current := obj.(*resourceapi.ResourceSlice)
+	if apiequality.Semantic.DeepEqual(current.Labels, resourceSlice.Labels) &&
+		apiequality.Semantic.DeepEqual(current.OwnerReferences, resourceSlice.OwnerReferences) &&
+		current.NodeName == resourceSlice.NodeName &&
+		current.DriverName == resourceSlice.DriverName &&
+		apiequality.Semantic.DeepEqual(current.ResourceModel, resourceSlice.ResourceModel) {
+		logger.V(4).Info("ResourceSlice is up to date")
+		return nil
+	}
+
+	updated := current.DeepCopy()
+	updated.Labels = resourceSlice.Labels
+	updated.OwnerReferences = resourceSlice.OwnerReferences
+	updated.NodeName = resourceSlice.NodeName
+	updated.DriverName = resourceSlice.DriverName
+	updated.ResourceModel = resourceSlice.ResourceModel
+	if _, err := p.clientset.ResourceV1alpha2().ResourceSlices().Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating ResourceSlice: %w", err)
+	}
+	logger.V(2).Info("Updated devices of virtual node", "devices", len(resourceSlice.NamedResources.Instances))
+	return nil
+}
+
+// deleteResourceSlices deletes the slices published for the named node except
+// keep, which may be nil.
+func (p *VirtualNodePublisher) deleteResourceSlices(ctx context.Context, nodeName string, keep *resourceapi.ResourceSlice) error {
+	logger := klog.FromContext(ctx)
+
+	objs, err := p.resourceSliceInformer.GetIndexer().ByIndex(resourceSliceNodeIndex, nodeName)
+	if err != nil {
+		return fmt.Errorf("error getting published ResourceSlices from cache: %w", err)
+	}
+	for _, obj := range objs {
+		item := obj.(*resourceapi.ResourceSlice)
+		if keep != nil && item.Name == keep.Name {
+			continue
+		}
+		err := p.clientset.ResourceV1alpha2().ResourceSlices().Delete(ctx, item.Name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: ptr.To(item.UID)},
+		})
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+			return fmt.Errorf("error deleting ResourceSlice: %w", err)
+		}
+		logger.V(2).Info("Deleted ResourceSlice of virtual node", "resourceSlice", klog.KObj(item))
+	}
+	return nil
+}
+
+func (p *VirtualNodePublisher) resourceSliceNodeIndexFunc(obj any) ([]string, error) {
+	slice, ok := obj.(*resourceapi.ResourceSlice)
+	if !ok || slice.DriverName != p.driverName || slice.NodeName == "" {
+		return nil, nil
+	}
+	return []string{slice.NodeName}, nil
+}
+
+// virtualNodeResourceSliceName returns the name of the slice published for a
+// node, which is stable so that restarts pick up the slices published before.
+func virtualNodeResourceSliceName(nodeName, driverName string) string {
+	name := nodeName + "-" + driverName
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+	// Keep the name unique by replacing the overflowing part with a hash
+	sum := sha256.Sum256([]byte(name))
+	suffix := "-" + hex.EncodeToString(sum[:])[:10]
+	return name[:validation.DNS1123SubdomainMaxLength-len(suffix)] + suffix
+}
@@ -0,0 +1,135 @@
+// Package vendorparameters encodes the vendor parameters the controller puts
+// into ResourceClaimParameters and decodes them from the allocation results
+// of ResourceClaims, which is how the kubelet plugin and the quota accounting
+// learn about the FakeClaimParameters a claim was allocated for.
+package vendorparameters
+
+import (
+	"encoding/json"
+	"fmt"
+
+	resourceapi "k8s.io/api/resource/v1alpha2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fakev1alpha1 "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1alpha1"
+	fakecrd "github.com/toVersus/fake-dra-driver/api/3-shake.com/resource/fake/v1beta1"
+)
+
+// AllocatedDevice is a named resource allocated for a request of the claim
+// parameters
+type AllocatedDevice struct {
+	// Name is the name of the named resource instance
+	Name string
+	// Request is the defaulted request the device was allocated for
+	Request fakecrd.FakeRequest
+}
+
+// EncodeClaimParameters encodes a spec into vendor claim parameters. The
+// version is passed along so that the parameters can be told apart from those
+// generated from earlier versions.
+func EncodeClaimParameters(spec *fakecrd.FakeClaimParametersSpec) ([]byte, error) {
+	return json.Marshal(&fakecrd.VendorClaimParameters{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: fakecrd.SchemeGroupVersion.String(),
+			Kind:       fakecrd.FakeClaimParametersKind,
+		},
+		Spec: *spec,
+	})
+}
+
+// EncodeRequestParameters encodes the name of a request into vendor request
+// parameters. The allocation results only refer to the ResourceRequest they
+// satisfy, the name tells which request of the spec it was.
+func EncodeRequestParameters(request string) ([]byte, error) {
+	return json.Marshal(&fakecrd.VendorRequestParameters{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: fakecrd.SchemeGroupVersion.String(),
+			Kind:       fakecrd.FakeRequestKind,
+		},
+		Request: request,
+	})
+}
+
+// DecodeClaimParameters decodes the FakeClaimParametersSpec passed in vendor
+// claim parameters. Claims allocated before v1beta1 was introduced carry a
+// bare v1alpha1 spec without TypeMeta, which is converted to v1beta1.
+func DecodeClaimParameters(raw []byte) (*fakecrd.FakeClaimParametersSpec, error) {
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return nil, err
+	}
+
+	switch typeMeta.APIVersion {
+	case fakecrd.SchemeGroupVersion.String():
+		var params fakecrd.VendorClaimParameters
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, err
+		}
+		return &params.Spec, nil
+	case "":
+		var v1alpha1Spec fakev1alpha1.FakeClaimParametersSpec
+		if err := json.Unmarshal(raw, &v1alpha1Spec); err != nil {
+			return nil, err
+		}
+		var spec fakecrd.FakeClaimParametersSpec
+		fakev1alpha1.ConvertFakeClaimParametersSpecToHub(&v1alpha1Spec, &spec)
+		return &spec, nil
+	default:
+		return nil, fmt.Errorf("unsupported apiVersion %q", typeMeta.APIVersion)
+	}
+}
+
+// DecodeRequestParameters returns the name of the request an allocation
+// result belongs to. Claims allocated before named requests were introduced
+// carry no request parameters and belong to the default request.
+func DecodeRequestParameters(raw []byte) (string, error) {
+	if len(raw) == 0 {
+		return fakecrd.DefaultRequestName, nil
+	}
+
+	var params fakecrd.VendorRequestParameters
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return "", err
+	}
+	if params.APIVersion != fakecrd.SchemeGroupVersion.String() || params.Kind != fakecrd.FakeRequestKind {
+		return "", fmt.Errorf("unsupported request parameters %s, Kind=%s", params.APIVersion, params.Kind)
+	}
+	return params.Request, nil
+}
+
+// DecodeAllocation returns the defaulted claim parameters of a structured
+// resource handle together with the devices allocated for them.
+func DecodeAllocation(handle *resourceapi.StructuredResourceHandle) (*fakecrd.FakeClaimParametersSpec, []AllocatedDevice, error) {
+	spec, err := DecodeClaimParameters(handle.VendorClaimParameters.Raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error unmarshalling vendor claim parameters: %w", err)
+	}
+	// Parameters generated before defaulting was introduced may still omit
+	// fields, so the defaults are applied again here
+	fakecrd.SetDefaultsFakeClaimParametersSpec(spec)
+	requests := map[string]fakecrd.FakeRequest{}
+	for _, request := range spec.GetRequests() {
+		requests[request.Name] = request
+	}
+
+	devices := make([]AllocatedDevice, 0, len(handle.Results))
+	for _, result := range handle.Results {
+		requestName, err := DecodeRequestParameters(result.VendorRequestParameters.Raw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error unmarshalling vendor request parameters: %w", err)
+		}
+		request, ok := requests[requestName]
+		if !ok {
+			return nil, nil, fmt.Errorf("request %q not found in vendor claim parameters", requestName)
+		}
+		if result.NamedResources == nil {
+			return nil, nil, fmt.Errorf("allocation result of request %q is not a named resource", requestName)
+		}
+		devices = append(devices, AllocatedDevice{
+			Name:    result.NamedResources.Name,
+			Request: request,
+		})
+	}
+
+	return spec, devices, nil
+}